@@ -1,6 +1,9 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"log"
 	"os"
 	"strconv"
@@ -21,6 +24,14 @@ type Config struct {
 	RateLimit   RateLimitConfig
 	Library     LibraryConfig
 	Pagination  PaginationConfig
+	Scheduler   SchedulerConfig
+	SIP2        SIP2Config
+	Search      SearchConfig
+	Import      ImportConfig
+	Metadata    MetadataConfig
+	Storage     StorageConfig
+	Covers      CoverConfig
+	Digital     DigitalConfig
 	
 	LogLevel    string
 	LogFormat   string
@@ -60,10 +71,82 @@ type RateLimitConfig struct {
 }
 
 type LibraryConfig struct {
-	MaxLoanDays        int
-	MaxRenewals        int
-	OverdueFinePerDay  float64
-	MaxBooksPerMember  int
+	MaxLoanDays           int
+	MaxRenewals           int
+	OverdueFinePerDay     float64
+	MaxBooksPerMember     int
+	HoldPickupDays        int
+	DefaultPickupLocation string
+	DefaultBranchCode     string
+	DefaultBranchName     string
+	CallNumberScheme      string
+	AutoRenewEnabled      bool
+	AutoRenewDaysBefore   int
+	AutoLostAfterDays     int
+	DefaultReplacementFee float64
+	LostProcessingFee     float64
+	SerialPredictMonths   int
+	RoomOpenHour          int
+	RoomCloseHour         int
+	RoomBookingDaysAhead  int
+}
+
+type SIP2Config struct {
+	Enabled     bool
+	Port        int
+	Institution string
+	IdleTimeout time.Duration
+}
+
+type SearchConfig struct {
+	DefaultLanguage      string
+	SuggestionThreshold  float64
+	AutocompleteCacheTTL time.Duration
+}
+
+type ImportConfig struct {
+	BackgroundThreshold int
+	MaxRows             int
+	MaxFileSize         int64
+}
+
+type MetadataConfig struct {
+	Providers         []string
+	GoogleBooksAPIKey string
+	SRUURL            string
+	Timeout           time.Duration
+	CacheTTL          time.Duration
+}
+
+type StorageConfig struct {
+	Driver      string
+	LocalPath   string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+	S3PathStyle bool
+}
+
+type CoverConfig struct {
+	MaxUploadSize int64
+	CacheMaxAge   time.Duration
+}
+
+type DigitalConfig struct {
+	LoanDays      int
+	MaxCheckouts  int
+	HoldDays      int
+	LinkTTL       time.Duration
+	SigningKey    string
+	MaxUploadSize int64
+}
+
+type SchedulerConfig struct {
+	Enabled  bool
+	Interval time.Duration
 }
 
 type PaginationConfig struct {
@@ -112,10 +195,24 @@ func Load() *Config {
 		},
 		
 		Library: LibraryConfig{
-			MaxLoanDays:       getEnvAsInt("MAX_LOAN_DAYS", 14),
-			MaxRenewals:       getEnvAsInt("MAX_RENEWALS", 2),
-			OverdueFinePerDay: getEnvAsFloat("OVERDUE_FINE_PER_DAY", 1.00),
-			MaxBooksPerMember: getEnvAsInt("MAX_BOOKS_PER_MEMBER", 5),
+			MaxLoanDays:           getEnvAsInt("MAX_LOAN_DAYS", 14),
+			MaxRenewals:           getEnvAsInt("MAX_RENEWALS", 2),
+			OverdueFinePerDay:     getEnvAsFloat("OVERDUE_FINE_PER_DAY", 1.00),
+			MaxBooksPerMember:     getEnvAsInt("MAX_BOOKS_PER_MEMBER", 5),
+			HoldPickupDays:        getEnvAsInt("HOLD_PICKUP_DAYS", 7),
+			DefaultPickupLocation: getEnv("DEFAULT_PICKUP_LOCATION", "Front Desk"),
+			DefaultBranchCode:     getEnv("DEFAULT_BRANCH_CODE", "MAIN"),
+			DefaultBranchName:     getEnv("DEFAULT_BRANCH_NAME", "Main Library"),
+			CallNumberScheme:      getEnv("CALL_NUMBER_SCHEME", "dewey"),
+			AutoRenewEnabled:      getEnvAsBool("AUTO_RENEW_ENABLED", false),
+			AutoRenewDaysBefore:   getEnvAsInt("AUTO_RENEW_DAYS_BEFORE", 1),
+			AutoLostAfterDays:     getEnvAsInt("AUTO_LOST_AFTER_DAYS", 60),
+			DefaultReplacementFee: getEnvAsFloat("DEFAULT_REPLACEMENT_FEE", 25.00),
+			LostProcessingFee:     getEnvAsFloat("LOST_PROCESSING_FEE", 5.00),
+			SerialPredictMonths:   getEnvAsInt("SERIAL_PREDICT_MONTHS", 3),
+			RoomOpenHour:          getEnvAsInt("ROOM_OPEN_HOUR", 9),
+			RoomCloseHour:         getEnvAsInt("ROOM_CLOSE_HOUR", 21),
+			RoomBookingDaysAhead:  getEnvAsInt("ROOM_BOOKING_DAYS_AHEAD", 14),
 		},
 		
 		Pagination: PaginationConfig{
@@ -123,6 +220,64 @@ func Load() *Config {
 			MaxPageSize:     getEnvAsInt("MAX_PAGE_SIZE", 100),
 		},
 		
+		Scheduler: SchedulerConfig{
+			Enabled:  getEnvAsBool("SCHEDULER_ENABLED", true),
+			Interval: getEnvAsDuration("SCHEDULER_INTERVAL", 15*time.Minute),
+		},
+		
+		Search: SearchConfig{
+			DefaultLanguage:      getEnv("SEARCH_DEFAULT_LANGUAGE", "en"),
+			SuggestionThreshold:  getEnvAsFloat("SEARCH_SUGGESTION_THRESHOLD", 0.3),
+			AutocompleteCacheTTL: getEnvAsDuration("AUTOCOMPLETE_CACHE_TTL", 5*time.Minute),
+		},
+		
+		Import: ImportConfig{
+			BackgroundThreshold: getEnvAsInt("IMPORT_BACKGROUND_THRESHOLD", 100),
+			MaxRows:             getEnvAsInt("IMPORT_MAX_ROWS", 10000),
+			MaxFileSize:         int64(getEnvAsInt("IMPORT_MAX_FILE_SIZE", 10<<20)),
+		},
+		
+		Metadata: MetadataConfig{
+			Providers:         getEnvAsSlice("METADATA_PROVIDERS", []string{"openlibrary", "googlebooks"}),
+			GoogleBooksAPIKey: getEnv("GOOGLE_BOOKS_API_KEY", ""),
+			SRUURL:            getEnv("METADATA_SRU_URL", ""),
+			Timeout:           getEnvAsDuration("METADATA_TIMEOUT", 5*time.Second),
+			CacheTTL:          getEnvAsDuration("METADATA_CACHE_TTL", 24*time.Hour),
+		},
+		
+		Storage: StorageConfig{
+			Driver:      getEnv("STORAGE_DRIVER", "local"),
+			LocalPath:   getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+			S3Endpoint:  getEnv("S3_ENDPOINT", "localhost:9000"),
+			S3Region:    getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("S3_BUCKET", "library"),
+			S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+			S3UseSSL:    getEnvAsBool("S3_USE_SSL", false),
+			S3PathStyle: getEnvAsBool("S3_PATH_STYLE", true),
+		},
+		
+		Covers: CoverConfig{
+			MaxUploadSize: int64(getEnvAsInt("COVER_MAX_UPLOAD_SIZE", 5<<20)),
+			CacheMaxAge:   getEnvAsDuration("COVER_CACHE_MAX_AGE", 365*24*time.Hour),
+		},
+		
+		Digital: DigitalConfig{
+			LoanDays:      getEnvAsInt("DIGITAL_LOAN_DAYS", 14),
+			MaxCheckouts:  getEnvAsInt("DIGITAL_MAX_CHECKOUTS", 3),
+			HoldDays:      getEnvAsInt("DIGITAL_HOLD_DAYS", 3),
+			LinkTTL:       getEnvAsDuration("DIGITAL_LINK_TTL", 15*time.Minute),
+			SigningKey:    getEnv("DIGITAL_SIGNING_KEY", deriveKey(getEnv("JWT_SECRET", "change-this-secret-in-production"), "digital-download-links")),
+			MaxUploadSize: int64(getEnvAsInt("DIGITAL_MAX_UPLOAD_SIZE", 200<<20)),
+		},
+		
+		SIP2: SIP2Config{
+			Enabled:     getEnvAsBool("SIP2_ENABLED", false),
+			Port:        getEnvAsInt("SIP2_PORT", 6001),
+			Institution: getEnv("SIP2_INSTITUTION", "library"),
+			IdleTimeout: getEnvAsDuration("SIP2_IDLE_TIMEOUT", 10*time.Minute),
+		},
+		
 		LogLevel:  getEnv("LOG_LEVEL", "info"),
 		LogFormat: getEnv("LOG_FORMAT", "json"),
 	}
@@ -135,6 +290,14 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// deriveKey turns secret into a key used only for purpose, so a key that is not
+// configured on its own never signs anything with the JWT secret itself.
+func deriveKey(secret, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func getEnvAsInt(key string, defaultValue int) int {
 	valueStr := getEnv(key, "")
 	if value, err := strconv.Atoi(valueStr); err == nil {
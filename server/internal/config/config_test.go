@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestDigitalSigningKey(t *testing.T) {
+	t.Setenv("JWT_SECRET", "jwt-secret")
+	
+	t.Setenv("DIGITAL_SIGNING_KEY", "")
+	derived := Load().Digital.SigningKey
+	if derived == "" || derived == "jwt-secret" {
+		t.Fatalf("signing key %q is not derived from JWT_SECRET", derived)
+	}
+	if again := Load().Digital.SigningKey; again != derived {
+		t.Fatalf("derived key changed between loads: %q then %q", derived, again)
+	}
+	
+	t.Setenv("JWT_SECRET", "rotated-secret")
+	if rotated := Load().Digital.SigningKey; rotated == derived {
+		t.Fatalf("derived key did not follow JWT_SECRET")
+	}
+	
+	t.Setenv("DIGITAL_SIGNING_KEY", "download-key")
+	if key := Load().Digital.SigningKey; key != "download-key" {
+		t.Fatalf("signing key = %q, want the configured DIGITAL_SIGNING_KEY", key)
+	}
+}
@@ -54,14 +54,56 @@ func Migrate(db *gorm.DB) error {
 		&models.User{},
 		&models.Member{},
 		&models.Book{},
+		&models.BookCopy{},
 		&models.Loan{},
 		&models.Reservation{},
+		&models.Repair{},
+		&models.DeskSession{},
+		&models.DeskTransaction{},
+		&models.ImportJob{},
+		&models.ImportRow{},
+		&models.Author{},
+		&models.Publisher{},
+		&models.Series{},
+		&models.Subject{},
+		&models.BookAuthor{},
+		&models.BookPublisher{},
+		&models.BookSeries{},
+		&models.BookSubject{},
+		&models.Branch{},
+		&models.BookHolding{},
+		&models.Transfer{},
+		&models.Location{},
+		&models.Stocktake{},
+		&models.StocktakeScan{},
+		&models.Vendor{},
+		&models.Fund{},
+		&models.PurchaseOrder{},
+		&models.PurchaseOrderLine{},
+		&models.Invoice{},
+		&models.InvoiceLine{},
+		&models.PurchaseSuggestion{},
+		&models.WeedingCandidate{},
+		&models.Serial{},
+		&models.SerialSubscription{},
+		&models.SerialIssue{},
+		&models.IssueLoan{},
+		&models.ItemTypeRule{},
+		&models.RoomBooking{},
+		&models.DigitalItem{},
+		&models.DigitalCheckout{},
+		&models.DigitalHold{},
+		&dataMigration{},
 	)
 	
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 	
+	if err := createSearchVector(db); err != nil {
+		return fmt.Errorf("failed to create search vector: %w", err)
+	}
+	
 	if err := createIndexes(db); err != nil {
 		return fmt.Errorf("failed to create indexes: %w", err)
 	}
@@ -76,10 +118,24 @@ func Migrate(db *gorm.DB) error {
 
 func createIndexes(db *gorm.DB) error {
 	indexes := []string{
+		"DROP INDEX IF EXISTS idx_books_isbn",
+		"DROP INDEX IF EXISTS idx_books_barcode",
 		"CREATE INDEX IF NOT EXISTS idx_books_title_author ON books(title, author)",
+		"CREATE INDEX IF NOT EXISTS idx_books_search_vector ON books USING GIN(search_vector)",
+		"CREATE INDEX IF NOT EXISTS idx_books_title_trgm ON books USING GIN(title gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_books_author_trgm ON books USING GIN(author gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_books_tags ON books USING GIN(tags)",
+		"CREATE INDEX IF NOT EXISTS idx_authors_name_key_trgm ON authors USING GIN(name_key gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_publishers_name_key_trgm ON publishers USING GIN(name_key gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_series_name_key_trgm ON series USING GIN(name_key gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_subjects_name_key_trgm ON subjects USING GIN(name_key gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_series_name_trgm ON series USING GIN(name gin_trgm_ops)",
 		"CREATE INDEX IF NOT EXISTS idx_loans_status_due_date ON loans(status, due_date)",
 		"CREATE INDEX IF NOT EXISTS idx_members_membership_id ON members(membership_id)",
 		"CREATE INDEX IF NOT EXISTS idx_reservations_status_book ON reservations(status, book_id)",
+		"CREATE INDEX IF NOT EXISTS idx_room_bookings_room_time ON room_bookings(room_id, start_time, end_time) WHERE status = 'confirmed'",
+		"CREATE INDEX IF NOT EXISTS idx_digital_checkouts_active ON digital_checkouts(digital_item_id, expires_at) WHERE status = 'active'",
+		"CREATE INDEX IF NOT EXISTS idx_digital_holds_queue ON digital_holds(digital_item_id, created_at) WHERE status = 'waiting'",
 		"CREATE INDEX IF NOT EXISTS idx_users_email_active ON users(email, is_active)",
 	}
 	
@@ -92,6 +148,44 @@ func createIndexes(db *gorm.DB) error {
 	return nil
 }
 
+func createSearchVector(db *gorm.DB) error {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		`CREATE OR REPLACE FUNCTION books_search_config(lang text) RETURNS regconfig
+			LANGUAGE sql IMMUTABLE AS $$
+			SELECT CASE lower(coalesce(lang, ''))
+				WHEN 'en' THEN 'english'::regconfig
+				WHEN 'fr' THEN 'french'::regconfig
+				WHEN 'de' THEN 'german'::regconfig
+				WHEN 'es' THEN 'spanish'::regconfig
+				WHEN 'it' THEN 'italian'::regconfig
+				WHEN 'pt' THEN 'portuguese'::regconfig
+				WHEN 'nl' THEN 'dutch'::regconfig
+				WHEN 'ru' THEN 'russian'::regconfig
+				WHEN 'sv' THEN 'swedish'::regconfig
+				ELSE 'simple'::regconfig
+			END
+			$$`,
+		`CREATE OR REPLACE FUNCTION books_tags_text(tags text[]) RETURNS text
+			LANGUAGE sql IMMUTABLE AS $$ SELECT coalesce(array_to_string(tags, ' '), '') $$`,
+		`ALTER TABLE books ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector(books_search_config(language), coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce(author, '')), 'A') ||
+			setweight(to_tsvector(books_search_config(language), books_tags_text(tags)), 'B') ||
+			setweight(to_tsvector('simple', coalesce(publisher, '')), 'C') ||
+			setweight(to_tsvector(books_search_config(language), coalesce(description, '')), 'D')
+		) STORED`,
+	}
+	
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
 func seedInitialData(db *gorm.DB) error {
 	var adminCount int64
 	db.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&adminCount)
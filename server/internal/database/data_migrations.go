@@ -0,0 +1,48 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// dataMigration records a one-off data migration that has been applied, so
+// full-table backfills run once rather than on every start.
+type dataMigration struct {
+	Name      string    `gorm:"primaryKey;type:varchar(100)"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (dataMigration) TableName() string {
+	return "data_migrations"
+}
+
+// RunDataMigration runs fn once per database under the given name. The
+// migration and its record commit together, and an advisory lock keeps two
+// servers starting at once from both running it. It reports whether fn ran.
+func RunDataMigration(db *gorm.DB, name string, fn func(tx *gorm.DB) error) (bool, error) {
+	ran := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "data_migration:"+name).Error; err != nil {
+			return err
+		}
+		
+		var applied int64
+		if err := tx.Model(&dataMigration{}).Where("name = ?", name).Count(&applied).Error; err != nil {
+			return err
+		}
+		if applied > 0 {
+			return nil
+		}
+		
+		if err := fn(tx); err != nil {
+			return err
+		}
+		ran = true
+		return tx.Create(&dataMigration{Name: name, AppliedAt: time.Now()}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return ran, nil
+}
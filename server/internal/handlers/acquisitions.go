@@ -0,0 +1,483 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type AcquisitionHandler struct {
+	db           *gorm.DB
+	config       *config.Config
+	acquisitions *services.AcquisitionService
+}
+
+func NewAcquisitionHandler(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *AcquisitionHandler {
+	return &AcquisitionHandler{
+		db:           db,
+		config:       cfg,
+		acquisitions: services.NewAcquisitionService(db, redisClient, cfg),
+	}
+}
+
+func (h *AcquisitionHandler) GetVendors(c *gin.Context) {
+	query := h.db.Model(&models.Vendor{})
+	if c.Query("include_inactive") != "true" {
+		query = query.Where("is_active = ?", true)
+	}
+	
+	var vendors []models.Vendor
+	if err := query.Order("name ASC").Find(&vendors).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch vendors"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": vendors,
+	})
+}
+
+func (h *AcquisitionHandler) CreateVendor(c *gin.Context) {
+	var req models.VendorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	vendor := models.Vendor{IsActive: true}
+	applyVendorRequest(&vendor, req)
+	
+	var existing int64
+	h.db.Unscoped().Model(&models.Vendor{}).Where("code = ?", vendor.Code).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": services.ErrVendorExists.Error()})
+		return
+	}
+	
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&vendor).Error; err != nil {
+			return err
+		}
+		if !vendor.IsActive {
+			return tx.Model(&vendor).Update("is_active", false).Error
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create vendor"})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": vendor,
+	})
+}
+
+func (h *AcquisitionHandler) UpdateVendor(c *gin.Context) {
+	var req struct {
+		VendorID   string               `json:"vendor_id" binding:"required"`
+		VendorData models.VendorRequest `json:"vendor_data"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	var vendor models.Vendor
+	if err := h.db.First(&vendor, "id = ?", req.VendorID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vendor not found"})
+		return
+	}
+	
+	applyVendorRequest(&vendor, req.VendorData)
+	
+	var existing int64
+	h.db.Unscoped().Model(&models.Vendor{}).Where("code = ? AND id <> ?", vendor.Code, vendor.ID).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": services.ErrVendorExists.Error()})
+		return
+	}
+	
+	if err := h.db.Save(&vendor).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vendor"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": vendor,
+	})
+}
+
+func (h *AcquisitionHandler) GetFunds(c *gin.Context) {
+	query := h.db.Model(&models.Fund{})
+	if year := c.Query("fiscal_year"); year != "" {
+		query = query.Where("fiscal_year = ?", year)
+	}
+	if branchID := c.Query("branch_id"); branchID != "" {
+		query = query.Where("branch_id = ?", branchID)
+	}
+	if c.Query("include_inactive") != "true" {
+		query = query.Where("is_active = ?", true)
+	}
+	
+	var funds []models.Fund
+	if err := query.Order("fiscal_year DESC").Order("code ASC").Find(&funds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch funds"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": funds,
+	})
+}
+
+func (h *AcquisitionHandler) CreateFund(c *gin.Context) {
+	var req models.FundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	fund := models.Fund{IsActive: true}
+	if err := h.applyFundRequest(&fund, req); err != nil {
+		c.JSON(acquisitionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	var existing int64
+	h.db.Unscoped().Model(&models.Fund{}).Where("code = ? AND fiscal_year = ?", fund.Code, fund.FiscalYear).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": services.ErrFundExists.Error()})
+		return
+	}
+	
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&fund).Error; err != nil {
+			return err
+		}
+		if !fund.IsActive {
+			return tx.Model(&fund).Update("is_active", false).Error
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create fund"})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": fund,
+	})
+}
+
+func (h *AcquisitionHandler) UpdateFund(c *gin.Context) {
+	var req struct {
+		FundID   string             `json:"fund_id" binding:"required"`
+		FundData models.FundRequest `json:"fund_data"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	var fund models.Fund
+	if err := h.db.First(&fund, "id = ?", req.FundID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fund not found"})
+		return
+	}
+	
+	if err := h.applyFundRequest(&fund, req.FundData); err != nil {
+		c.JSON(acquisitionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	var existing int64
+	h.db.Unscoped().Model(&models.Fund{}).Where("code = ? AND fiscal_year = ? AND id <> ?", fund.Code, fund.FiscalYear, fund.ID).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": services.ErrFundExists.Error()})
+		return
+	}
+	
+	if err := h.db.Save(&fund).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update fund"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": fund,
+	})
+}
+
+func (h *AcquisitionHandler) GetFundReport(c *gin.Context) {
+	fiscalYear, _ := strconv.Atoi(c.Query("fiscal_year"))
+	
+	report, err := h.acquisitions.FundReport(fiscalYear, c.Query("branch_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build fund report"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": report,
+	})
+}
+
+func (h *AcquisitionHandler) GetOrders(c *gin.Context) {
+	orders, err := h.acquisitions.Orders(c.Query("vendor_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch purchase orders"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": orders,
+	})
+}
+
+func (h *AcquisitionHandler) GetOrder(c *gin.Context) {
+	order, err := h.acquisitions.Order(c.Query("order_id"))
+	if err != nil {
+		c.JSON(acquisitionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": order,
+	})
+}
+
+func (h *AcquisitionHandler) CreateOrder(c *gin.Context) {
+	var req models.OrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	order, err := h.acquisitions.CreateOrder(req, user.ID)
+	if err != nil {
+		c.JSON(acquisitionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": order,
+	})
+}
+
+func (h *AcquisitionHandler) UpdateOrder(c *gin.Context) {
+	var req struct {
+		OrderID   string              `json:"order_id" binding:"required"`
+		OrderData models.OrderRequest `json:"order_data"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	order, err := h.acquisitions.UpdateOrder(req.OrderID, req.OrderData)
+	if err != nil {
+		c.JSON(acquisitionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": order,
+	})
+}
+
+func (h *AcquisitionHandler) PlaceOrder(c *gin.Context) {
+	h.changeOrder(c, h.acquisitions.PlaceOrder)
+}
+
+func (h *AcquisitionHandler) CancelOrder(c *gin.Context) {
+	h.changeOrder(c, h.acquisitions.CancelOrder)
+}
+
+func (h *AcquisitionHandler) ReceiveItems(c *gin.Context) {
+	var req models.ReceiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	result, err := h.acquisitions.Receive(req)
+	if err != nil {
+		c.JSON(acquisitionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": result,
+	})
+}
+
+func (h *AcquisitionHandler) GetInvoices(c *gin.Context) {
+	invoices, err := h.acquisitions.Invoices(c.Query("vendor_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoices"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": invoices,
+	})
+}
+
+func (h *AcquisitionHandler) CreateInvoice(c *gin.Context) {
+	var req models.InvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	invoice, err := h.acquisitions.CreateInvoice(req, user.ID)
+	if err != nil {
+		c.JSON(acquisitionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": invoice,
+	})
+}
+
+func (h *AcquisitionHandler) PayInvoice(c *gin.Context) {
+	h.changeInvoice(c, h.acquisitions.PayInvoice)
+}
+
+func (h *AcquisitionHandler) CancelInvoice(c *gin.Context) {
+	h.changeInvoice(c, h.acquisitions.CancelInvoice)
+}
+
+func (h *AcquisitionHandler) changeOrder(c *gin.Context, change func(string) (*models.PurchaseOrder, error)) {
+	var req struct {
+		OrderID string `json:"order_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	order, err := change(req.OrderID)
+	if err != nil {
+		c.JSON(acquisitionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": order,
+	})
+}
+
+func (h *AcquisitionHandler) changeInvoice(c *gin.Context, change func(string) (*models.Invoice, error)) {
+	var req struct {
+		InvoiceID string `json:"invoice_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	invoice, err := change(req.InvoiceID)
+	if err != nil {
+		c.JSON(acquisitionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": invoice,
+	})
+}
+
+func (h *AcquisitionHandler) applyFundRequest(fund *models.Fund, req models.FundRequest) error {
+	fund.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	fund.Name = strings.TrimSpace(req.Name)
+	fund.FiscalYear = req.FiscalYear
+	fund.Allocated = req.Allocated
+	if req.IsActive != nil {
+		fund.IsActive = *req.IsActive
+	}
+	
+	fund.BranchID = nil
+	if req.BranchID != "" {
+		branch, err := services.ResolveBranch(h.db, req.BranchID)
+		if err != nil {
+			return err
+		}
+		fund.BranchID = &branch.ID
+	}
+	return nil
+}
+
+func applyVendorRequest(vendor *models.Vendor, req models.VendorRequest) {
+	vendor.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	vendor.Name = strings.TrimSpace(req.Name)
+	vendor.ContactName = req.ContactName
+	vendor.Email = req.Email
+	vendor.Phone = req.Phone
+	vendor.Address = req.Address
+	vendor.AccountNumber = req.AccountNumber
+	if req.IsActive != nil {
+		vendor.IsActive = *req.IsActive
+	}
+}
+
+func acquisitionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrVendorNotFound),
+		errors.Is(err, services.ErrFundNotFound),
+		errors.Is(err, services.ErrOrderNotFound),
+		errors.Is(err, services.ErrOrderLineNotFound),
+		errors.Is(err, services.ErrInvoiceNotFound),
+		errors.Is(err, services.ErrBookNotFound),
+		errors.Is(err, services.ErrBranchNotFound),
+		errors.Is(err, services.ErrLocationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrOrderNotDraft),
+		errors.Is(err, services.ErrOrderNotOpen),
+		errors.Is(err, services.ErrOrderClosed),
+		errors.Is(err, services.ErrInvoiceExists),
+		errors.Is(err, services.ErrInvoiceNotOpen),
+		errors.Is(err, services.ErrFundOverspent):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrVendorInactive),
+		errors.Is(err, services.ErrFundInactive),
+		errors.Is(err, services.ErrFundRequired),
+		errors.Is(err, services.ErrOrderLineTitle),
+		errors.Is(err, services.ErrOrderLineInvalid),
+		errors.Is(err, services.ErrOverReceipt),
+		errors.Is(err, services.ErrInvoiceOrderVendor),
+		errors.Is(err, services.ErrBranchInactive),
+		errors.Is(err, services.ErrLocationBranch):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
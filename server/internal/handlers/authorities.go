@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+var authoritySortColumns = map[string]string{
+	"sort_name":  "t.sort_name",
+	"created_at": "t.created_at",
+}
+
+type AuthorityHandler struct {
+	db          *gorm.DB
+	config      *config.Config
+	authorities *services.AuthorityService
+}
+
+func NewAuthorityHandler(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *AuthorityHandler {
+	return &AuthorityHandler{
+		db:          db,
+		config:      cfg,
+		authorities: services.NewAuthorityService(db, redisClient),
+	}
+}
+
+func (h *AuthorityHandler) Browse(typ models.AuthorityType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		params, err := utils.GetListParams(c, authoritySortColumns, "sort_name", "asc", h.config.Pagination.DefaultPageSize, h.config.Pagination.MaxPageSize)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		
+		query := h.authorities.BrowseQuery(typ)
+		
+		if search := c.Query("search"); search != "" {
+			pattern := "%" + search + "%"
+			query = query.Where("t.name ILIKE ? OR array_to_string(t.alt_names, ' ') ILIKE ?", pattern, pattern)
+		}
+		
+		if prefix := c.Query("starts_with"); prefix != "" {
+			query = query.Where("t.sort_name ILIKE ?", prefix+"%")
+		}
+		
+		if scheme := c.Query("scheme"); scheme != "" && typ == models.AuthoritySubject {
+			query = query.Where("t.scheme = ?", scheme)
+		}
+		
+		var total int64
+		query.Session(&gorm.Session{}).Select("COUNT(*)").Scan(&total)
+		
+		records := []models.AuthorityResponse{}
+		if err := params.Apply(query, "t.id").Scan(&records).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch authority records"})
+			return
+		}
+		
+		for i := range records {
+			records[i].Type = typ
+		}
+		
+		records, nextCursor := utils.TrimPage(params, records)
+		
+		c.JSON(http.StatusOK, gin.H{
+			"message": gin.H{
+				"records":    records,
+				"pagination": params.Pagination(total, nextCursor),
+			},
+		})
+	}
+}
+
+func (h *AuthorityHandler) Get(typ models.AuthorityType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Query("id")
+		if id == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Authority ID required"})
+			return
+		}
+		
+		record, books, err := h.authorities.Get(typ, id)
+		if err != nil {
+			c.JSON(authorityErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		
+		c.JSON(http.StatusOK, gin.H{
+			"message": gin.H{
+				"record": record,
+				"books":  books,
+			},
+		})
+	}
+}
+
+func (h *AuthorityHandler) UpdateAuthority(c *gin.Context) {
+	var req models.AuthorityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	record, err := h.authorities.Update(req)
+	if err != nil {
+		c.JSON(authorityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": record,
+	})
+}
+
+func (h *AuthorityHandler) MergeAuthorities(c *gin.Context) {
+	var req struct {
+		Type      models.AuthorityType `json:"type" binding:"required"`
+		TargetID  string               `json:"target_id" binding:"required"`
+		SourceIDs []string             `json:"source_ids" binding:"required,min=1"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	record, err := h.authorities.Merge(req.Type, req.TargetID, req.SourceIDs)
+	if err != nil {
+		c.JSON(authorityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": record,
+	})
+}
+
+func (h *AuthorityHandler) GetDuplicateSuggestions(c *gin.Context) {
+	typ := models.AuthorityType(c.DefaultQuery("type", string(models.AuthorityAuthor)))
+	
+	threshold, err := strconv.ParseFloat(c.DefaultQuery("threshold", "0.5"), 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Threshold must be between 0 and 1"})
+		return
+	}
+	
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > h.config.Pagination.MaxPageSize {
+		limit = h.config.Pagination.MaxPageSize
+	}
+	
+	suggestions, err := h.authorities.DuplicateSuggestions(typ, threshold, limit)
+	if err != nil {
+		c.JSON(authorityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": suggestions,
+	})
+}
+
+func (h *AuthorityHandler) MigrateAuthorities(c *gin.Context) {
+	report, err := h.authorities.Migrate(c.Query("dry_run") != "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to migrate authority records"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": report,
+	})
+}
+
+func authorityErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrAuthorityNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrAuthorityExists):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidAuthorityType),
+		errors.Is(err, services.ErrAuthorityMergeSelf),
+		errors.Is(err, services.ErrInvalidContributor),
+		errors.Is(err, services.ErrEmptyAuthorityName):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
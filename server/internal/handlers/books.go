@@ -1,49 +1,71 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/metadata"
 	"github.com/library-management-system/server/internal/middleware"
 	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/internal/storage"
+	"github.com/library-management-system/server/pkg/isbn"
 	"github.com/library-management-system/server/pkg/utils"
 	
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
 type BookHandler struct {
-	db     *gorm.DB
-	config *config.Config
+	db       *gorm.DB
+	config   *config.Config
+	catalog  *services.CatalogService
+	metadata *metadata.Chain
+	covers      *services.CoverService
+	authorities *services.AuthorityService
+	locations   *services.LocationService
+	suggestions *services.PurchaseSuggestionService
 }
 
-func NewBookHandler(db *gorm.DB, cfg *config.Config) *BookHandler {
+func NewBookHandler(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *BookHandler {
 	return &BookHandler{
-		db:     db,
-		config: cfg,
+		db:       db,
+		config:   cfg,
+		catalog:  services.NewCatalogService(db, redisClient, cfg),
+		metadata: metadata.NewFromConfig(cfg.Metadata, redisClient),
+		covers:      services.NewCoverService(db, storage.NewFromConfig(cfg.Storage), cfg),
+		authorities: services.NewAuthorityService(db, redisClient),
+		locations:   services.NewLocationService(db, cfg),
+		suggestions: services.NewPurchaseSuggestionService(db, cfg),
 	}
 }
 
+var bookSortColumns = map[string]string{
+	"title":            "books.title",
+	"author":           "books.author",
+	"isbn":             "books.isbn",
+	"publish_date":     "books.publish_date",
+	"available_copies": "books.available_copies",
+	"created_at":       "books.created_at",
+	// Rows catalogued before call numbers have a NULL sort key, which a
+	// keyset cursor would skip past, so it is read as empty instead.
+	"shelf_order":      `COALESCE(books.call_number_sort, '') COLLATE "C"`,
+	"relevance":        "rank",
+}
+
 func (h *BookHandler) GetBooks(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(h.config.Pagination.DefaultPageSize)))
 	search := c.Query("search")
-	sortBy := c.DefaultQuery("sort_by", "title")
-	sortOrder := c.DefaultQuery("sort_order", "asc")
-	
-	if limit > h.config.Pagination.MaxPageSize {
-		limit = h.config.Pagination.MaxPageSize
-	}
-	
-	offset := (page - 1) * limit
+	language := c.DefaultQuery("lang", h.config.Search.DefaultLanguage)
 	
 	query := h.db.Model(&models.Book{})
 	
 	if search != "" {
-		query = query.Where("title ILIKE ? OR author ILIKE ? OR isbn ILIKE ?",
-			"%"+search+"%", "%"+search+"%", "%"+search+"%")
+		query = services.MatchBooks(query, search, language)
 	}
 	
 	if category := c.Query("category"); category != "" {
@@ -54,33 +76,90 @@ func (h *BookHandler) GetBooks(c *gin.Context) {
 		query = query.Where("status = ?", status)
 	}
 	
-	var total int64
-	query.Count(&total)
+	if itemType := c.Query("item_type"); itemType != "" {
+		query = query.Where("item_type = ?", itemType)
+	}
 	
-	var books []models.Book
-	query.Order(sortBy + " " + sortOrder).
-		Limit(limit).
-		Offset(offset).
-		Find(&books)
+	bookResponses, pagination, err := h.listBooks(c, query, search, language)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	
-	bookResponses := make([]models.BookResponse, len(books))
-	for i, book := range books {
-		bookResponses[i] = h.bookToResponse(book)
+	response := gin.H{
+		"books":      bookResponses,
+		"pagination": pagination,
+	}
+	
+	if search != "" && pagination.Total == 0 {
+		response["did_you_mean"] = h.catalog.DidYouMean(search, 3)
 	}
 	
 	c.JSON(http.StatusOK, gin.H{
-		"message": gin.H{
-			"books": bookResponses,
-			"pagination": gin.H{
-				"page":       page,
-				"limit":      limit,
-				"total":      total,
-				"totalPages": (total + int64(limit) - 1) / int64(limit),
-			},
-		},
+		"message": response,
 	})
 }
 
+func (h *BookHandler) listBooks(c *gin.Context, query *gorm.DB, search, language string) ([]models.BookResponse, utils.Pagination, error) {
+	defaultSort, defaultOrder := "title", "asc"
+	if search != "" {
+		defaultSort, defaultOrder = "relevance", "desc"
+	}
+	
+	params, err := utils.GetListParams(c, bookSortColumns, defaultSort, defaultOrder, h.config.Pagination.DefaultPageSize, h.config.Pagination.MaxPageSize)
+	if err != nil {
+		return nil, utils.Pagination{}, err
+	}
+	
+	var total int64
+	query.Count(&total)
+	
+	if params.SortBy == "relevance" {
+		if search == "" {
+			return nil, utils.Pagination{}, utils.ErrInvalidSortField
+		}
+		
+		if params.HasCursor() {
+			return nil, utils.Pagination{}, utils.ErrInvalidCursor
+		}
+		
+		bookResponses := h.findBooks(query.Order("rank DESC, books.id ASC").Limit(params.Limit+1).Offset(params.Offset), search, language)
+		if len(bookResponses) > params.Limit {
+			bookResponses = bookResponses[:params.Limit]
+		}
+		return bookResponses, params.Pagination(total, ""), nil
+	}
+	
+	bookResponses, nextCursor := utils.TrimPage(params, h.findBooks(params.Apply(query, "books.id"), search, language))
+	return bookResponses, params.Pagination(total, nextCursor), nil
+}
+
+func (h *BookHandler) findBooks(query *gorm.DB, search, language string) []models.BookResponse {
+	if search == "" {
+		var books []models.Book
+		query.Find(&books)
+		return h.booksToResponses(books)
+	}
+	
+	var ranked []services.RankedBook
+	services.RankBooks(query, search, language).Scan(&ranked)
+	
+	books := make([]models.Book, len(ranked))
+	for i, result := range ranked {
+		books[i] = result.Book
+	}
+	
+	bookResponses := h.booksToResponses(books)
+	for i, result := range ranked {
+		bookResponses[i].Rank = result.Rank
+		bookResponses[i].Highlight = &models.BookHighlight{
+			Title:   result.TitleHighlight,
+			Snippet: result.Snippet,
+		}
+	}
+	return bookResponses
+}
+
 func (h *BookHandler) GetBook(c *gin.Context) {
 	bookID := c.Query("book_id")
 	if bookID == "" {
@@ -94,8 +173,11 @@ func (h *BookHandler) GetBook(c *gin.Context) {
 		return
 	}
 	
+	response := h.bookToResponse(book)
+	response.CopyBarcodes = services.CopyBarcodes(h.db, book.ID)
+	
 	c.JSON(http.StatusOK, gin.H{
-		"message": h.bookToResponse(book),
+		"message": response,
 	})
 }
 
@@ -109,20 +191,63 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 		return
 	}
 	
+	itemType, attributes, err := services.CleanItemAttributes(req.BookData.ItemType, req.BookData.Attributes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	normalizedISBN, err := normalizeItemISBN(itemType, req.BookData.ISBN)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ISBN: " + err.Error()})
+		return
+	}
+	
+	callNumber, err := services.ParseCallNumber(req.BookData.CallNumber, req.BookData.Classification, h.config.Library.CallNumberScheme)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid call number: " + err.Error()})
+		return
+	}
+	
+	var existing int64
+	if normalizedISBN != "" {
+		h.db.Unscoped().Model(&models.Book{}).Where("isbn = ?", normalizedISBN).Count(&existing)
+	}
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "A book with this ISBN already exists"})
+		return
+	}
+	
+	barcode := strings.TrimSpace(req.BookData.Barcode)
+	copyBarcodes, err := services.CleanCopyBarcodes(barcode, req.BookData.CopyBarcodes, req.BookData.TotalCopies)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.CheckBarcodes(h.db, uuid.Nil, append(copyBarcodes, barcode)...); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	
 	book := models.Book{
 		ID:              uuid.New(),
 		Title:           req.BookData.Title,
 		Author:          req.BookData.Author,
-		ISBN:            req.BookData.ISBN,
+		ISBN:            normalizedISBN,
+		Barcode:         barcode,
 		Publisher:       req.BookData.Publisher,
 		PublishDate:     req.BookData.PublishDate,
 		Category:        req.BookData.Category,
+		Language:        req.BookData.Language,
 		Description:     req.BookData.Description,
 		CoverImage:      req.BookData.CoverImage,
 		TotalCopies:     req.BookData.TotalCopies,
 		AvailableCopies: req.BookData.AvailableCopies,
+		ReplacementCost: req.BookData.ReplacementCost,
 		Location:        req.BookData.Location,
 		Status:          models.BookStatusAvailable,
+		ItemType:        itemType,
+		Attributes:      attributes,
 		Tags:            req.BookData.Tags,
 	}
 	
@@ -130,10 +255,58 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 		book.AvailableCopies = book.TotalCopies
 	}
 	
-	if err := h.db.Create(&book).Error; err != nil {
+	if book.Language == "" {
+		book.Language = h.config.Search.DefaultLanguage
+	}
+	
+	services.ApplyCallNumber(&book, callNumber)
+	
+	branch, err := services.ResolveBranch(h.db, req.BookData.BranchID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch"})
+		return
+	}
+	
+	location, err := h.locations.Resolve(h.db, branch.ID, req.BookData.LocationID, req.BookData.Location)
+	if err == nil && location == nil {
+		location, err = h.locations.ShelfFor(h.db, branch.ID, book.CallNumberSort)
+	}
+	if err != nil {
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if location != nil {
+		book.Location = location.Path
+	}
+	
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&book).Error; err != nil {
+			return err
+		}
+		if err := services.AdjustHolding(tx, book.ID, branch.ID, book.TotalCopies); err != nil {
+			return err
+		}
+		if err := services.SyncCopies(tx, book.ID, copyBarcodes); err != nil {
+			return err
+		}
+		if err := h.locations.Place(tx, book.ID, branch.ID, location); err != nil {
+			return err
+		}
+		if err := h.authorities.LinkBook(tx, &book, req.BookData); err != nil {
+			return err
+		}
+		_, err := h.suggestions.Fulfill(tx, &book, branch.ID)
+		return err
+	})
+	if err != nil {
+		if status := authorityErrorStatus(err); status != http.StatusInternalServerError {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create book"})
 		return
 	}
+	h.catalog.InvalidateAutocomplete(c.Request.Context())
 	
 	c.JSON(http.StatusCreated, gin.H{
 		"message": h.bookToResponse(book),
@@ -157,28 +330,152 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 		return
 	}
 	
+	itemType, attributes, err := services.CleanItemAttributes(req.BookData.ItemType, req.BookData.Attributes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	if itemType != book.ItemType && services.ItemInUse(h.db, book.ID) {
+		c.JSON(http.StatusConflict, gin.H{"error": services.ErrItemTypeInUse.Error()})
+		return
+	}
+	
+	normalizedISBN, err := normalizeItemISBN(itemType, req.BookData.ISBN)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ISBN: " + err.Error()})
+		return
+	}
+	
+	callNumber, err := services.ParseCallNumber(req.BookData.CallNumber, req.BookData.Classification, h.config.Library.CallNumberScheme)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid call number: " + err.Error()})
+		return
+	}
+	
+	var existing int64
+	if normalizedISBN != "" {
+		h.db.Unscoped().Model(&models.Book{}).Where("isbn = ? AND id <> ?", normalizedISBN, book.ID).Count(&existing)
+	}
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "A book with this ISBN already exists"})
+		return
+	}
+	
+	barcode := strings.TrimSpace(req.BookData.Barcode)
+	copyBarcodes := req.BookData.CopyBarcodes
+	if copyBarcodes == nil {
+		copyBarcodes = services.CopyBarcodes(h.db, book.ID)
+	}
+	copyBarcodes, err = services.CleanCopyBarcodes(barcode, copyBarcodes, req.BookData.TotalCopies)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.CheckBarcodes(h.db, book.ID, append(copyBarcodes, barcode)...); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	
 	updates := map[string]interface{}{
 		"title":            req.BookData.Title,
 		"author":           req.BookData.Author,
-		"isbn":             req.BookData.ISBN,
+		"isbn":             normalizedISBN,
+		"barcode":          barcode,
 		"publisher":        req.BookData.Publisher,
 		"publish_date":     req.BookData.PublishDate,
 		"category":         req.BookData.Category,
+		"language":         req.BookData.Language,
 		"description":      req.BookData.Description,
 		"cover_image":      req.BookData.CoverImage,
 		"total_copies":     req.BookData.TotalCopies,
 		"available_copies": req.BookData.AvailableCopies,
+		"replacement_cost": req.BookData.ReplacementCost,
 		"location":         req.BookData.Location,
+		"call_number":      callNumber.Display,
+		"classification":   string(callNumber.Scheme),
+		"call_number_sort": callNumber.SortKey,
+		"item_type":        itemType,
+		"attributes":       attributes,
 		"tags":             req.BookData.Tags,
 	}
 	
-	if err := h.db.Model(&book).Updates(updates).Error; err != nil {
+	var branchID uuid.UUID
+	if req.BookData.BranchID != "" {
+		branch, err := services.ResolveBranch(h.db, req.BookData.BranchID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch"})
+			return
+		}
+		branchID = branch.ID
+	}
+	
+	var location *models.Location
+	placeBranchID := branchID
+	relocate := req.BookData.LocationID != "" || !strings.EqualFold(req.BookData.Location, book.Location)
+	reshelve := callNumber.SortKey != "" && callNumber.SortKey != book.CallNumberSort
+	if relocate || reshelve {
+		if placeBranchID == uuid.Nil {
+			if placeBranchID, err = h.locations.PrimaryBranch(h.db, book.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update book"})
+				return
+			}
+		}
+		
+		if relocate {
+			location, err = h.locations.Resolve(h.db, placeBranchID, req.BookData.LocationID, req.BookData.Location)
+		} else {
+			location, err = h.locations.ShelfFor(h.db, placeBranchID, callNumber.SortKey)
+		}
+		if err != nil {
+			c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if location != nil {
+			updates["location"] = location.Path
+		}
+	}
+	
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&book).Updates(updates).Error; err != nil {
+			return err
+		}
+		
+		book.TotalCopies = req.BookData.TotalCopies
+		if err := services.SyncHoldings(tx, &book, branchID); err != nil {
+			return err
+		}
+		if err := services.SyncCopies(tx, book.ID, copyBarcodes); err != nil {
+			return err
+		}
+		if err := h.locations.Place(tx, book.ID, placeBranchID, location); err != nil {
+			return err
+		}
+		
+		book.Author = req.BookData.Author
+		book.Publisher = req.BookData.Publisher
+		book.Category = req.BookData.Category
+		return h.authorities.LinkBook(tx, &book, req.BookData)
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrCopyOnLoan) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if status := authorityErrorStatus(err); status != http.StatusInternalServerError {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update book"})
 		return
 	}
+	h.catalog.InvalidateAutocomplete(c.Request.Context())
+	
+	response := h.bookToResponse(book)
+	response.CopyBarcodes = copyBarcodes
 	
 	c.JSON(http.StatusOK, gin.H{
-		"message": h.bookToResponse(book),
+		"message": response,
 	})
 }
 
@@ -204,6 +501,7 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete book"})
 		return
 	}
+	h.catalog.InvalidateAutocomplete(c.Request.Context())
 	
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Book deleted successfully",
@@ -211,41 +509,25 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 }
 
 func (h *BookHandler) GetAvailableBooks(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(h.config.Pagination.DefaultPageSize)))
 	search := c.Query("search")
-	
-	offset := (page - 1) * limit
+	language := c.DefaultQuery("lang", h.config.Search.DefaultLanguage)
 	
 	query := h.db.Model(&models.Book{}).Where("available_copies > 0 AND status = ?", models.BookStatusAvailable)
 	
 	if search != "" {
-		query = query.Where("title ILIKE ? OR author ILIKE ?", "%"+search+"%", "%"+search+"%")
+		query = services.MatchBooks(query, search, language)
 	}
 	
-	var total int64
-	query.Count(&total)
-	
-	var books []models.Book
-	query.Order("title ASC").
-		Limit(limit).
-		Offset(offset).
-		Find(&books)
-	
-	bookResponses := make([]models.BookResponse, len(books))
-	for i, book := range books {
-		bookResponses[i] = h.bookToResponse(book)
+	bookResponses, pagination, err := h.listBooks(c, query, search, language)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 	
 	c.JSON(http.StatusOK, gin.H{
 		"message": gin.H{
-			"books": bookResponses,
-			"pagination": gin.H{
-				"page":       page,
-				"limit":      limit,
-				"total":      total,
-				"totalPages": (total + int64(limit) - 1) / int64(limit),
-			},
+			"books":      bookResponses,
+			"pagination": pagination,
 		},
 	})
 }
@@ -254,20 +536,29 @@ func (h *BookHandler) SearchBooks(c *gin.Context) {
 	query := c.Query("query")
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	
+	if limit < 1 {
+		limit = 10
+	}
+	
+	if limit > h.config.Pagination.MaxPageSize {
+		limit = h.config.Pagination.MaxPageSize
+	}
+	
 	if query == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query required"})
 		return
 	}
 	
-	var books []models.Book
-	h.db.Where("title ILIKE ? OR author ILIKE ? OR isbn ILIKE ?",
-		"%"+query+"%", "%"+query+"%", "%"+query+"%").
-		Limit(limit).
-		Find(&books)
+	language := c.DefaultQuery("lang", h.config.Search.DefaultLanguage)
+	matched := services.MatchBooks(h.db.Model(&models.Book{}), query, language)
+	bookResponses := h.findBooks(matched.Order("rank DESC, title ASC").Limit(limit), query, language)
 	
-	bookResponses := make([]models.BookResponse, len(books))
-	for i, book := range books {
-		bookResponses[i] = h.bookToResponse(book)
+	if len(bookResponses) == 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"message":      bookResponses,
+			"did_you_mean": h.catalog.DidYouMean(query, 3),
+		})
+		return
 	}
 	
 	c.JSON(http.StatusOK, gin.H{
@@ -275,9 +566,107 @@ func (h *BookHandler) SearchBooks(c *gin.Context) {
 	})
 }
 
+func (h *BookHandler) FacetedSearch(c *gin.Context) {
+	filters := services.FacetFilters{
+		Search:       c.Query("search"),
+		Language:     c.DefaultQuery("lang", h.config.Search.DefaultLanguage),
+		Categories:   c.QueryArray("category"),
+		Authors:      c.QueryArray("author"),
+		Publishers:   c.QueryArray("publisher"),
+		Tags:         c.QueryArray("tag"),
+		TagsMatchAll: c.Query("tag_mode") == "all",
+		Decades:      services.ParseDecades(c.QueryArray("decade")),
+		Languages:    c.QueryArray("language"),
+		Availability: c.QueryArray("availability"),
+	}
+	
+	query := h.catalog.FilterBooks(h.db.Model(&models.Book{}), filters)
+	
+	bookResponses, pagination, err := h.listBooks(c, query, filters.Search, filters.Language)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	facets, err := h.catalog.Facets(filters, 20)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute facets"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": gin.H{
+			"books":      bookResponses,
+			"facets":     facets,
+			"pagination": pagination,
+		},
+	})
+}
+
+func (h *BookHandler) Autocomplete(c *gin.Context) {
+	prefix := c.Query("prefix")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "8"))
+	
+	if limit < 1 || limit > 20 {
+		limit = 8
+	}
+	
+	suggestions, err := h.catalog.Autocomplete(c.Request.Context(), prefix, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": suggestions,
+	})
+}
+
+func (h *BookHandler) BrowseShelf(c *gin.Context) {
+	if c.Query("call_number") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Call number required"})
+		return
+	}
+	
+	callNumber, err := services.ParseCallNumber(c.Query("call_number"), c.Query("classification"), h.config.Library.CallNumberScheme)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid call number: " + err.Error()})
+		return
+	}
+	
+	before, _ := strconv.Atoi(c.DefaultQuery("before", "5"))
+	if before < 0 || before > 50 {
+		before = 5
+	}
+	
+	after, _ := strconv.Atoi(c.DefaultQuery("after", "5"))
+	if after < 0 || after > 50 {
+		after = 5
+	}
+	
+	previous, next, err := h.catalog.BrowseShelf(callNumber, before, after)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to browse shelf list"})
+		return
+	}
+	
+	shelfList := models.ShelfList{
+		CallNumber:     callNumber.Display,
+		Classification: string(callNumber.Scheme),
+		Before:         h.booksToResponses(previous),
+		After:          h.booksToResponses(next),
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": shelfList,
+	})
+}
+
 func (h *BookHandler) ReserveBook(c *gin.Context) {
 	var req struct {
-		BookID string `json:"book_id"`
+		BookID         string `json:"book_id"`
+		PickupLocation string `json:"pickup_location"`
+		PickupBranchID string `json:"pickup_branch_id"`
 	}
 	
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -303,31 +692,59 @@ func (h *BookHandler) ReserveBook(c *gin.Context) {
 		return
 	}
 	
+	if !services.ItemRule(h.db, h.config, book.ItemType).Lendable {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrItemNotLendable.Error()})
+		return
+	}
+	
 	if book.IsAvailable() {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Book is available for immediate loan"})
 		return
 	}
 	
 	var existingReservation models.Reservation
-	err := h.db.Where("book_id = ? AND member_id = ? AND status = ?",
-		book.ID, member.ID, models.ReservationStatusPending).First(&existingReservation).Error
+	err := h.db.Where("book_id = ? AND member_id = ? AND status IN ?",
+		book.ID, member.ID, []models.ReservationStatus{models.ReservationStatusPending, models.ReservationStatusInTransit, models.ReservationStatusReadyForPickup}).
+		First(&existingReservation).Error
 	
 	if err == nil {
 		c.JSON(http.StatusConflict, gin.H{"error": "You already have a pending reservation for this book"})
 		return
 	}
 	
+	var pickupBranch *models.Branch
+	if req.PickupBranchID != "" {
+		branch, err := services.ResolveBranch(h.db, req.PickupBranchID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pickup branch"})
+			return
+		}
+		pickupBranch = branch
+	} else if member.HomeBranchID != nil {
+		pickupBranch, _ = services.ResolveBranch(h.db, member.HomeBranchID.String())
+	}
+	
+	var pickupBranchID *uuid.UUID
+	if pickupBranch != nil {
+		pickupBranchID = &pickupBranch.ID
+		if req.PickupLocation == "" {
+			req.PickupLocation = pickupBranch.Name
+		}
+	}
+	
 	var queuePosition int64
 	h.db.Model(&models.Reservation{}).
 		Where("book_id = ? AND status = ?", book.ID, models.ReservationStatusPending).
 		Count(&queuePosition)
 	
 	reservation := models.Reservation{
-		ID:            uuid.New(),
-		BookID:        book.ID,
-		MemberID:      member.ID,
-		Status:        models.ReservationStatusPending,
-		QueuePosition: int(queuePosition) + 1,
+		ID:             uuid.New(),
+		BookID:         book.ID,
+		MemberID:       member.ID,
+		Status:         models.ReservationStatusPending,
+		QueuePosition:  int(queuePosition) + 1,
+		PickupLocation: req.PickupLocation,
+		PickupBranchID: pickupBranchID,
 	}
 	
 	if err := h.db.Create(&reservation).Error; err != nil {
@@ -344,6 +761,211 @@ func (h *BookHandler) ReserveBook(c *gin.Context) {
 	})
 }
 
+func (h *BookHandler) LookupMetadata(c *gin.Context) {
+	normalizedISBN, err := isbn.Normalize(c.Query("isbn"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ISBN: " + err.Error()})
+		return
+	}
+	
+	result, err := h.metadata.Lookup(c.Request.Context(), normalizedISBN)
+	if err != nil {
+		c.JSON(metadataErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": result,
+	})
+}
+
+func (h *BookHandler) PreviewMetadata(c *gin.Context) {
+	var req struct {
+		ISBN      string             `json:"isbn"`
+		BookID    string             `json:"book_id"`
+		BookData  models.BookRequest `json:"book_data"`
+		Overwrite bool               `json:"overwrite"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	current := req.BookData
+	if req.BookID != "" {
+		var book models.Book
+		if err := h.db.First(&book, "id = ?", req.BookID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+			return
+		}
+		current = bookToRequest(book)
+	}
+	
+	if req.ISBN == "" {
+		req.ISBN = current.ISBN
+	}
+	
+	normalizedISBN, err := isbn.Normalize(req.ISBN)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ISBN: " + err.Error()})
+		return
+	}
+	
+	result, err := h.metadata.Lookup(c.Request.Context(), normalizedISBN)
+	if err != nil {
+		c.JSON(metadataErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	merged, changes := metadata.Preview(current, result, req.Overwrite)
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": gin.H{
+			"book_data": merged,
+			"changes":   changes,
+			"sources":   result.Sources,
+		},
+	})
+}
+
+func bookToRequest(book models.Book) models.BookRequest {
+	return models.BookRequest{
+		Title:           book.Title,
+		Author:          book.Author,
+		ISBN:            book.ISBN,
+		Barcode:         book.Barcode,
+		Publisher:       book.Publisher,
+		PublishDate:     book.PublishDate,
+		Category:        book.Category,
+		Language:        book.Language,
+		Description:     book.Description,
+		CoverImage:      book.CoverImage,
+		TotalCopies:     book.TotalCopies,
+		AvailableCopies: book.AvailableCopies,
+		ReplacementCost: book.ReplacementCost,
+		Location:        book.Location,
+		CallNumber:      book.CallNumber,
+		Classification:  book.Classification,
+		Tags:            book.Tags,
+	}
+}
+
+func metadataErrorStatus(err error) int {
+	if errors.Is(err, metadata.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadGateway
+}
+
+func (h *BookHandler) UploadCover(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.config.Covers.MaxUploadSize+1<<20)
+	
+	fileHeader, err := c.FormFile("cover")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cover image required"})
+		return
+	}
+	
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read cover image"})
+		return
+	}
+	defer file.Close()
+	
+	book, err := h.covers.Upload(c.Request.Context(), c.PostForm("book_id"), file)
+	if err != nil {
+		c.JSON(coverErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	response := h.bookToResponse(*book)
+	c.JSON(http.StatusOK, gin.H{
+		"message": gin.H{
+			"book": response,
+			"covers": gin.H{
+				services.CoverLarge:  h.covers.CoverURL(response.ID, book.CoverVersion, services.CoverLarge),
+				services.CoverMedium: h.covers.CoverURL(response.ID, book.CoverVersion, services.CoverMedium),
+				services.CoverSmall:  h.covers.CoverURL(response.ID, book.CoverVersion, services.CoverSmall),
+			},
+		},
+	})
+}
+
+func (h *BookHandler) GetCover(c *gin.Context) {
+	version := c.Query("v")
+	
+	body, info, err := h.covers.Open(c.Request.Context(), c.Query("book_id"), version, c.Query("size"))
+	if err != nil {
+		c.JSON(coverErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer body.Close()
+	
+	cacheControl := "public, max-age=300"
+	if version != "" {
+		cacheControl = "public, max-age=" + strconv.Itoa(int(h.config.Covers.CacheMaxAge.Seconds())) + ", immutable"
+	}
+	
+	c.Header("Cache-Control", cacheControl)
+	if info.ETag != "" {
+		c.Header("ETag", info.ETag)
+		if c.GetHeader("If-None-Match") == info.ETag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	
+	if !info.LastModified.IsZero() {
+		c.Header("Last-Modified", info.LastModified.UTC().Format(http.TimeFormat))
+	}
+	
+	c.DataFromReader(http.StatusOK, info.Size, "image/jpeg", body, nil)
+}
+
+func coverErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrBookNotFound),
+		errors.Is(err, services.ErrCoverNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrCoverTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrCoverUnsupported):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, services.ErrCoverUnreadable),
+		errors.Is(err, services.ErrCoverDimensionLimit),
+		errors.Is(err, services.ErrInvalidCoverSize):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *BookHandler) GetISBNReport(c *gin.Context) {
+	report, err := services.NormalizeISBNs(h.db, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build ISBN report"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": report,
+	})
+}
+
+func (h *BookHandler) NormalizeISBNs(c *gin.Context) {
+	report, err := services.NormalizeISBNs(h.db, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to normalize ISBNs"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": report,
+	})
+}
+
 func (h *BookHandler) GetBookStatistics(c *gin.Context) {
 	var stats models.BookStatistics
 	
@@ -363,25 +985,61 @@ func (h *BookHandler) GetBookStatistics(c *gin.Context) {
 }
 
 func (h *BookHandler) bookToResponse(book models.Book) models.BookResponse {
+	return h.booksToResponses([]models.Book{book})[0]
+}
+
+// booksToResponses converts a page of books, loading their authority links
+// for the whole page at once instead of once per book.
+func (h *BookHandler) booksToResponses(books []models.Book) []models.BookResponse {
+	ids := make([]uuid.UUID, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	authorities, _ := h.authorities.BooksAuthorities(ids)
+	
+	responses := make([]models.BookResponse, len(books))
+	for i, book := range books {
+		responses[i] = h.bookResponse(book, authorities[book.ID])
+	}
+	return responses
+}
+
+func (h *BookHandler) bookResponse(book models.Book, authorities *models.BookRequest) models.BookResponse {
 	response := models.BookResponse{
 		ID:              book.ID.String(),
 		Title:           book.Title,
 		Author:          book.Author,
 		ISBN:            book.ISBN,
+		ISBN10:          isbn.To10(book.ISBN),
+		Barcode:         book.Barcode,
 		Publisher:       book.Publisher,
 		PublishDate:     book.PublishDate,
 		Category:        book.Category,
+		Language:        book.Language,
 		Description:     book.Description,
 		CoverImage:      book.CoverImage,
 		TotalCopies:     book.TotalCopies,
 		AvailableCopies: book.AvailableCopies,
+		ReplacementCost: book.ReplacementCost,
 		Location:        book.Location,
+		CallNumber:      book.CallNumber,
+		Classification:  book.Classification,
+		ShelfOrder:      book.CallNumberSort,
 		Status:          string(book.Status),
 		Tags:            book.Tags,
+		ItemType:        book.ItemType,
+		Attributes:      book.Attributes,
 		IsAvailable:     book.IsAvailable(),
 		CreatedAt:       book.CreatedAt,
 	}
 	
+	if authorities != nil {
+		response.Contributors = authorities.Contributors
+		response.Publishers = authorities.Publishers
+		response.Series = authorities.Series
+		response.Subjects = authorities.Subjects
+	}
+	
 	var currentLoan models.Loan
 	if err := h.db.Where("book_id = ? AND status = ?", book.ID, models.LoanStatusActive).
 		First(&currentLoan).Error; err == nil {
@@ -401,4 +1059,11 @@ func (h *BookHandler) bookToResponse(book models.Book) models.BookResponse {
 	}
 	
 	return response
-}
\ No newline at end of file
+}
+
+func normalizeItemISBN(itemType models.ItemType, value string) (string, error) {
+	if itemType != models.ItemTypeBook && strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	return isbn.Normalize(value)
+}
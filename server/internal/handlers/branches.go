@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BranchHandler struct {
+	db          *gorm.DB
+	config      *config.Config
+	circulation *services.CirculationService
+}
+
+func NewBranchHandler(db *gorm.DB, cfg *config.Config) *BranchHandler {
+	return &BranchHandler{
+		db:          db,
+		config:      cfg,
+		circulation: services.NewCirculationService(db, cfg),
+	}
+}
+
+func (h *BranchHandler) GetBranches(c *gin.Context) {
+	query := h.db.Model(&models.Branch{})
+	if c.Query("include_inactive") != "true" {
+		query = query.Where("is_active = ?", true)
+	}
+	
+	var branches []models.Branch
+	if err := query.Order("is_default DESC").Order("name ASC").Find(&branches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch branches"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": branches,
+	})
+}
+
+func (h *BranchHandler) CreateBranch(c *gin.Context) {
+	var req models.BranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	branch := models.Branch{IsActive: true}
+	applyBranchRequest(&branch, req)
+	
+	var existing int64
+	h.db.Unscoped().Model(&models.Branch{}).Where("code = ?", branch.Code).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": services.ErrBranchExists.Error()})
+		return
+	}
+	
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&branch).Error; err != nil {
+			return err
+		}
+		if req.IsActive != nil && !*req.IsActive {
+			if err := tx.Model(&branch).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+		return clearOtherDefaults(tx, &branch)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create branch"})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": branch,
+	})
+}
+
+func (h *BranchHandler) UpdateBranch(c *gin.Context) {
+	var req struct {
+		BranchID   string               `json:"branch_id" binding:"required"`
+		BranchData models.BranchRequest `json:"branch_data"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	var branch models.Branch
+	if err := h.db.First(&branch, "id = ?", req.BranchID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Branch not found"})
+		return
+	}
+	
+	if branch.IsDefault && (!req.BranchData.IsDefault || (req.BranchData.IsActive != nil && !*req.BranchData.IsActive)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Mark another branch as default before changing the default branch"})
+		return
+	}
+	
+	applyBranchRequest(&branch, req.BranchData)
+	
+	var existing int64
+	h.db.Unscoped().Model(&models.Branch{}).Where("code = ? AND id <> ?", branch.Code, branch.ID).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": services.ErrBranchExists.Error()})
+		return
+	}
+	
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&branch).Error; err != nil {
+			return err
+		}
+		return clearOtherDefaults(tx, &branch)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update branch"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": branch,
+	})
+}
+
+func (h *BranchHandler) GetHoldings(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Query("book_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		return
+	}
+	
+	holdings, err := h.circulation.Holdings(bookID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch holdings"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": holdings,
+	})
+}
+
+func (h *BranchHandler) UpdateHolding(c *gin.Context) {
+	var req models.HoldingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	bookID, err := uuid.Parse(req.BookID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		return
+	}
+	
+	branchID, err := uuid.Parse(req.BranchID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+		return
+	}
+	
+	holding, err := h.circulation.UpdateHolding(bookID, branchID, req.TotalCopies)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": holding,
+	})
+}
+
+func (h *BranchHandler) RequestTransfer(c *gin.Context) {
+	var req models.TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	bookID, err := uuid.Parse(req.BookID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	transfer, err := h.circulation.RequestTransfer(bookID, req.FromBranchID, req.ToBranchID, user.ID, req.Notes)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": transfer,
+	})
+}
+
+func (h *BranchHandler) ShipTransfer(c *gin.Context) {
+	transferID, ok := bindTransferID(c)
+	if !ok {
+		return
+	}
+	
+	transfer, err := h.circulation.ShipTransfer(transferID)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": transfer,
+	})
+}
+
+func (h *BranchHandler) ReceiveTransfer(c *gin.Context) {
+	transferID, ok := bindTransferID(c)
+	if !ok {
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	transfer, hold, err := h.circulation.ReceiveTransfer(transferID, user.ID)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	response := gin.H{
+		"transfer": transfer,
+	}
+	
+	if hold != nil {
+		response["hold"] = gin.H{
+			"reservation_id":  hold.ID.String(),
+			"member_id":       hold.MemberID.String(),
+			"status":          hold.Status,
+			"pickup_location": hold.PickupLocation,
+			"pickup_deadline": hold.PickupDeadline,
+		}
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": response,
+	})
+}
+
+func (h *BranchHandler) CancelTransfer(c *gin.Context) {
+	transferID, ok := bindTransferID(c)
+	if !ok {
+		return
+	}
+	
+	transfer, err := h.circulation.CancelTransfer(transferID)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": transfer,
+	})
+}
+
+func (h *BranchHandler) GetTransfers(c *gin.Context) {
+	transfers, err := h.circulation.Transfers(c.Query("branch_id"), c.Query("direction"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transfers"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": transfers,
+	})
+}
+
+func bindTransferID(c *gin.Context) (uuid.UUID, bool) {
+	var req struct {
+		TransferID string `json:"transfer_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return uuid.Nil, false
+	}
+	
+	transferID, err := uuid.Parse(req.TransferID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transfer ID"})
+		return uuid.Nil, false
+	}
+	return transferID, true
+}
+
+func applyBranchRequest(branch *models.Branch, req models.BranchRequest) {
+	branch.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	branch.Name = strings.TrimSpace(req.Name)
+	branch.Address = req.Address
+	branch.Phone = req.Phone
+	branch.Email = req.Email
+	branch.IsDefault = req.IsDefault
+	if req.IsActive != nil {
+		branch.IsActive = *req.IsActive
+	}
+}
+
+func clearOtherDefaults(tx *gorm.DB, branch *models.Branch) error {
+	if !branch.IsDefault {
+		return nil
+	}
+	return tx.Model(&models.Branch{}).Where("id <> ? AND is_default = ?", branch.ID, true).Update("is_default", false).Error
+}
+
+func uuidString(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
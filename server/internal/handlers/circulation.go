@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CirculationHandler struct {
+	db          *gorm.DB
+	config      *config.Config
+	circulation *services.CirculationService
+}
+
+func NewCirculationHandler(db *gorm.DB, cfg *config.Config) *CirculationHandler {
+	return &CirculationHandler{
+		db:          db,
+		config:      cfg,
+		circulation: services.NewCirculationService(db, cfg),
+	}
+}
+
+func (h *CirculationHandler) OpenSession(c *gin.Context) {
+	var req models.OpenDeskSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	session, alerts, err := h.circulation.OpenDeskSession(req.MembershipID, req.DeskLocation, req.BranchID, user.ID)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	response := gin.H{
+		"session_id":    session.ID.String(),
+		"desk_location": session.DeskLocation,
+		"branch_id":     session.BranchID,
+		"opened_at":     session.OpenedAt,
+		"alerts":        alerts,
+	}
+	
+	if member := session.Member; member != nil {
+		response["patron"] = models.MemberResponse{
+			ID:                 member.ID.String(),
+			MembershipID:       member.MembershipID,
+			Name:               member.User.FullName,
+			Email:              member.User.Email,
+			Phone:              member.User.Phone,
+			MembershipType:     string(member.MembershipType),
+			ExpiryDate:         member.ExpiryDate,
+			MaxBooksAllowed:    member.MaxBooksAllowed,
+			CurrentBooksIssued: member.CurrentBooksIssued,
+			OutstandingFines:   member.GetOutstandingFines(),
+			IsActive:           member.IsActive,
+			IsExpired:          member.IsExpired(),
+		}
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": response,
+	})
+}
+
+func (h *CirculationHandler) CheckoutItems(c *gin.Context) {
+	var req models.DeskScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	sessionID, err := uuid.Parse(req.SessionID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	results, err := h.circulation.DeskCheckout(sessionID, req.Items, user.ID)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": results,
+	})
+}
+
+func (h *CirculationHandler) CheckinItems(c *gin.Context) {
+	var req models.DeskScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	sessionID, err := uuid.Parse(req.SessionID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+	
+	results, err := h.circulation.DeskCheckin(sessionID, req.Items)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": results,
+	})
+}
+
+func (h *CirculationHandler) CloseSession(c *gin.Context) {
+	var req models.DeskSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	sessionID, err := uuid.Parse(req.SessionID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+	
+	slip, err := h.circulation.CloseDeskSession(sessionID)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": slip,
+	})
+}
+
+func (h *CirculationHandler) GetSlip(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Query("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+	
+	slip, err := h.circulation.DeskSlip(sessionID)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	if c.Query("format") == "text" {
+		c.String(http.StatusOK, slip.Text)
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": slip,
+	})
+}
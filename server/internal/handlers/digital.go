@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DigitalHandler struct {
+	db      *gorm.DB
+	config  *config.Config
+	digital *services.DigitalService
+}
+
+func NewDigitalHandler(db *gorm.DB, cfg *config.Config) *DigitalHandler {
+	return &DigitalHandler{
+		db:      db,
+		config:  cfg,
+		digital: services.NewDigitalService(db, storage.NewFromConfig(cfg.Storage), cfg),
+	}
+}
+
+func (h *DigitalHandler) GetDigitalItems(c *gin.Context) {
+	items, err := h.digital.Items(c.Query("search"), c.Query("format"), c.Query("include_inactive") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch digital items"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": items,
+	})
+}
+
+func (h *DigitalHandler) CreateDigitalItem(c *gin.Context) {
+	var req models.DigitalItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	item, err := h.digital.CreateItem(req)
+	if err != nil {
+		c.JSON(digitalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": item,
+	})
+}
+
+func (h *DigitalHandler) UpdateDigitalItem(c *gin.Context) {
+	var req struct {
+		ID string `json:"id" binding:"required"`
+		models.DigitalItemRequest
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	item, err := h.digital.UpdateItem(req.ID, req.DigitalItemRequest)
+	if err != nil {
+		c.JSON(digitalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": item,
+	})
+}
+
+func (h *DigitalHandler) UploadDigitalFile(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.config.Digital.MaxUploadSize+1<<20)
+	
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File required"})
+		return
+	}
+	
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+	
+	item, err := h.digital.Upload(c.Request.Context(), c.PostForm("digital_item_id"), fileHeader.Filename, file)
+	if err != nil {
+		c.JSON(digitalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": item,
+	})
+}
+
+func (h *DigitalHandler) GetDigitalCheckouts(c *gin.Context) {
+	checkouts, err := h.digital.Checkouts(c.Query("digital_item_id"), c.Query("member_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch digital checkouts"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": checkouts,
+	})
+}
+
+func (h *DigitalHandler) GetDigitalHolds(c *gin.Context) {
+	holds, err := h.digital.Holds(c.Query("digital_item_id"), c.Query("member_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch digital holds"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": holds,
+	})
+}
+
+func (h *DigitalHandler) CheckoutDigital(c *gin.Context) {
+	var req struct {
+		DigitalItemID string `json:"digital_item_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	member, ok := h.currentMember(c)
+	if !ok {
+		return
+	}
+	
+	checkout, err := h.digital.Checkout(req.DigitalItemID, member.ID)
+	if err != nil {
+		c.JSON(digitalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": checkout,
+	})
+}
+
+func (h *DigitalHandler) ReturnDigital(c *gin.Context) {
+	var req struct {
+		CheckoutID string `json:"checkout_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	memberID, ok := h.actingMemberID(c)
+	if !ok {
+		return
+	}
+	
+	checkout, err := h.digital.Return(req.CheckoutID, memberID)
+	if err != nil {
+		c.JSON(digitalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": checkout,
+	})
+}
+
+func (h *DigitalHandler) GetMyDigitalCheckouts(c *gin.Context) {
+	member, ok := h.currentMember(c)
+	if !ok {
+		return
+	}
+	
+	checkouts, err := h.digital.Checkouts("", member.ID.String(), c.DefaultQuery("status", string(models.DigitalCheckoutActive)))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch digital checkouts"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": checkouts,
+	})
+}
+
+func (h *DigitalHandler) PlaceDigitalHold(c *gin.Context) {
+	var req struct {
+		DigitalItemID string `json:"digital_item_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	member, ok := h.currentMember(c)
+	if !ok {
+		return
+	}
+	
+	hold, err := h.digital.PlaceHold(req.DigitalItemID, member.ID)
+	if err != nil {
+		c.JSON(digitalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": hold,
+	})
+}
+
+func (h *DigitalHandler) CancelDigitalHold(c *gin.Context) {
+	var req struct {
+		HoldID string `json:"hold_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	memberID, ok := h.actingMemberID(c)
+	if !ok {
+		return
+	}
+	
+	hold, err := h.digital.CancelHold(req.HoldID, memberID)
+	if err != nil {
+		c.JSON(digitalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": hold,
+	})
+}
+
+func (h *DigitalHandler) GetMyDigitalHolds(c *gin.Context) {
+	member, ok := h.currentMember(c)
+	if !ok {
+		return
+	}
+	
+	holds, err := h.digital.Holds("", member.ID.String(), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch digital holds"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": holds,
+	})
+}
+
+func (h *DigitalHandler) GetDownloadLink(c *gin.Context) {
+	member, ok := h.currentMember(c)
+	if !ok {
+		return
+	}
+	
+	link, err := h.digital.DownloadLink(c.Query("checkout_id"), member.ID)
+	if err != nil {
+		c.JSON(digitalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": link,
+	})
+}
+
+func (h *DigitalHandler) Download(c *gin.Context) {
+	body, info, item, err := h.digital.Open(c.Request.Context(), c.Query("checkout_id"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		c.JSON(digitalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer body.Close()
+	
+	contentType := item.ContentType
+	if contentType == "" {
+		contentType = info.ContentType
+	}
+	
+	c.DataFromReader(http.StatusOK, info.Size, contentType, body, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", item.FileName),
+		"Cache-Control":       "private, no-store",
+	})
+}
+
+func (h *DigitalHandler) currentMember(c *gin.Context) (*models.Member, bool) {
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return nil, false
+	}
+	
+	var member models.Member
+	if err := h.db.Where("user_id = ?", user.ID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member profile not found"})
+		return nil, false
+	}
+	return &member, true
+}
+
+func (h *DigitalHandler) actingMemberID(c *gin.Context) (*uuid.UUID, bool) {
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return nil, false
+	}
+	if user.Role != models.RoleMember {
+		return nil, true
+	}
+	
+	member, ok := h.currentMember(c)
+	if !ok {
+		return nil, false
+	}
+	return &member.ID, true
+}
+
+func digitalErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrDigitalItemNotFound),
+		errors.Is(err, services.ErrDigitalCheckoutNotFound),
+		errors.Is(err, services.ErrDigitalHoldNotFound),
+		errors.Is(err, services.ErrDigitalNoFile),
+		errors.Is(err, services.ErrBookNotFound),
+		errors.Is(err, services.ErrMemberNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrDigitalCheckedOut),
+		errors.Is(err, services.ErrDigitalNoLicense),
+		errors.Is(err, services.ErrDigitalLicenseAvailable),
+		errors.Is(err, services.ErrDigitalHoldExists),
+		errors.Is(err, services.ErrDigitalCheckoutEnded),
+		errors.Is(err, services.ErrDigitalHoldClosed):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrDigitalFormat):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrDigitalFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrDigitalFileUnsupported):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, storage.ErrLinkInvalid),
+		errors.Is(err, storage.ErrLinkExpired),
+		errors.Is(err, services.ErrDigitalItemInactive),
+		errors.Is(err, services.ErrDigitalLimitReached),
+		errors.Is(err, services.ErrDigitalNotOwner),
+		errors.Is(err, services.ErrMemberInactive):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
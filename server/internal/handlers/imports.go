@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/marc"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type ImportHandler struct {
+	db       *gorm.DB
+	config   *config.Config
+	importer *services.ImportService
+}
+
+func NewImportHandler(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *ImportHandler {
+	return &ImportHandler{
+		db:       db,
+		config:   cfg,
+		importer: services.NewImportService(db, redisClient, cfg),
+	}
+}
+
+func (h *ImportHandler) ImportBooks(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.config.Import.MaxFileSize)
+	
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Import file required"})
+		return
+	}
+	
+	format := strings.ToLower(c.PostForm("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	}
+	
+	switch format {
+	case "mrc":
+		format = "marc"
+	case "xml":
+		format = "marcxml"
+	}
+	
+	mapping := map[string]string{}
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Column mapping must be a JSON object"})
+			return
+		}
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read import file"})
+		return
+	}
+	defer file.Close()
+	
+	records, err := h.importer.Parse(format, file, mapping)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	job, background, err := h.importer.Start(records, services.ImportOptions{
+		Format:          format,
+		FileName:        fileHeader.Filename,
+		DuplicatePolicy: models.DuplicatePolicy(c.DefaultPostForm("duplicate_policy", string(models.DuplicateSkip))),
+		DryRun:          c.PostForm("dry_run") == "true",
+		StartedByID:     user.ID,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDuplicatePolicy) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start import"})
+		return
+	}
+	
+	if background {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": importJobToResponse(*job),
+		})
+		return
+	}
+	
+	rows, _, err := h.importer.JobRows(job.ID, "", len(records), 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch import report"})
+		return
+	}
+	job.Rows = rows
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": importJobToResponse(*job),
+	})
+}
+
+func (h *ImportHandler) GetImportJob(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Query("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+	
+	job, err := h.importer.Job(jobID)
+	if err != nil {
+		if errors.Is(err, services.ErrImportJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Import job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch import job"})
+		return
+	}
+	
+	page, limit, offset := utils.GetPaginationParams(c, h.config.Pagination.DefaultPageSize, h.config.Pagination.MaxPageSize)
+	
+	rows, total, err := h.importer.JobRows(job.ID, c.Query("action"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch import report"})
+		return
+	}
+	job.Rows = rows
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": gin.H{
+			"job":        importJobToResponse(*job),
+			"pagination": utils.CalculatePagination(page, limit, total),
+		},
+	})
+}
+
+func (h *ImportHandler) ExportMARC(c *gin.Context) {
+	format := c.DefaultQuery("format", "marc")
+	if format != "marc" && format != "marcxml" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format must be marc or marcxml"})
+		return
+	}
+	
+	query := h.db.Model(&models.Book{})
+	
+	if bookIDs := c.QueryArray("book_id"); len(bookIDs) > 0 {
+		query = query.Where("id IN ?", bookIDs)
+	}
+	
+	if category := c.Query("category"); category != "" {
+		query = query.Where("category = ?", category)
+	}
+	
+	var write func(*marc.Record) error
+	var finish func() error
+	
+	if format == "marcxml" {
+		c.Header("Content-Type", "application/marcxml+xml")
+		c.Header("Content-Disposition", `attachment; filename="catalog.xml"`)
+		writer := marc.NewXMLWriter(c.Writer)
+		write, finish = writer.Write, writer.Close
+	} else {
+		c.Header("Content-Type", "application/marc")
+		c.Header("Content-Disposition", `attachment; filename="catalog.mrc"`)
+		writer := marc.NewWriter(c.Writer)
+		write, finish = writer.Write, func() error { return nil }
+	}
+	
+	c.Status(http.StatusOK)
+	
+	var books []models.Book
+	query.FindInBatches(&books, 100, func(tx *gorm.DB, batch int) error {
+		for _, book := range books {
+			if err := write(marc.FromBook(book)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	
+	finish()
+}
+
+func importJobToResponse(job models.ImportJob) models.ImportJobResponse {
+	return models.ImportJobResponse{
+		ImportJob: job,
+		Progress:  job.Progress(),
+	}
+}
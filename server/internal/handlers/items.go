@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ItemHandler struct {
+	db     *gorm.DB
+	config *config.Config
+	rooms  *services.RoomService
+}
+
+func NewItemHandler(db *gorm.DB, cfg *config.Config) *ItemHandler {
+	return &ItemHandler{
+		db:     db,
+		config: cfg,
+		rooms:  services.NewRoomService(db, cfg),
+	}
+}
+
+func (h *ItemHandler) GetItemTypes(c *gin.Context) {
+	types, err := services.ItemRules(h.db, h.config)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch item types"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": types,
+	})
+}
+
+func (h *ItemHandler) UpdateItemTypeRule(c *gin.Context) {
+	var req models.ItemTypeRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	rule, err := services.SaveItemRule(h.db, h.config, req)
+	if err != nil {
+		c.JSON(itemErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": rule,
+	})
+}
+
+func (h *ItemHandler) GetRooms(c *gin.Context) {
+	minCapacity, _ := strconv.Atoi(c.Query("min_capacity"))
+	
+	rooms, err := h.rooms.Rooms(c.Query("branch_id"), minCapacity)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rooms"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": rooms,
+	})
+}
+
+func (h *ItemHandler) GetRoomCalendar(c *gin.Context) {
+	from, ok := parseDateQuery(c, "from")
+	if !ok {
+		return
+	}
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "7"))
+	
+	calendars, err := h.rooms.Calendar(c.Query("room_id"), c.Query("branch_id"), from, days)
+	if err != nil {
+		c.JSON(itemErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": calendars,
+	})
+}
+
+func (h *ItemHandler) GetRoomBookings(c *gin.Context) {
+	from, ok := parseDateQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseDateQuery(c, "to")
+	if !ok {
+		return
+	}
+	
+	bookings, err := h.rooms.Bookings(c.Query("room_id"), c.Query("member_id"), c.Query("status"), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch room bookings"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": bookings,
+	})
+}
+
+func (h *ItemHandler) GetMyRoomBookings(c *gin.Context) {
+	member, ok := h.currentMember(c)
+	if !ok {
+		return
+	}
+	
+	bookings, err := h.rooms.Bookings("", member.ID.String(), c.Query("status"), time.Time{}, time.Time{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch room bookings"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": bookings,
+	})
+}
+
+func (h *ItemHandler) BookRoom(c *gin.Context) {
+	var req models.RoomBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	if user.Role == models.RoleMember {
+		member, ok := h.currentMember(c)
+		if !ok {
+			return
+		}
+		req.MemberID = member.ID.String()
+		req.MembershipID = ""
+	} else if req.MemberID == "" && req.MembershipID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Member ID or membership ID required"})
+		return
+	}
+	
+	booking, err := h.rooms.Book(req, user.ID)
+	if err != nil {
+		var conflict *services.BookingConflictError
+		if errors.As(err, &conflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "conflicts": conflict.Bookings})
+			return
+		}
+		c.JSON(itemErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": booking,
+	})
+}
+
+func (h *ItemHandler) CancelRoomBooking(c *gin.Context) {
+	var req struct {
+		BookingID string `json:"booking_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	var memberID *uuid.UUID
+	if user.Role == models.RoleMember {
+		member, ok := h.currentMember(c)
+		if !ok {
+			return
+		}
+		memberID = &member.ID
+	}
+	
+	booking, err := h.rooms.Cancel(req.BookingID, memberID)
+	if err != nil {
+		c.JSON(itemErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": booking,
+	})
+}
+
+func (h *ItemHandler) currentMember(c *gin.Context) (*models.Member, bool) {
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return nil, false
+	}
+	
+	var member models.Member
+	if err := h.db.Where("user_id = ?", user.ID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member profile not found"})
+		return nil, false
+	}
+	return &member, true
+}
+
+func parseDateQuery(c *gin.Context, key string) (time.Time, bool) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, true
+	}
+	
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + key + " date, use YYYY-MM-DD"})
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+func itemErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrRoomNotFound),
+		errors.Is(err, services.ErrBookingNotFound),
+		errors.Is(err, services.ErrMemberNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrBookingCancelled),
+		errors.Is(err, services.ErrBookingEnded):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrItemType),
+		errors.Is(err, services.ErrItemAttribute),
+		errors.Is(err, services.ErrBookingTime),
+		errors.Is(err, services.ErrBookingTooLong),
+		errors.Is(err, services.ErrBookingTooFar),
+		errors.Is(err, services.ErrBookingHours),
+		errors.Is(err, services.ErrBookingCapacity):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrMemberInactive),
+		errors.Is(err, services.ErrBookingNotOwner):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,386 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LoanHandler struct {
+	db          *gorm.DB
+	config      *config.Config
+	circulation *services.CirculationService
+}
+
+func NewLoanHandler(db *gorm.DB, cfg *config.Config) *LoanHandler {
+	return &LoanHandler{
+		db:          db,
+		config:      cfg,
+		circulation: services.NewCirculationService(db, cfg),
+	}
+}
+
+func (h *LoanHandler) CreateLoan(c *gin.Context) {
+	var req struct {
+		LoanData models.LoanRequest `json:"loan_data"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	bookID, err := uuid.Parse(req.LoanData.BookID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		return
+	}
+	
+	memberID, err := uuid.Parse(req.LoanData.MemberID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid member ID"})
+		return
+	}
+	
+	var branchID uuid.UUID
+	if req.LoanData.BranchID != "" {
+		if branchID, err = uuid.Parse(req.LoanData.BranchID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+			return
+		}
+	}
+	
+	var dueDate time.Time
+	if req.LoanData.DueDate != "" {
+		dueDate, err = time.Parse("2006-01-02", req.LoanData.DueDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid due date"})
+			return
+		}
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	result, err := h.circulation.Checkout(bookID, memberID, user.ID, branchID, strings.TrimSpace(req.LoanData.CopyBarcode), dueDate, req.LoanData.Notes)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": h.loanToResponse(*result.Loan),
+	})
+}
+
+func (h *LoanHandler) ReturnBook(c *gin.Context) {
+	var req models.ReturnBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	loanID, err := uuid.Parse(req.LoanID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid loan ID"})
+		return
+	}
+	
+	var branchID uuid.UUID
+	if req.BranchID != "" {
+		if branchID, err = uuid.Parse(req.BranchID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+			return
+		}
+	}
+	
+	result, err := h.circulation.Return(loanID, branchID, req.ActualReturnDate)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	response := gin.H{
+		"loan": h.loanToResponse(*result.Loan),
+	}
+	
+	if result.Credit > 0 {
+		response["credit"] = result.Credit
+	}
+	
+	if result.Transfer != nil {
+		response["transfer"] = gin.H{
+			"transfer_id":    result.Transfer.ID.String(),
+			"reason":         result.Transfer.Reason,
+			"from_branch_id": result.Transfer.FromBranchID.String(),
+			"to_branch_id":   result.Transfer.ToBranchID.String(),
+		}
+	}
+	
+	if result.TrappedHold != nil {
+		response["hold"] = gin.H{
+			"reservation_id":  result.TrappedHold.ID.String(),
+			"member_id":       result.TrappedHold.MemberID.String(),
+			"pickup_location": result.TrappedHold.PickupLocation,
+			"pickup_deadline": result.TrappedHold.PickupDeadline,
+		}
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": response,
+	})
+}
+
+func (h *LoanHandler) DeclareLost(c *gin.Context) {
+	var req models.DeclareLostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	loanID, err := uuid.Parse(req.LoanID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid loan ID"})
+		return
+	}
+	
+	loan, err := h.circulation.DeclareLost(loanID, req.Notes)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": h.loanToResponse(*loan),
+	})
+}
+
+func (h *LoanHandler) RenewLoan(c *gin.Context) {
+	var req models.RenewLoanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	loanID, err := uuid.Parse(req.LoanID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid loan ID"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	if !user.IsLibrarian() {
+		if req.Override || !req.NewReturnDate.IsZero() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Librarian access required to override renewal rules"})
+			return
+		}
+		
+		var count int64
+		h.db.Model(&models.Loan{}).
+			Joins("JOIN members ON members.id = loans.member_id").
+			Where("loans.id = ? AND members.user_id = ?", loanID, user.ID).
+			Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+			return
+		}
+	}
+	
+	loan, err := h.circulation.Renew(loanID, req.NewReturnDate, req.Override)
+	if err != nil {
+		var denied *services.RenewalDeniedError
+		if errors.As(err, &denied) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":  err.Error(),
+				"reason": denied.Reason,
+			})
+			return
+		}
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": h.loanToResponse(*loan),
+	})
+}
+
+var loanSortColumns = map[string]string{
+	"loan_date":  "loans.loan_date",
+	"due_date":   "loans.due_date",
+	"created_at": "loans.created_at",
+}
+
+func (h *LoanHandler) GetLoans(c *gin.Context) {
+	params, err := utils.GetListParams(c, loanSortColumns, "loan_date", "desc", h.config.Pagination.DefaultPageSize, h.config.Pagination.MaxPageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	query := h.db.Model(&models.Loan{})
+	
+	if user.IsLibrarian() {
+		if memberID := c.Query("member_id"); memberID != "" {
+			query = query.Where("loans.member_id = ?", memberID)
+		}
+	} else {
+		query = query.Joins("JOIN members ON members.id = loans.member_id").
+			Where("members.user_id = ?", user.ID)
+	}
+	
+	if bookID := c.Query("book_id"); bookID != "" {
+		query = query.Where("loans.book_id = ?", bookID)
+	}
+	
+	if status := c.Query("status"); status != "" {
+		query = query.Where("loans.status = ?", status)
+	}
+	
+	if c.Query("overdue") == "true" {
+		query = query.Where("loans.status = ? AND loans.due_date < ?", models.LoanStatusActive, time.Now())
+	}
+	
+	var total int64
+	query.Count(&total)
+	
+	var loans []models.Loan
+	if err := params.Apply(query, "loans.id").Preload("Book").Find(&loans).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch loans"})
+		return
+	}
+	
+	denials := h.circulation.RenewalDenials(loans)
+	loanResponses := make([]models.LoanResponse, len(loans))
+	for i, loan := range loans {
+		loanResponses[i] = loanResponse(loan, denials[loan.ID])
+	}
+	
+	loanResponses, nextCursor := utils.TrimPage(params, loanResponses)
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": gin.H{
+			"loans":      loanResponses,
+			"pagination": params.Pagination(total, nextCursor),
+		},
+	})
+}
+
+func (h *LoanHandler) loanToResponse(loan models.Loan) models.LoanResponse {
+	return loanResponse(loan, h.circulation.RenewalDenial(&loan))
+}
+
+func loanResponse(loan models.Loan, denial models.RenewalDenial) models.LoanResponse {
+	response := models.LoanResponse{
+		ID:               loan.ID.String(),
+		BookID:           loan.BookID.String(),
+		CopyBarcode:      loan.CopyBarcode,
+		MemberID:         loan.MemberID.String(),
+		BranchID:         uuidString(loan.BranchID),
+		OwningBranchID:   uuidString(loan.OwningBranchID),
+		ReturnBranchID:   uuidString(loan.ReturnBranchID),
+		LoanDate:         loan.LoanDate,
+		DueDate:          loan.DueDate,
+		ReturnDate:       loan.ReturnDate,
+		ActualReturnDate: loan.ActualReturnDate,
+		Status:           string(loan.Status),
+		IsOverdue:        loan.IsOverdue(),
+		RenewalCount:     loan.RenewalCount,
+		MaxRenewals:      loan.MaxRenewals,
+		AutoRenewed:      loan.AutoRenewed,
+		FineAmount:       loan.FineAmount,
+		FinePaid:         loan.FinePaid,
+		LostDate:         loan.LostDate,
+		FoundDate:        loan.FoundDate,
+		ReplacementFee:   loan.ReplacementFee,
+		ProcessingFee:    loan.ProcessingFee,
+		Notes:            loan.Notes,
+		CreatedAt:        loan.CreatedAt,
+	}
+	
+	if denial != "" {
+		response.RenewalDenial = string(denial)
+	} else {
+		response.CanRenew = true
+	}
+	
+	if loan.Book.ID != uuid.Nil {
+		response.Book = &models.BookResponse{
+			ID:              loan.Book.ID.String(),
+			Title:           loan.Book.Title,
+			Author:          loan.Book.Author,
+			ISBN:            loan.Book.ISBN,
+			AvailableCopies: loan.Book.AvailableCopies,
+			TotalCopies:     loan.Book.TotalCopies,
+			Status:          string(loan.Book.Status),
+			IsAvailable:     loan.Book.IsAvailable(),
+		}
+	}
+	
+	return response
+}
+
+func circulationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrBookNotFound),
+		errors.Is(err, services.ErrMemberNotFound),
+		errors.Is(err, services.ErrLoanNotFound),
+		errors.Is(err, services.ErrCopyNotFound),
+		errors.Is(err, services.ErrRepairNotFound),
+		errors.Is(err, services.ErrSessionNotFound),
+		errors.Is(err, services.ErrBranchNotFound),
+		errors.Is(err, services.ErrTransferNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrBookUnavailable),
+		errors.Is(err, services.ErrBookOnHold),
+		errors.Is(err, services.ErrLoanNotActive),
+		errors.Is(err, services.ErrRepairClosed),
+		errors.Is(err, services.ErrNoCopyOnShelf),
+		errors.Is(err, services.ErrLoanLost),
+		errors.Is(err, services.ErrLoanAmbiguous),
+		errors.Is(err, services.ErrCopyOnLoan),
+		errors.Is(err, services.ErrSessionClosed),
+		errors.Is(err, services.ErrNoCopyAtBranch),
+		errors.Is(err, services.ErrBranchInactive),
+		errors.Is(err, services.ErrInvalidTransferState),
+		errors.Is(err, services.ErrHoldingInUse):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidRepairStatus),
+		errors.Is(err, services.ErrSessionNoPatron),
+		errors.Is(err, services.ErrPaymentExceedsBalance),
+		errors.Is(err, services.ErrTransferSameBranch),
+		errors.Is(err, services.ErrItemNotLendable):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrMemberInactive),
+		errors.Is(err, services.ErrLoanLimitReached),
+		errors.Is(err, services.ErrItemTypeLimit):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LocationHandler struct {
+	db        *gorm.DB
+	config    *config.Config
+	locations *services.LocationService
+}
+
+func NewLocationHandler(db *gorm.DB, cfg *config.Config) *LocationHandler {
+	return &LocationHandler{
+		db:        db,
+		config:    cfg,
+		locations: services.NewLocationService(db, cfg),
+	}
+}
+
+func (h *LocationHandler) GetLocations(c *gin.Context) {
+	tree, err := h.locations.Tree(c.Query("branch_id"))
+	if err != nil {
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": tree,
+	})
+}
+
+func (h *LocationHandler) CreateLocation(c *gin.Context) {
+	var req models.LocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	location, err := h.locations.Create(req)
+	if err != nil {
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": location,
+	})
+}
+
+func (h *LocationHandler) UpdateLocation(c *gin.Context) {
+	var req struct {
+		LocationID   string                 `json:"location_id" binding:"required"`
+		LocationData models.LocationRequest `json:"location_data"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	location, err := h.locations.Update(req.LocationID, req.LocationData)
+	if err != nil {
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": location,
+	})
+}
+
+func (h *LocationHandler) MoveLocation(c *gin.Context) {
+	var req struct {
+		LocationID string `json:"location_id" binding:"required"`
+		ParentID   string `json:"parent_id"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	location, err := h.locations.Move(req.LocationID, req.ParentID)
+	if err != nil {
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": location,
+	})
+}
+
+func (h *LocationHandler) RelocateItems(c *gin.Context) {
+	var req models.RelocateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	report, err := h.locations.Relocate(req)
+	if err != nil {
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": report,
+	})
+}
+
+func (h *LocationHandler) WhereIs(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Query("book_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		return
+	}
+	
+	placements, err := h.locations.WhereIs(bookID)
+	if err != nil {
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": placements,
+	})
+}
+
+func locationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrLocationNotFound),
+		errors.Is(err, services.ErrBookNotFound),
+		errors.Is(err, services.ErrBranchNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrLocationExists):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidLocationLevel),
+		errors.Is(err, services.ErrLocationParentLevel),
+		errors.Is(err, services.ErrLocationCycle),
+		errors.Is(err, services.ErrLocationBranch),
+		errors.Is(err, services.ErrLocationInactive),
+		errors.Is(err, services.ErrUnknownLocation),
+		errors.Is(err, services.ErrAmbiguousLocation),
+		errors.Is(err, services.ErrNothingToRelocate),
+		errors.Is(err, services.ErrBranchInactive):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type MemberHandler struct {
+	db     *gorm.DB
+	config *config.Config
+}
+
+func NewMemberHandler(db *gorm.DB, cfg *config.Config) *MemberHandler {
+	return &MemberHandler{
+		db:     db,
+		config: cfg,
+	}
+}
+
+var memberSortColumns = map[string]string{
+	"name":          "users.full_name",
+	"membership_id": "members.membership_id",
+	"join_date":     "members.join_date",
+	"created_at":    "members.created_at",
+}
+
+func (h *MemberHandler) GetMembers(c *gin.Context) {
+	params, err := utils.GetListParams(c, memberSortColumns, "name", "asc", h.config.Pagination.DefaultPageSize, h.config.Pagination.MaxPageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	query := h.db.Model(&models.Member{}).Joins("JOIN users ON users.id = members.user_id")
+	
+	if search := c.Query("search"); search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("users.full_name ILIKE ? OR users.email ILIKE ? OR members.membership_id ILIKE ?", pattern, pattern, pattern)
+	}
+	
+	if membershipType := c.Query("membership_type"); membershipType != "" {
+		query = query.Where("members.membership_type = ?", membershipType)
+	}
+	
+	if isActive := c.Query("is_active"); isActive != "" {
+		query = query.Where("members.is_active = ?", isActive == "true")
+	}
+	
+	if branchID := c.Query("home_branch_id"); branchID != "" {
+		query = query.Where("members.home_branch_id = ?", branchID)
+	}
+	
+	var total int64
+	query.Count(&total)
+	
+	var members []models.Member
+	if err := params.Apply(query, "members.id").Preload("User").Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch members"})
+		return
+	}
+	
+	memberResponses := make([]models.MemberResponse, len(members))
+	for i, member := range members {
+		memberResponses[i] = memberToResponse(member)
+	}
+	
+	memberResponses, nextCursor := utils.TrimPage(params, memberResponses)
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": gin.H{
+			"members":    memberResponses,
+			"pagination": params.Pagination(total, nextCursor),
+		},
+	})
+}
+
+func (h *MemberHandler) UpdateHomeBranch(c *gin.Context) {
+	var req struct {
+		MemberID string `json:"member_id"`
+		BranchID string `json:"branch_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	query := h.db.Preload("User")
+	if user.IsLibrarian() && req.MemberID != "" {
+		query = query.Where("id = ?", req.MemberID)
+	} else {
+		query = query.Where("user_id = ?", user.ID)
+	}
+	
+	var member models.Member
+	if err := query.First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+	
+	branch, err := services.ResolveBranch(h.db, req.BranchID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch"})
+		return
+	}
+	
+	member.HomeBranchID = &branch.ID
+	if err := h.db.Model(&member).Update("home_branch_id", branch.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update home branch"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": memberToResponse(member),
+	})
+}
+
+func (h *MemberHandler) SetAutoRenew(c *gin.Context) {
+	var req struct {
+		MemberID  string `json:"member_id"`
+		AutoRenew *bool  `json:"auto_renew" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	query := h.db.Preload("User")
+	if user.IsLibrarian() && req.MemberID != "" {
+		query = query.Where("id = ?", req.MemberID)
+	} else {
+		query = query.Where("user_id = ?", user.ID)
+	}
+	
+	var member models.Member
+	if err := query.First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+	
+	member.AutoRenew = *req.AutoRenew
+	if err := h.db.Model(&member).Update("auto_renew", member.AutoRenew).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update auto-renew setting"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": memberToResponse(member),
+	})
+}
+
+func memberToResponse(member models.Member) models.MemberResponse {
+	return models.MemberResponse{
+		ID:                 member.ID.String(),
+		MembershipID:       member.MembershipID,
+		Name:               member.User.FullName,
+		Email:              member.User.Email,
+		Phone:              member.User.Phone,
+		MembershipType:     string(member.MembershipType),
+		JoinDate:           member.JoinDate,
+		ExpiryDate:         member.ExpiryDate,
+		MaxBooksAllowed:    member.MaxBooksAllowed,
+		CurrentBooksIssued: member.CurrentBooksIssued,
+		OutstandingFines:   member.GetOutstandingFines(),
+		IsActive:           member.IsActive,
+		IsExpired:          member.IsExpired(),
+		HomeBranchID:       uuidString(member.HomeBranchID),
+		AutoRenew:          member.AutoRenew,
+		Address:            member.Address,
+		City:               member.City,
+		State:              member.State,
+		ZipCode:            member.ZipCode,
+		CreatedAt:          member.CreatedAt,
+	}
+}
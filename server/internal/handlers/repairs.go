@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RepairHandler struct {
+	db          *gorm.DB
+	config      *config.Config
+	circulation *services.CirculationService
+}
+
+func NewRepairHandler(db *gorm.DB, cfg *config.Config) *RepairHandler {
+	return &RepairHandler{
+		db:          db,
+		config:      cfg,
+		circulation: services.NewCirculationService(db, cfg),
+	}
+}
+
+func (h *RepairHandler) GetRepairQueue(c *gin.Context) {
+	repairs, err := h.circulation.RepairQueue(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch repair queue"})
+		return
+	}
+	
+	responses := make([]models.RepairResponse, len(repairs))
+	for i, repair := range repairs {
+		responses[i] = repairToResponse(repair)
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": responses,
+	})
+}
+
+func (h *RepairHandler) ReportDamage(c *gin.Context) {
+	var req models.ReportDamageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	bookID, err := uuid.Parse(req.BookID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		return
+	}
+	
+	var loanID *uuid.UUID
+	if req.LoanID != "" {
+		id, err := uuid.Parse(req.LoanID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid loan ID"})
+			return
+		}
+		loanID = &id
+	}
+	
+	var branchID *uuid.UUID
+	if req.BranchID != "" {
+		id, err := uuid.Parse(req.BranchID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+			return
+		}
+		branchID = &id
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	repair, err := h.circulation.ReportDamage(bookID, loanID, branchID, user.ID, req.DamageCharge, req.Notes, req.Photos)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": repairToResponse(*repair),
+	})
+}
+
+func (h *RepairHandler) UpdateRepair(c *gin.Context) {
+	var req models.UpdateRepairRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	repairID, err := uuid.Parse(req.RepairID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid repair ID"})
+		return
+	}
+	
+	repair, err := h.circulation.UpdateRepair(repairID, models.RepairStatus(req.Status), req.Notes)
+	if err != nil {
+		c.JSON(circulationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": repairToResponse(*repair),
+	})
+}
+
+func repairToResponse(repair models.Repair) models.RepairResponse {
+	response := models.RepairResponse{
+		ID:            repair.ID.String(),
+		BookID:        repair.BookID.String(),
+		Status:        string(repair.Status),
+		DamageCharge:  repair.DamageCharge,
+		Notes:         repair.Notes,
+		Photos:        repair.Photos,
+		ResolvedDate:  repair.ResolvedDate,
+		ResolvedNotes: repair.ResolvedNotes,
+		CreatedAt:     repair.CreatedAt,
+	}
+	
+	if repair.LoanID != nil {
+		loanID := repair.LoanID.String()
+		response.LoanID = &loanID
+	}
+	
+	response.BranchID = uuidString(repair.BranchID)
+	
+	if repair.Book.ID != uuid.Nil {
+		response.Book = &models.BookResponse{
+			ID:       repair.Book.ID.String(),
+			Title:    repair.Book.Title,
+			Author:   repair.Book.Author,
+			ISBN:     repair.Book.ISBN,
+			Location: repair.Book.Location,
+			Status:   string(repair.Book.Status),
+		}
+	}
+	
+	return response
+}
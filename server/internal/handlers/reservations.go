@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReservationHandler struct {
+	db          *gorm.DB
+	config      *config.Config
+	circulation *services.CirculationService
+}
+
+func NewReservationHandler(db *gorm.DB, cfg *config.Config) *ReservationHandler {
+	return &ReservationHandler{
+		db:          db,
+		config:      cfg,
+		circulation: services.NewCirculationService(db, cfg),
+	}
+}
+
+func (h *ReservationHandler) GetHoldShelf(c *gin.Context) {
+	holds, err := h.circulation.HoldShelf(c.Query("pickup_location"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hold shelf"})
+		return
+	}
+	
+	shelf := make(map[string][]models.ReservationResponse)
+	locations := make([]string, 0)
+	for _, hold := range holds {
+		if _, ok := shelf[hold.PickupLocation]; !ok {
+			locations = append(locations, hold.PickupLocation)
+		}
+		shelf[hold.PickupLocation] = append(shelf[hold.PickupLocation], h.reservationToResponse(hold))
+	}
+	
+	groups := make([]gin.H, len(locations))
+	for i, location := range locations {
+		groups[i] = gin.H{
+			"pickup_location": location,
+			"holds":           shelf[location],
+		}
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": gin.H{
+			"locations": groups,
+			"total":     len(holds),
+		},
+	})
+}
+
+func (h *ReservationHandler) ExpireHolds(c *gin.Context) {
+	count, err := h.circulation.ExpireHolds()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to expire holds"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": gin.H{
+			"expired": count,
+		},
+	})
+}
+
+var reservationSortColumns = map[string]string{
+	"reservation_date": "reservations.reservation_date",
+	"expiry_date":      "reservations.expiry_date",
+	"queue_position":   "reservations.queue_position",
+	"created_at":       "reservations.created_at",
+}
+
+func (h *ReservationHandler) GetReservations(c *gin.Context) {
+	params, err := utils.GetListParams(c, reservationSortColumns, "reservation_date", "desc", h.config.Pagination.DefaultPageSize, h.config.Pagination.MaxPageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	query := h.db.Model(&models.Reservation{})
+	
+	if user.IsLibrarian() {
+		if memberID := c.Query("member_id"); memberID != "" {
+			query = query.Where("reservations.member_id = ?", memberID)
+		}
+	} else {
+		query = query.Joins("JOIN members ON members.id = reservations.member_id").
+			Where("members.user_id = ?", user.ID)
+	}
+	
+	if bookID := c.Query("book_id"); bookID != "" {
+		query = query.Where("reservations.book_id = ?", bookID)
+	}
+	
+	if status := c.Query("status"); status != "" {
+		query = query.Where("reservations.status = ?", status)
+	}
+	
+	var total int64
+	query.Count(&total)
+	
+	var reservations []models.Reservation
+	if err := params.Apply(query, "reservations.id").Preload("Book").Find(&reservations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reservations"})
+		return
+	}
+	
+	reservationResponses := make([]models.ReservationResponse, len(reservations))
+	for i, reservation := range reservations {
+		reservationResponses[i] = h.reservationToResponse(reservation)
+	}
+	
+	reservationResponses, nextCursor := utils.TrimPage(params, reservationResponses)
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": gin.H{
+			"reservations": reservationResponses,
+			"pagination":   params.Pagination(total, nextCursor),
+		},
+	})
+}
+
+func (h *ReservationHandler) reservationToResponse(reservation models.Reservation) models.ReservationResponse {
+	response := models.ReservationResponse{
+		ID:              reservation.ID.String(),
+		BookID:          reservation.BookID.String(),
+		MemberID:        reservation.MemberID.String(),
+		ReservationDate: reservation.ReservationDate,
+		ExpiryDate:      reservation.ExpiryDate,
+		FulfilledDate:   reservation.FulfilledDate,
+		CancelledDate:   reservation.CancelledDate,
+		TrappedDate:     reservation.TrappedDate,
+		PickupDeadline:  reservation.PickupDeadline,
+		PickupLocation:  reservation.PickupLocation,
+		PickupBranchID:  uuidString(reservation.PickupBranchID),
+		Status:          string(reservation.Status),
+		QueuePosition:   reservation.QueuePosition,
+		IsExpired:       reservation.IsExpired() || reservation.IsPickupExpired(),
+		Notes:           reservation.Notes,
+		CreatedAt:       reservation.CreatedAt,
+	}
+	
+	if reservation.Book.ID != uuid.Nil {
+		response.Book = &models.BookResponse{
+			ID:       reservation.Book.ID.String(),
+			Title:    reservation.Book.Title,
+			Author:   reservation.Book.Author,
+			ISBN:     reservation.Book.ISBN,
+			Location: reservation.Book.Location,
+			Status:   string(reservation.Book.Status),
+		}
+	}
+	
+	if reservation.Member.ID != uuid.Nil {
+		response.Member = &models.MemberResponse{
+			ID:           reservation.Member.ID.String(),
+			MembershipID: reservation.Member.MembershipID,
+			Name:         reservation.Member.User.FullName,
+			Email:        reservation.Member.User.Email,
+			Phone:        reservation.Member.User.Phone,
+		}
+	}
+	
+	return response
+}
@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/pkg/issn"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type SerialHandler struct {
+	db      *gorm.DB
+	config  *config.Config
+	serials *services.SerialService
+}
+
+func NewSerialHandler(db *gorm.DB, cfg *config.Config) *SerialHandler {
+	return &SerialHandler{
+		db:      db,
+		config:  cfg,
+		serials: services.NewSerialService(db, cfg),
+	}
+}
+
+func (h *SerialHandler) GetSerials(c *gin.Context) {
+	query := h.db.Model(&models.Serial{})
+	if search := strings.TrimSpace(c.Query("search")); search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("title ILIKE ? OR issn ILIKE ? OR publisher ILIKE ?", pattern, pattern, pattern)
+	}
+	if category := c.Query("category"); category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if c.Query("include_inactive") != "true" {
+		query = query.Where("is_active = ?", true)
+	}
+	
+	var serials []models.Serial
+	if err := query.Order("title ASC").Find(&serials).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch serials"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": serials,
+	})
+}
+
+func (h *SerialHandler) CreateSerial(c *gin.Context) {
+	var req models.SerialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	serial := models.Serial{Circulates: true, IsActive: true}
+	if err := applySerialRequest(&serial, req); err != nil {
+		c.JSON(serialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	var existing int64
+	h.db.Unscoped().Model(&models.Serial{}).Where("issn = ?", serial.ISSN).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": services.ErrSerialExists.Error()})
+		return
+	}
+	
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&serial).Error; err != nil {
+			return err
+		}
+		if !serial.Circulates || !serial.IsActive {
+			return tx.Model(&serial).Updates(map[string]interface{}{
+				"circulates": serial.Circulates,
+				"is_active":  serial.IsActive,
+			}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create serial"})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": serial,
+	})
+}
+
+func (h *SerialHandler) UpdateSerial(c *gin.Context) {
+	var req struct {
+		SerialID   string               `json:"serial_id" binding:"required"`
+		SerialData models.SerialRequest `json:"serial_data"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	var serial models.Serial
+	if err := h.db.First(&serial, "id = ?", req.SerialID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serial not found"})
+		return
+	}
+	
+	if err := applySerialRequest(&serial, req.SerialData); err != nil {
+		c.JSON(serialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	var existing int64
+	h.db.Unscoped().Model(&models.Serial{}).Where("issn = ? AND id <> ?", serial.ISSN, serial.ID).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": services.ErrSerialExists.Error()})
+		return
+	}
+	
+	if err := h.db.Save(&serial).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update serial"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": serial,
+	})
+}
+
+func (h *SerialHandler) GetSubscriptions(c *gin.Context) {
+	subscriptions, err := h.serials.Subscriptions(c.Query("serial_id"), c.Query("branch_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subscriptions"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": subscriptions,
+	})
+}
+
+func (h *SerialHandler) CreateSubscription(c *gin.Context) {
+	var req models.SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	subscription, err := h.serials.CreateSubscription(req)
+	if err != nil {
+		c.JSON(serialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": subscription,
+	})
+}
+
+func (h *SerialHandler) CancelSubscription(c *gin.Context) {
+	var req struct {
+		SubscriptionID string `json:"subscription_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	subscription, err := h.serials.CancelSubscription(req.SubscriptionID)
+	if err != nil {
+		c.JSON(serialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": subscription,
+	})
+}
+
+func (h *SerialHandler) PredictIssues(c *gin.Context) {
+	var req struct {
+		SubscriptionID string    `json:"subscription_id" binding:"required"`
+		Through        time.Time `json:"through"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	issues, err := h.serials.Predict(req.SubscriptionID, req.Through)
+	if err != nil {
+		c.JSON(serialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": issues,
+	})
+}
+
+func (h *SerialHandler) GetIssues(c *gin.Context) {
+	issues, err := h.serials.Issues(c.Query("serial_id"), c.Query("subscription_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch issues"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": issues,
+	})
+}
+
+func (h *SerialHandler) CheckInIssue(c *gin.Context) {
+	var req models.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	issue, err := h.serials.CheckIn(req)
+	if err != nil {
+		c.JSON(serialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": issue,
+	})
+}
+
+func (h *SerialHandler) GetClaimsDue(c *gin.Context) {
+	claims, err := h.serials.ClaimsDue(c.Query("branch_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch claims due"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": claims,
+	})
+}
+
+func (h *SerialHandler) ClaimIssues(c *gin.Context) {
+	h.changeIssues(c, h.serials.Claim)
+}
+
+func (h *SerialHandler) MarkIssuesMissing(c *gin.Context) {
+	h.changeIssues(c, h.serials.MarkMissing)
+}
+
+func (h *SerialHandler) CheckoutIssue(c *gin.Context) {
+	var req models.IssueCheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	if (req.IssueID == "" && req.Barcode == "") || (req.MemberID == "" && req.MembershipID == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An issue and a member are required"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	loan, err := h.serials.Checkout(req, user.ID)
+	if err != nil {
+		c.JSON(serialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": loan,
+	})
+}
+
+func (h *SerialHandler) ReturnIssue(c *gin.Context) {
+	h.changeIssueLoan(c, h.serials.Return)
+}
+
+func (h *SerialHandler) RenewIssue(c *gin.Context) {
+	h.changeIssueLoan(c, h.serials.Renew)
+}
+
+func (h *SerialHandler) GetIssueLoans(c *gin.Context) {
+	loans, err := h.serials.Loans(c.Query("member_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch issue loans"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": loans,
+	})
+}
+
+func (h *SerialHandler) changeIssues(c *gin.Context, change func(models.IssueActionRequest) (*models.IssueActionResult, error)) {
+	var req models.IssueActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	result, err := change(req)
+	if err != nil {
+		c.JSON(serialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": result,
+	})
+}
+
+func (h *SerialHandler) changeIssueLoan(c *gin.Context, change func(string) (*models.IssueLoan, error)) {
+	var req struct {
+		LoanID string `json:"loan_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	loan, err := change(req.LoanID)
+	if err != nil {
+		c.JSON(serialErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": loan,
+	})
+}
+
+func applySerialRequest(serial *models.Serial, req models.SerialRequest) error {
+	normalized, err := issn.Normalize(req.ISSN)
+	if err != nil {
+		return fmt.Errorf("%w: %v", services.ErrSerialISSN, err)
+	}
+	if !req.Frequency.Valid() {
+		return services.ErrSerialFrequency
+	}
+	
+	serial.Title = strings.TrimSpace(req.Title)
+	serial.ISSN = normalized
+	serial.Publisher = req.Publisher
+	serial.Category = req.Category
+	serial.Language = req.Language
+	serial.Frequency = req.Frequency
+	serial.LoanDays = req.LoanDays
+	serial.MaxRenewals = req.MaxRenewals
+	serial.FinePerDay = req.FinePerDay
+	if req.Circulates != nil {
+		serial.Circulates = *req.Circulates
+	}
+	if req.IsActive != nil {
+		serial.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+func serialErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrSerialNotFound),
+		errors.Is(err, services.ErrSubscriptionNotFound),
+		errors.Is(err, services.ErrIssueNotFound),
+		errors.Is(err, services.ErrIssueLoanNotFound),
+		errors.Is(err, services.ErrMemberNotFound),
+		errors.Is(err, services.ErrVendorNotFound),
+		errors.Is(err, services.ErrBranchNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrSerialExists),
+		errors.Is(err, services.ErrSubscriptionCancelled),
+		errors.Is(err, services.ErrIssueReceived),
+		errors.Is(err, services.ErrIssueUnavailable),
+		errors.Is(err, services.ErrNoExpectedIssue):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrSerialISSN),
+		errors.Is(err, services.ErrSerialFrequency),
+		errors.Is(err, services.ErrSerialInactive),
+		errors.Is(err, services.ErrCheckInTarget),
+		errors.Is(err, services.ErrIssueNotCirculating),
+		errors.Is(err, services.ErrIssueRenewalLimit),
+		errors.Is(err, services.ErrIssueLoanOverdue),
+		errors.Is(err, services.ErrLoanNotActive),
+		errors.Is(err, services.ErrBranchInactive):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrMemberInactive),
+		errors.Is(err, services.ErrLoanLimitReached):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type StocktakeHandler struct {
+	db         *gorm.DB
+	config     *config.Config
+	stocktakes *services.StocktakeService
+}
+
+func NewStocktakeHandler(db *gorm.DB, cfg *config.Config) *StocktakeHandler {
+	return &StocktakeHandler{
+		db:         db,
+		config:     cfg,
+		stocktakes: services.NewStocktakeService(db, cfg),
+	}
+}
+
+func (h *StocktakeHandler) GetStocktakes(c *gin.Context) {
+	stocktakes, err := h.stocktakes.List(c.Query("branch_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stocktakes"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": stocktakes,
+	})
+}
+
+func (h *StocktakeHandler) StartStocktake(c *gin.Context) {
+	var req models.StocktakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	stocktake, err := h.stocktakes.Start(req, user.ID)
+	if err != nil {
+		c.JSON(stocktakeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": stocktake,
+	})
+}
+
+func (h *StocktakeHandler) ScanItems(c *gin.Context) {
+	var req models.StocktakeScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	results, err := h.stocktakes.Scan(req, user.ID)
+	if err != nil {
+		c.JSON(stocktakeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": results,
+	})
+}
+
+func (h *StocktakeHandler) GetStocktakeReport(c *gin.Context) {
+	report, err := h.stocktakes.Report(c.Query("stocktake_id"))
+	if err != nil {
+		c.JSON(stocktakeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": report,
+	})
+}
+
+func (h *StocktakeHandler) CompleteStocktake(c *gin.Context) {
+	h.closeStocktake(c, h.stocktakes.Complete)
+}
+
+func (h *StocktakeHandler) CancelStocktake(c *gin.Context) {
+	h.closeStocktake(c, h.stocktakes.Cancel)
+}
+
+func (h *StocktakeHandler) MarkMissingLost(c *gin.Context) {
+	h.resolveStocktake(c, h.stocktakes.MarkMissingLost)
+}
+
+func (h *StocktakeHandler) FixLocations(c *gin.Context) {
+	h.resolveStocktake(c, h.stocktakes.FixLocations)
+}
+
+func (h *StocktakeHandler) closeStocktake(c *gin.Context, finish func(string) (*models.StocktakeReport, error)) {
+	var req struct {
+		StocktakeID string `json:"stocktake_id" binding:"required"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	report, err := finish(req.StocktakeID)
+	if err != nil {
+		c.JSON(stocktakeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": report,
+	})
+}
+
+func (h *StocktakeHandler) resolveStocktake(c *gin.Context, action func(models.StocktakeActionRequest) (*models.StocktakeActionReport, error)) {
+	var req models.StocktakeActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	result, err := action(req)
+	if err != nil {
+		c.JSON(stocktakeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": result,
+	})
+}
+
+func stocktakeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrStocktakeNotFound),
+		errors.Is(err, services.ErrLocationNotFound),
+		errors.Is(err, services.ErrBranchNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrStocktakeClosed),
+		errors.Is(err, services.ErrStocktakeNotCompleted):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrNothingToScan),
+		errors.Is(err, services.ErrLocationBranch),
+		errors.Is(err, services.ErrBranchInactive):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
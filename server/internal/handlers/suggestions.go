@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SuggestionHandler struct {
+	db          *gorm.DB
+	config      *config.Config
+	suggestions *services.PurchaseSuggestionService
+}
+
+func NewSuggestionHandler(db *gorm.DB, cfg *config.Config) *SuggestionHandler {
+	return &SuggestionHandler{
+		db:          db,
+		config:      cfg,
+		suggestions: services.NewPurchaseSuggestionService(db, cfg),
+	}
+}
+
+func (h *SuggestionHandler) GetSuggestions(c *gin.Context) {
+	suggestions, err := h.suggestions.List(c.Query("status"), c.Query("member_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": suggestions,
+	})
+}
+
+func (h *SuggestionHandler) GetMySuggestions(c *gin.Context) {
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	suggestions, err := h.suggestions.ForUser(user.ID)
+	if err != nil {
+		c.JSON(suggestionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": suggestions,
+	})
+}
+
+func (h *SuggestionHandler) SuggestTitle(c *gin.Context) {
+	var req models.SuggestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil || user.Role != models.RoleMember {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only members can suggest titles"})
+		return
+	}
+	
+	suggestion, err := h.suggestions.Submit(user.ID, req)
+	if err != nil {
+		c.JSON(suggestionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": suggestion,
+	})
+}
+
+func (h *SuggestionHandler) ApproveSuggestion(c *gin.Context) {
+	h.reviewSuggestion(c, h.suggestions.Approve)
+}
+
+func (h *SuggestionHandler) RejectSuggestion(c *gin.Context) {
+	h.reviewSuggestion(c, h.suggestions.Reject)
+}
+
+func (h *SuggestionHandler) MarkSuggestionOrdered(c *gin.Context) {
+	h.reviewSuggestion(c, h.suggestions.MarkOrdered)
+}
+
+func (h *SuggestionHandler) reviewSuggestion(c *gin.Context, review func(models.SuggestionReviewRequest, uuid.UUID) (*models.PurchaseSuggestion, error)) {
+	var req models.SuggestionReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	suggestion, err := review(req, user.ID)
+	if err != nil {
+		c.JSON(suggestionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": suggestion,
+	})
+}
+
+func suggestionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrSuggestionNotFound),
+		errors.Is(err, services.ErrMemberNotFound),
+		errors.Is(err, services.ErrOrderLineNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrSuggestionExists),
+		errors.Is(err, services.ErrSuggestionInCatalog),
+		errors.Is(err, services.ErrSuggestionState):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrSuggestionISBN),
+		errors.Is(err, services.ErrRejectionReason):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
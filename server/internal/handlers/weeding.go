@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WeedingHandler struct {
+	db      *gorm.DB
+	config  *config.Config
+	weeding *services.WeedingService
+}
+
+func NewWeedingHandler(db *gorm.DB, cfg *config.Config) *WeedingHandler {
+	return &WeedingHandler{
+		db:      db,
+		config:  cfg,
+		weeding: services.NewWeedingService(db, cfg),
+	}
+}
+
+func (h *WeedingHandler) GetDormantItems(c *gin.Context) {
+	years, _ := strconv.Atoi(c.DefaultQuery("years", "3"))
+	if years < 1 {
+		years = 3
+	}
+	
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit < 1 || limit > 1000 {
+		limit = 100
+	}
+	
+	items, err := h.weeding.Dormant(years, c.Query("branch_id"), c.Query("category"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build dormant items report"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": items,
+	})
+}
+
+func (h *WeedingHandler) GetTurnoverReport(c *gin.Context) {
+	years, _ := strconv.Atoi(c.DefaultQuery("years", "1"))
+	if years < 1 {
+		years = 1
+	}
+	threshold, _ := strconv.ParseFloat(c.Query("threshold"), 64)
+	
+	report, err := h.weeding.Turnover(years, threshold)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build turnover report"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": report,
+	})
+}
+
+func (h *WeedingHandler) GetCollectionAge(c *gin.Context) {
+	report, err := h.weeding.CollectionAge(c.Query("branch_id"), c.Query("category"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build collection age report"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": report,
+	})
+}
+
+func (h *WeedingHandler) GetDemandReport(c *gin.Context) {
+	minHolds, _ := strconv.Atoi(c.DefaultQuery("min_holds", "1"))
+	if minHolds < 1 {
+		minHolds = 1
+	}
+	holdsPerCopy, _ := strconv.ParseFloat(c.DefaultQuery("holds_per_copy", "3"), 64)
+	
+	ratios, err := h.weeding.Demand(minHolds, holdsPerCopy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build demand report"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": ratios,
+	})
+}
+
+func (h *WeedingHandler) GetWeedingCandidates(c *gin.Context) {
+	candidates, err := h.weeding.Candidates(c.Query("status"), c.Query("branch_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch weeding candidates"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": candidates,
+	})
+}
+
+func (h *WeedingHandler) ProposeWeeding(c *gin.Context) {
+	var req models.WeedingProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	result, err := h.weeding.Propose(req, user.ID)
+	if err != nil {
+		c.JSON(weedingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, gin.H{
+		"message": result,
+	})
+}
+
+func (h *WeedingHandler) WithdrawCandidates(c *gin.Context) {
+	h.decideCandidates(c, h.weeding.Withdraw)
+}
+
+func (h *WeedingHandler) KeepCandidates(c *gin.Context) {
+	h.decideCandidates(c, h.weeding.Keep)
+}
+
+func (h *WeedingHandler) decideCandidates(c *gin.Context, decide func(models.WeedingDecisionRequest, uuid.UUID) (*models.WeedingResult, error)) {
+	var req models.WeedingDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	
+	user, _ := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	
+	result, err := decide(req, user.ID)
+	if err != nil {
+		c.JSON(weedingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{
+		"message": result,
+	})
+}
+
+func weedingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrBranchNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrWeedingCriteria),
+		errors.Is(err, services.ErrWithdrawalReason),
+		errors.Is(err, services.ErrBranchInactive):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,201 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidLeader    = errors.New("marc: invalid leader")
+	ErrInvalidDirectory = errors.New("marc: invalid directory")
+	ErrRecordTooLong    = errors.New("marc: record exceeds 99999 bytes")
+	ErrMARC8            = errors.New("marc: MARC-8 records are not supported, convert them to UTF-8 (leader/09 = a) first")
+	ErrInvalidUTF8      = errors.New("marc: record is marked UTF-8 but contains invalid UTF-8")
+)
+
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+func (d *Reader) Read() (*Record, error) {
+	for {
+		b, err := d.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] != '\n' && b[0] != '\r' && b[0] != ' ' {
+			break
+		}
+		d.r.ReadByte()
+	}
+	
+	prefix := make([]byte, 5)
+	if _, err := io.ReadFull(d.r, prefix); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, ErrInvalidLeader
+		}
+		return nil, err
+	}
+	
+	length, err := strconv.Atoi(string(prefix))
+	if err != nil || length < leaderLength+1 {
+		return nil, ErrInvalidLeader
+	}
+	
+	data := make([]byte, length)
+	copy(data, prefix)
+	if _, err := io.ReadFull(d.r, data[5:]); err != nil {
+		return nil, ErrInvalidLeader
+	}
+	
+	return Unmarshal(data)
+}
+
+func (d *Reader) ReadAll() ([]*Record, error) {
+	var records []*Record
+	for {
+		record, err := d.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, fmt.Errorf("record %d: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+}
+
+func Unmarshal(data []byte) (*Record, error) {
+	if len(data) < leaderLength {
+		return nil, ErrInvalidLeader
+	}
+	
+	leader := string(data[:leaderLength])
+	base, err := strconv.Atoi(leader[12:17])
+	if err != nil || base <= leaderLength || base > len(data) {
+		return nil, ErrInvalidLeader
+	}
+	
+	// Leader/09 is blank for MARC-8 and 'a' for UCS/Unicode. Without a
+	// MARC-8 transcoder the bytes cannot be stored as text, and Marshal
+	// always writes 'a', so anything else is refused here.
+	if leader[9] != 'a' {
+		return nil, ErrMARC8
+	}
+	if !utf8.Valid(data) {
+		return nil, ErrInvalidUTF8
+	}
+	
+	directory := data[leaderLength : base-1]
+	if len(directory)%directoryEntryLen != 0 {
+		return nil, ErrInvalidDirectory
+	}
+	
+	record := &Record{Leader: leader}
+	for i := 0; i < len(directory); i += directoryEntryLen {
+		entry := directory[i : i+directoryEntryLen]
+		tag := string(entry[:3])
+		length, errLength := strconv.Atoi(string(entry[3:7]))
+		start, errStart := strconv.Atoi(string(entry[7:12]))
+		if errLength != nil || errStart != nil || base+start+length > len(data) || length < 1 {
+			return nil, ErrInvalidDirectory
+		}
+		
+		raw := data[base+start : base+start+length-1]
+		field := Field{Tag: tag}
+		if field.IsControl() {
+			field.Value = string(raw)
+		} else {
+			if len(raw) < 2 {
+				return nil, ErrInvalidDirectory
+			}
+			field.Indicator = [2]byte{raw[0], raw[1]}
+			for _, part := range bytes.Split(raw[2:], []byte{subfieldDelimiter}) {
+				if len(part) == 0 {
+					continue
+				}
+				field.Subfields = append(field.Subfields, Subfield{Code: part[0], Value: string(part[1:])})
+			}
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	
+	return record, nil
+}
+
+func Marshal(record *Record) ([]byte, error) {
+	var directory, body bytes.Buffer
+	for _, field := range record.Fields {
+		start := body.Len()
+		if field.IsControl() {
+			body.WriteString(field.Value)
+		} else {
+			body.WriteByte(indicator(field.Indicator[0]))
+			body.WriteByte(indicator(field.Indicator[1]))
+			for _, sf := range field.Subfields {
+				body.WriteByte(subfieldDelimiter)
+				body.WriteByte(sf.Code)
+				body.WriteString(sf.Value)
+			}
+		}
+		body.WriteByte(fieldTerminator)
+		fmt.Fprintf(&directory, "%s%04d%05d", field.Tag, body.Len()-start, start)
+	}
+	directory.WriteByte(fieldTerminator)
+	
+	base := leaderLength + directory.Len()
+	length := base + body.Len() + 1
+	if length > 99999 {
+		return nil, ErrRecordTooLong
+	}
+	
+	leader := []byte(record.Leader)
+	if len(leader) != leaderLength {
+		leader = []byte(defaultLeader)
+	}
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	leader[9] = 'a'
+	copy(leader[10:12], "22")
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+	copy(leader[20:24], "4500")
+	
+	out := make([]byte, 0, length)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, body.Bytes()...)
+	out = append(out, recordTerminator)
+	return out, nil
+}
+
+type Writer struct {
+	w io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (e *Writer) Write(record *Record) error {
+	data, err := Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+	return b
+}
@@ -0,0 +1,249 @@
+package marc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/pkg/callnumber"
+)
+
+var (
+	yearPattern   = regexp.MustCompile(`\d{4}`)
+	volumePattern = regexp.MustCompile(`\d+(\.\d+)?`)
+)
+
+var languageCodes = map[string]string{
+	"en": "eng",
+	"fr": "fre",
+	"de": "ger",
+	"es": "spa",
+	"it": "ita",
+	"pt": "por",
+	"nl": "dut",
+	"ru": "rus",
+	"sv": "swe",
+}
+
+func FromBook(book models.Book) *Record {
+	record := NewRecord()
+	
+	record.AddControl("001", book.ID.String())
+	if !book.UpdatedAt.IsZero() {
+		record.AddControl("005", book.UpdatedAt.UTC().Format("20060102150405")+".0")
+	}
+	record.AddControl("008", fixedData(book))
+	
+	price := ""
+	if book.ReplacementCost > 0 {
+		price = strconv.FormatFloat(book.ReplacementCost, 'f', 2, 64)
+	}
+	record.AddData("020", ' ', ' ', Subfield{'a', book.ISBN}, Subfield{'c', price})
+	
+	class, item, _ := strings.Cut(book.CallNumber, " ")
+	switch callnumber.Scheme(book.Classification) {
+	case callnumber.LC:
+		record.AddData("050", ' ', '4', Subfield{'a', class}, Subfield{'b', item})
+	case callnumber.Dewey:
+		record.AddData("082", '0', '4', Subfield{'a', class}, Subfield{'b', item})
+	}
+	
+	authors := strings.Split(book.Author, ";")
+	record.AddData("100", '1', ' ', Subfield{'a', strings.TrimSpace(authors[0])})
+	record.AddData("245", '1', '0', Subfield{'a', book.Title})
+	
+	year := ""
+	if !book.PublishDate.IsZero() {
+		year = strconv.Itoa(book.PublishDate.Year())
+	}
+	record.AddData("264", ' ', '1', Subfield{'b', book.Publisher}, Subfield{'c', year})
+	record.AddData("520", ' ', ' ', Subfield{'a', book.Description})
+	
+	for _, tag := range book.Tags {
+		record.AddData("650", ' ', '4', Subfield{'a', tag})
+	}
+	record.AddData("655", ' ', '7', Subfield{'a', book.Category}, Subfield{'2', "local"})
+	for _, author := range authors[1:] {
+		record.AddData("700", '1', ' ', Subfield{'a', strings.TrimSpace(author)})
+	}
+	record.AddData("852", ' ', ' ', Subfield{'c', book.Location}, Subfield{'p', book.Barcode})
+	if book.CoverImage != "" {
+		record.AddData("856", '4', '2', Subfield{'3', "Cover image"}, Subfield{'u', book.CoverImage})
+	}
+	
+	return record
+}
+
+func ToBook(record *Record) models.Book {
+	book := models.Book{
+		ISBN:        isbnValue(record.SubfieldValue("020", 'a')),
+		Author:      trimPunctuation(record.SubfieldValue("100", 'a')),
+		Title:       title(record),
+		Description: record.SubfieldValue("520", 'a'),
+		Category:    trimPunctuation(record.SubfieldValue("655", 'a')),
+		Location:    record.SubfieldValue("852", 'c'),
+		Barcode:     record.SubfieldValue("852", 'p'),
+		CoverImage:  record.SubfieldValue("856", 'u'),
+	}
+	
+	if book.Author == "" {
+		book.Author = trimPunctuation(record.SubfieldValue("110", 'a'))
+	}
+	
+	if field := record.Field("082"); field != nil {
+		book.CallNumber = strings.TrimSpace(field.Subfield('a') + " " + field.Subfield('b'))
+		book.Classification = string(callnumber.Dewey)
+	} else if field := record.Field("050"); field != nil {
+		book.CallNumber = strings.TrimSpace(field.Subfield('a') + " " + field.Subfield('b'))
+		book.Classification = string(callnumber.LC)
+	}
+	
+	authors := []string{}
+	if book.Author != "" {
+		authors = append(authors, book.Author)
+	}
+	for _, field := range record.FieldsByTag("700") {
+		if name := trimPunctuation(field.Subfield('a')); name != "" && !contains(authors, name) {
+			authors = append(authors, name)
+		}
+	}
+	book.Author = strings.Join(authors, "; ")
+	
+	if price := record.SubfieldValue("020", 'c'); price != "" {
+		cleaned := strings.TrimLeft(price, "$£€ ")
+		if value, err := strconv.ParseFloat(strings.Fields(cleaned + " ")[0], 64); err == nil {
+			book.ReplacementCost = value
+		}
+	}
+	
+	publication := record.Field("264")
+	if publication == nil {
+		publication = record.Field("260")
+	}
+	if publication != nil {
+		book.Publisher = trimPunctuation(publication.Subfield('b'))
+		if year := yearPattern.FindString(publication.Subfield('c')); year != "" {
+			book.PublishDate = yearDate(year)
+		}
+	}
+	
+	fixed := record.Control("008")
+	if book.PublishDate.IsZero() && len(fixed) >= 11 {
+		if year := fixed[7:11]; yearPattern.MatchString(year) {
+			book.PublishDate = yearDate(year)
+		}
+	}
+	
+	if len(fixed) >= 38 {
+		book.Language = languageFromCode(fixed[35:38])
+	}
+	
+	for _, tag := range []string{"650", "651", "653"} {
+		for _, field := range record.FieldsByTag(tag) {
+			if subject := trimPunctuation(field.Subfield('a')); subject != "" && !contains(book.Tags, subject) {
+				book.Tags = append(book.Tags, subject)
+			}
+		}
+	}
+	
+	return book
+}
+
+func fixedData(book models.Book) string {
+	created := book.CreatedAt
+	if created.IsZero() {
+		created = time.Now()
+	}
+	
+	date := "    "
+	dateType := "n"
+	if !book.PublishDate.IsZero() {
+		date = fmt.Sprintf("%04d", book.PublishDate.Year())
+		dateType = "s"
+	}
+	
+	language := languageCodes[strings.ToLower(book.Language)]
+	if language == "" {
+		language = "und"
+	}
+	
+	return created.Format("060102") + dateType + date + "    " + "xx " + strings.Repeat(" ", 17) + language + " d"
+}
+
+func Series(record *Record) (string, float64) {
+	field := record.Field("830")
+	if field == nil {
+		field = record.Field("490")
+	}
+	if field == nil {
+		return "", 0
+	}
+	
+	var volume float64
+	if number := volumePattern.FindString(field.Subfield('v')); number != "" {
+		volume, _ = strconv.ParseFloat(number, 64)
+	}
+	return trimPunctuation(field.Subfield('a')), volume
+}
+
+func title(record *Record) string {
+	field := record.Field("245")
+	if field == nil {
+		return ""
+	}
+	
+	parts := []string{trimPunctuation(field.Subfield('a'))}
+	if subtitle := trimPunctuation(field.Subfield('b')); subtitle != "" {
+		parts = append(parts, subtitle)
+	}
+	return strings.Join(parts, ": ")
+}
+
+func isbnValue(value string) string {
+	if fields := strings.Fields(value); len(fields) > 0 {
+		return strings.ReplaceAll(fields[0], "-", "")
+	}
+	return ""
+}
+
+func trimPunctuation(value string) string {
+	value = strings.TrimRight(strings.TrimSpace(value), " /:;,=")
+	if strings.HasSuffix(value, ".") && !endsWithInitial(value[:len(value)-1]) {
+		value = value[:len(value)-1]
+	}
+	return strings.TrimSpace(value)
+}
+
+func endsWithInitial(value string) bool {
+	n := len(value)
+	if n == 0 || value[n-1] < 'A' || value[n-1] > 'Z' {
+		return false
+	}
+	return n == 1 || value[n-2] == ' ' || value[n-2] == '.'
+}
+
+func yearDate(year string) time.Time {
+	y, _ := strconv.Atoi(year)
+	return time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func languageFromCode(code string) string {
+	for short, marc := range languageCodes {
+		if marc == code {
+			return short
+		}
+	}
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
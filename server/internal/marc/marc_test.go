@@ -0,0 +1,187 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func sampleBook() models.Book {
+	return models.Book{
+		ID:              uuid.MustParse("0b6c6c2e-54a1-4c4f-9d1e-6a0f5f1f2a10"),
+		Title:           "Les Misérables",
+		Author:          "Hugo, Victor; Wilbour, Charles E.",
+		ISBN:            "9780140444308",
+		Barcode:         "B0001",
+		Publisher:       "Penguin",
+		PublishDate:     time.Date(1982, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Category:        "Fiction",
+		Language:        "fr",
+		Description:     "Jean Valjean’s story — in full.",
+		ReplacementCost: 18.5,
+		Location:        "2F/READING/R12",
+		CallNumber:      "843.7 H895m",
+		Classification:  "dewey",
+		Tags:            []string{"France", "Révolution"},
+	}
+}
+
+func sampleRecord() *Record {
+	record := NewRecord()
+	record.AddControl("001", "rec-1")
+	record.AddControl("008", "820101s1982    enk           000 1 fre d")
+	record.AddData("020", ' ', ' ', Subfield{'a', "9780140444308"}, Subfield{'c', "18.50"})
+	record.AddData("100", '1', ' ', Subfield{'a', "Hugo, Victor,"})
+	record.AddData("245", '1', '0', Subfield{'a', "Les Misérables /"}, Subfield{'c', "Victor Hugo ; traduit par Ωmega."})
+	record.AddData("650", ' ', '0', Subfield{'a', "Révolution"}, Subfield{'z', "France"})
+	return record
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	want := sampleRecord()
+	
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if data[9] != 'a' {
+		t.Errorf("leader/09 = %q, want 'a'", data[9])
+	}
+	if data[len(data)-1] != recordTerminator {
+		t.Errorf("record does not end with a record terminator")
+	}
+	
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(got.Fields, want.Fields) {
+		t.Errorf("fields = %+v, want %+v", got.Fields, want.Fields)
+	}
+	
+	var buf bytes.Buffer
+	writer := NewWriter(&buf)
+	for i := 0; i < 2; i++ {
+		if err := writer.Write(want); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	records, err := NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("read %d records, want 2", len(records))
+	}
+}
+
+func TestXMLRoundTrip(t *testing.T) {
+	want := sampleRecord()
+	want.Leader = "00000nam  2200000 i 4500"
+	
+	var buf bytes.Buffer
+	writer := NewXMLWriter(&buf)
+	if err := writer.Write(want); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	
+	records, err := ReadXML(&buf)
+	if err != nil {
+		t.Fatalf("ReadXML: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("read %d records, want 1", len(records))
+	}
+	if records[0].Leader[9] != 'a' {
+		t.Errorf("leader/09 = %q, want 'a'", records[0].Leader[9])
+	}
+	if !reflect.DeepEqual(records[0].Fields, want.Fields) {
+		t.Errorf("fields = %+v, want %+v", records[0].Fields, want.Fields)
+	}
+}
+
+func TestBookRoundTrip(t *testing.T) {
+	book := sampleBook()
+	
+	encode := map[string]func(*Record) ([]*Record, error){
+		"binary": func(record *Record) ([]*Record, error) {
+			data, err := Marshal(record)
+			if err != nil {
+				return nil, err
+			}
+			return NewReader(bytes.NewReader(data)).ReadAll()
+		},
+		"marcxml": func(record *Record) ([]*Record, error) {
+			var buf bytes.Buffer
+			writer := NewXMLWriter(&buf)
+			if err := writer.Write(record); err != nil {
+				return nil, err
+			}
+			if err := writer.Close(); err != nil {
+				return nil, err
+			}
+			return ReadXML(&buf)
+		},
+	}
+	
+	for name, roundTrip := range encode {
+		t.Run(name, func(t *testing.T) {
+			records, err := roundTrip(FromBook(book))
+			if err != nil {
+				t.Fatalf("round trip: %v", err)
+			}
+			got := ToBook(records[0])
+			
+			checks := []struct {
+				field     string
+				got, want interface{}
+			}{
+				{"title", got.Title, book.Title},
+				{"author", got.Author, book.Author},
+				{"isbn", got.ISBN, book.ISBN},
+				{"barcode", got.Barcode, book.Barcode},
+				{"publisher", got.Publisher, book.Publisher},
+				{"publish year", got.PublishDate.Year(), book.PublishDate.Year()},
+				{"language", got.Language, book.Language},
+				{"description", got.Description, book.Description},
+				{"replacement cost", got.ReplacementCost, book.ReplacementCost},
+				{"location", got.Location, book.Location},
+				{"call number", got.CallNumber, book.CallNumber},
+				{"classification", got.Classification, book.Classification},
+			}
+			for _, check := range checks {
+				if !reflect.DeepEqual(check.got, check.want) {
+					t.Errorf("%s = %v, want %v", check.field, check.got, check.want)
+				}
+			}
+		})
+	}
+}
+
+func TestUnmarshalRejectsNonUnicode(t *testing.T) {
+	data, err := Marshal(sampleRecord())
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	
+	marc8 := append([]byte(nil), data...)
+	marc8[9] = ' '
+	if _, err := Unmarshal(marc8); !errors.Is(err, ErrMARC8) {
+		t.Errorf("MARC-8 leader: error = %v, want %v", err, ErrMARC8)
+	}
+	
+	invalid := append([]byte(nil), data...)
+	invalid[bytes.Index(invalid, []byte("Hugo"))] = 0xE9
+	if _, err := Unmarshal(invalid); !errors.Is(err, ErrInvalidUTF8) {
+		t.Errorf("invalid UTF-8: error = %v, want %v", err, ErrInvalidUTF8)
+	}
+}
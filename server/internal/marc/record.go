@@ -0,0 +1,110 @@
+package marc
+
+import (
+	"strings"
+)
+
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+	leaderLength      = 24
+	directoryEntryLen = 12
+)
+
+const defaultLeader = "00000nam a2200000 i 4500"
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+type Field struct {
+	Tag       string
+	Value     string
+	Indicator [2]byte
+	Subfields []Subfield
+}
+
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+func NewRecord() *Record {
+	return &Record{Leader: defaultLeader}
+}
+
+func (f Field) IsControl() bool {
+	return strings.HasPrefix(f.Tag, "00")
+}
+
+func (f Field) Subfield(code byte) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
+
+func (r *Record) AddControl(tag, value string) {
+	if value == "" {
+		return
+	}
+	r.Fields = append(r.Fields, Field{Tag: tag, Value: value})
+}
+
+func (r *Record) AddData(tag string, ind1, ind2 byte, subfields ...Subfield) {
+	kept := make([]Subfield, 0, len(subfields))
+	for _, sf := range subfields {
+		if sf.Value != "" {
+			kept = append(kept, sf)
+		}
+	}
+	
+	if len(kept) == 0 {
+		return
+	}
+	
+	r.Fields = append(r.Fields, Field{
+		Tag:       tag,
+		Indicator: [2]byte{ind1, ind2},
+		Subfields: kept,
+	})
+}
+
+func (r *Record) Control(tag string) string {
+	for _, f := range r.Fields {
+		if f.Tag == tag && f.IsControl() {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+func (r *Record) Field(tag string) *Field {
+	for i := range r.Fields {
+		if r.Fields[i].Tag == tag {
+			return &r.Fields[i]
+		}
+	}
+	return nil
+}
+
+func (r *Record) FieldsByTag(tag string) []Field {
+	var fields []Field
+	for _, f := range r.Fields {
+		if f.Tag == tag {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func (r *Record) SubfieldValue(tag string, code byte) string {
+	if f := r.Field(tag); f != nil {
+		return f.Subfield(code)
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+package marc
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+const xmlNamespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+func ReadXML(r io.Reader) ([]*Record, error) {
+	decoder := xml.NewDecoder(r)
+	
+	var records []*Record
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+		
+		var raw xmlRecord
+		if err := decoder.DecodeElement(&raw, &start); err != nil {
+			return records, err
+		}
+		records = append(records, raw.record())
+	}
+}
+
+func (x xmlRecord) record() *Record {
+	record := &Record{Leader: x.Leader}
+	if len(record.Leader) != leaderLength {
+		record.Leader = defaultLeader
+	}
+	
+	for _, cf := range x.ControlFields {
+		record.Fields = append(record.Fields, Field{Tag: cf.Tag, Value: cf.Value})
+	}
+	
+	for _, df := range x.DataFields {
+		field := Field{Tag: df.Tag, Indicator: [2]byte{firstByte(df.Ind1), firstByte(df.Ind2)}}
+		for _, sf := range df.Subfields {
+			field.Subfields = append(field.Subfields, Subfield{Code: firstByte(sf.Code), Value: sf.Value})
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	
+	return record
+}
+
+type XMLWriter struct {
+	w       io.Writer
+	encoder *xml.Encoder
+	started bool
+}
+
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return &XMLWriter{w: w, encoder: encoder}
+}
+
+func (e *XMLWriter) Write(record *Record) error {
+	if !e.started {
+		if _, err := io.WriteString(e.w, xml.Header); err != nil {
+			return err
+		}
+		start := xml.StartElement{
+			Name: xml.Name{Local: "collection"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: xmlNamespace}},
+		}
+		if err := e.encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		e.started = true
+	}
+	
+	// MARCXML is always Unicode, whatever the source leader said.
+	leader := []byte(record.Leader)
+	if len(leader) != leaderLength {
+		leader = []byte(defaultLeader)
+	}
+	leader[9] = 'a'
+	
+	raw := xmlRecord{Leader: string(leader)}
+	for _, field := range record.Fields {
+		if field.IsControl() {
+			raw.ControlFields = append(raw.ControlFields, xmlControlField{Tag: field.Tag, Value: field.Value})
+			continue
+		}
+		
+		df := xmlDataField{
+			Tag:  field.Tag,
+			Ind1: string(indicator(field.Indicator[0])),
+			Ind2: string(indicator(field.Indicator[1])),
+		}
+		for _, sf := range field.Subfields {
+			df.Subfields = append(df.Subfields, xmlSubfield{Code: string(sf.Code), Value: sf.Value})
+		}
+		raw.DataFields = append(raw.DataFields, df)
+	}
+	
+	return e.encoder.Encode(raw)
+}
+
+func (e *XMLWriter) Close() error {
+	if !e.started {
+		if _, err := io.WriteString(e.w, xml.Header); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(e.w, `<collection xmlns="`+xmlNamespace+`"></collection>`); err != nil {
+			return err
+		}
+		return nil
+	}
+	
+	if err := e.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: "collection"}}); err != nil {
+		return err
+	}
+	return e.encoder.Flush()
+}
+
+func firstByte(s string) byte {
+	if s == "" {
+		return ' '
+	}
+	return s[0]
+}
@@ -0,0 +1,57 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const notFoundMarker = "null"
+
+type CachedProvider struct {
+	provider Provider
+	redis    *redis.Client
+	ttl      time.Duration
+}
+
+func NewCachedProvider(provider Provider, redisClient *redis.Client, ttl time.Duration) Provider {
+	if redisClient == nil || ttl <= 0 {
+		return provider
+	}
+	return &CachedProvider{provider: provider, redis: redisClient, ttl: ttl}
+}
+
+func (p *CachedProvider) Name() string {
+	return p.provider.Name()
+}
+
+func (p *CachedProvider) Lookup(ctx context.Context, isbn string) (*models.BookRequest, error) {
+	key := "metadata:" + p.provider.Name() + ":" + isbn
+	if cached, err := p.redis.Get(ctx, key).Result(); err == nil {
+		if cached == notFoundMarker {
+			return nil, ErrNotFound
+		}
+		
+		var book models.BookRequest
+		if json.Unmarshal([]byte(cached), &book) == nil {
+			return &book, nil
+		}
+	}
+	
+	book, err := p.provider.Lookup(ctx, isbn)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		p.redis.Set(ctx, key, notFoundMarker, p.ttl)
+	case err == nil:
+		if data, err := json.Marshal(book); err == nil {
+			p.redis.Set(ctx, key, data, p.ttl)
+		}
+	}
+	
+	return book, err
+}
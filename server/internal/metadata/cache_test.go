@@ -0,0 +1,180 @@
+package metadata
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis speaks just enough RESP for GET and SET; every other command
+// gets an error, which go-redis tolerates during its connection handshake.
+type fakeRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func startRedis(t *testing.T) (*fakeRedis, *redis.Client) {
+	t.Helper()
+	
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	
+	server := &fakeRedis{values: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String(), Protocol: 2})
+	t.Cleanup(func() {
+		client.Close()
+		listener.Close()
+	})
+	return server, client
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		
+		s.mu.Lock()
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			if value, ok := s.values[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case "SET":
+			s.values[args[1]] = args[2]
+			reply = "+OK\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+		
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeRedis) get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || count < 1 {
+		return nil, errors.New("expected a command array")
+	}
+	
+	args := make([]string, count)
+	for i := range args {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:length])
+	}
+	return args, nil
+}
+
+func TestCachedProvider(t *testing.T) {
+	server, client := startRedis(t)
+	ctx := context.Background()
+	
+	static := NewStaticProvider("static", map[string]models.BookRequest{
+		testISBN: {Title: "Les Misérables", Author: "Victor Hugo"},
+	})
+	cached := NewCachedProvider(static, client, time.Hour)
+	if cached.Name() != "static" {
+		t.Errorf("name = %q, want the wrapped provider's", cached.Name())
+	}
+	
+	for i := 0; i < 2; i++ {
+		book, err := cached.Lookup(ctx, testISBN)
+		if err != nil {
+			t.Fatalf("lookup %d: %v", i+1, err)
+		}
+		if book.Title != "Les Misérables" || book.Author != "Victor Hugo" {
+			t.Errorf("lookup %d = %+v", i+1, book)
+		}
+	}
+	if static.Calls() != 1 {
+		t.Errorf("provider called %d times, want the second lookup served from the cache", static.Calls())
+	}
+	if _, ok := server.get("metadata:static:" + testISBN); !ok {
+		t.Errorf("result was not cached under the provider's key")
+	}
+	
+	for i := 0; i < 2; i++ {
+		if _, err := cached.Lookup(ctx, "9780000000002"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("missing lookup %d: error = %v, want %v", i+1, err, ErrNotFound)
+		}
+	}
+	if static.Calls() != 2 {
+		t.Errorf("provider called %d times, want misses cached too", static.Calls())
+	}
+	
+	chain := NewChain(cached)
+	result, err := chain.Lookup(ctx, testISBN)
+	if err != nil || result.Sources["title"] != "static" {
+		t.Errorf("chain over cache = %+v, %v", result, err)
+	}
+	if static.Calls() != 2 {
+		t.Errorf("chain lookup reached the provider despite the cache")
+	}
+}
+
+func TestCachedProviderDisabled(t *testing.T) {
+	static := NewStaticProvider("static", nil)
+	if provider := NewCachedProvider(static, nil, time.Hour); provider != Provider(static) {
+		t.Errorf("without redis the provider should be used directly")
+	}
+	
+	_, client := startRedis(t)
+	if provider := NewCachedProvider(static, client, 0); provider != Provider(static) {
+		t.Errorf("with a zero TTL the provider should be used directly")
+	}
+}
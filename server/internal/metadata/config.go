@@ -0,0 +1,34 @@
+package metadata
+
+import (
+	"log"
+	"strings"
+
+	"github.com/library-management-system/server/internal/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func NewFromConfig(cfg config.MetadataConfig, redisClient *redis.Client) *Chain {
+	providers := make([]Provider, 0, len(cfg.Providers))
+	for _, name := range cfg.Providers {
+		var provider Provider
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "openlibrary":
+			provider = NewOpenLibraryProvider(cfg.Timeout)
+		case "googlebooks":
+			provider = NewGoogleBooksProvider(cfg.GoogleBooksAPIKey, cfg.Timeout)
+		case "sru":
+			if cfg.SRUURL == "" {
+				log.Printf("Warning: metadata provider sru needs METADATA_SRU_URL, skipping")
+				continue
+			}
+			provider = NewSRUProvider(cfg.SRUURL, cfg.Timeout)
+		default:
+			log.Printf("Warning: unknown metadata provider %q, skipping", name)
+			continue
+		}
+		providers = append(providers, NewCachedProvider(provider, redisClient, cfg.CacheTTL))
+	}
+	return NewChain(providers...)
+}
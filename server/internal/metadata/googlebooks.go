@@ -0,0 +1,86 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+)
+
+const googleBooksURL = "https://www.googleapis.com/books/v1/volumes"
+
+type GoogleBooksProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+func NewGoogleBooksProvider(apiKey string, timeout time.Duration) *GoogleBooksProvider {
+	return &GoogleBooksProvider{client: newHTTPClient(timeout), baseURL: googleBooksURL, apiKey: apiKey}
+}
+
+func (p *GoogleBooksProvider) Name() string {
+	return "googlebooks"
+}
+
+type googleBooksResponse struct {
+	TotalItems int `json:"totalItems"`
+	Items      []struct {
+		VolumeInfo struct {
+			Title         string   `json:"title"`
+			Subtitle      string   `json:"subtitle"`
+			Authors       []string `json:"authors"`
+			Publisher     string   `json:"publisher"`
+			PublishedDate string   `json:"publishedDate"`
+			Description   string   `json:"description"`
+			Categories    []string `json:"categories"`
+			Language      string   `json:"language"`
+			ImageLinks    struct {
+				Thumbnail string `json:"thumbnail"`
+			} `json:"imageLinks"`
+		} `json:"volumeInfo"`
+	} `json:"items"`
+}
+
+func (p *GoogleBooksProvider) Lookup(ctx context.Context, isbn string) (*models.BookRequest, error) {
+	params := url.Values{}
+	params.Set("q", "isbn:"+isbn)
+	if p.apiKey != "" {
+		params.Set("key", p.apiKey)
+	}
+	
+	var response googleBooksResponse
+	if err := getJSON(ctx, p.client, p.baseURL+"?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+	
+	if len(response.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	
+	info := response.Items[0].VolumeInfo
+	book := &models.BookRequest{
+		ISBN:        isbn,
+		Title:       info.Title,
+		Author:      strings.Join(info.Authors, ", "),
+		Publisher:   info.Publisher,
+		PublishDate: parseDate(info.PublishedDate),
+		Description: info.Description,
+		Language:    info.Language,
+		CoverImage:  strings.Replace(info.ImageLinks.Thumbnail, "http://", "https://", 1),
+		Tags:        info.Categories,
+	}
+	
+	if info.Subtitle != "" {
+		book.Title += ": " + info.Subtitle
+	}
+	
+	if len(info.Categories) > 0 {
+		book.Category = info.Categories[0]
+	}
+	
+	return book, nil
+}
@@ -0,0 +1,52 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout}
+}
+
+func getJSON(ctx context.Context, client *http.Client, endpoint string, dest interface{}) error {
+	body, err := get(ctx, client, endpoint, "application/json")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	
+	return json.NewDecoder(body).Decode(dest)
+}
+
+func get(ctx context.Context, client *http.Client, endpoint, accept string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", "library-management-system")
+	
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	case resp.StatusCode >= 300:
+		resp.Body.Close()
+		return nil, fmt.Errorf("metadata provider returned %s", resp.Status)
+	}
+	
+	return resp.Body, nil
+}
@@ -0,0 +1,102 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+)
+
+const openLibraryURL = "https://openlibrary.org/api/books"
+
+type OpenLibraryProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewOpenLibraryProvider(timeout time.Duration) *OpenLibraryProvider {
+	return &OpenLibraryProvider{client: newHTTPClient(timeout), baseURL: openLibraryURL}
+}
+
+func (p *OpenLibraryProvider) Name() string {
+	return "openlibrary"
+}
+
+type openLibraryName struct {
+	Name string `json:"name"`
+}
+
+type openLibraryBook struct {
+	Title       string            `json:"title"`
+	Subtitle    string            `json:"subtitle"`
+	Authors     []openLibraryName `json:"authors"`
+	Publishers  []openLibraryName `json:"publishers"`
+	PublishDate string            `json:"publish_date"`
+	Subjects    []openLibraryName `json:"subjects"`
+	Cover       struct {
+		Large  string `json:"large"`
+		Medium string `json:"medium"`
+	} `json:"cover"`
+}
+
+func (p *OpenLibraryProvider) Lookup(ctx context.Context, isbn string) (*models.BookRequest, error) {
+	params := url.Values{}
+	params.Set("bibkeys", "ISBN:"+isbn)
+	params.Set("format", "json")
+	params.Set("jscmd", "data")
+	
+	var response map[string]openLibraryBook
+	if err := getJSON(ctx, p.client, p.baseURL+"?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+	
+	found, ok := response["ISBN:"+isbn]
+	if !ok || found.Title == "" {
+		return nil, ErrNotFound
+	}
+	
+	book := &models.BookRequest{
+		ISBN:        isbn,
+		Title:       found.Title,
+		PublishDate: parseDate(found.PublishDate),
+		CoverImage:  found.Cover.Large,
+	}
+	
+	if found.Subtitle != "" {
+		book.Title += ": " + found.Subtitle
+	}
+	
+	if book.CoverImage == "" {
+		book.CoverImage = found.Cover.Medium
+	}
+	
+	if len(found.Authors) > 0 {
+		book.Author = joinNames(found.Authors)
+	}
+	
+	if len(found.Publishers) > 0 {
+		book.Publisher = found.Publishers[0].Name
+	}
+	
+	for i, subject := range found.Subjects {
+		if i == 10 {
+			break
+		}
+		book.Tags = append(book.Tags, subject.Name)
+	}
+	
+	return book, nil
+}
+
+func joinNames(names []openLibraryName) string {
+	joined := ""
+	for i, n := range names {
+		if i > 0 {
+			joined += ", "
+		}
+		joined += n.Name
+	}
+	return joined
+}
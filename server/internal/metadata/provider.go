@@ -0,0 +1,188 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+)
+
+var ErrNotFound = errors.New("no metadata found for isbn")
+
+type Provider interface {
+	Name() string
+	Lookup(ctx context.Context, isbn string) (*models.BookRequest, error)
+}
+
+type Result struct {
+	ISBN     string             `json:"isbn"`
+	BookData models.BookRequest `json:"book_data"`
+	Sources  map[string]string  `json:"sources"`
+}
+
+type Chain struct {
+	providers []Provider
+}
+
+func NewChain(providers ...Provider) *Chain {
+	return &Chain{providers: providers}
+}
+
+func (c *Chain) Providers() []string {
+	names := make([]string, len(c.providers))
+	for i, p := range c.providers {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+func (c *Chain) Lookup(ctx context.Context, isbn string) (*Result, error) {
+	result := &Result{
+		ISBN:     isbn,
+		BookData: models.BookRequest{ISBN: isbn},
+		Sources:  make(map[string]string),
+	}
+	
+	var lastErr error
+	for _, provider := range c.providers {
+		found, err := provider.Lookup(ctx, isbn)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				lastErr = err
+			}
+			continue
+		}
+		
+		for _, f := range bookFields {
+			if f.empty(&result.BookData) && !f.empty(found) {
+				f.copy(&result.BookData, found)
+				result.Sources[f.name] = provider.Name()
+			}
+		}
+		
+		if len(result.Sources) == len(bookFields) {
+			break
+		}
+	}
+	
+	if len(result.Sources) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, ErrNotFound
+	}
+	
+	return result, nil
+}
+
+type FieldChange struct {
+	Field     string      `json:"field"`
+	Current   interface{} `json:"current"`
+	Suggested interface{} `json:"suggested"`
+	Source    string      `json:"source"`
+	Action    string      `json:"action"`
+}
+
+const (
+	ActionFill    = "fill"
+	ActionReplace = "replace"
+	ActionKeep    = "keep"
+)
+
+func Preview(current models.BookRequest, result *Result, overwrite bool) (models.BookRequest, []FieldChange) {
+	merged := current
+	changes := make([]FieldChange, 0)
+	
+	for _, f := range bookFields {
+		source, ok := result.Sources[f.name]
+		if !ok || f.equal(&current, &result.BookData) {
+			continue
+		}
+		
+		change := FieldChange{
+			Field:     f.name,
+			Current:   f.get(&current),
+			Suggested: f.get(&result.BookData),
+			Source:    source,
+			Action:    ActionKeep,
+		}
+		
+		switch {
+		case f.empty(&current):
+			change.Action = ActionFill
+			f.copy(&merged, &result.BookData)
+		case overwrite:
+			change.Action = ActionReplace
+			f.copy(&merged, &result.BookData)
+		}
+		
+		changes = append(changes, change)
+	}
+	
+	if merged.ISBN == "" {
+		merged.ISBN = result.ISBN
+	}
+	
+	return merged, changes
+}
+
+type bookField struct {
+	name  string
+	get   func(*models.BookRequest) interface{}
+	empty func(*models.BookRequest) bool
+	copy  func(dst, src *models.BookRequest)
+	equal func(a, b *models.BookRequest) bool
+}
+
+func stringField(name string, ref func(*models.BookRequest) *string) bookField {
+	return bookField{
+		name:  name,
+		get:   func(b *models.BookRequest) interface{} { return *ref(b) },
+		empty: func(b *models.BookRequest) bool { return strings.TrimSpace(*ref(b)) == "" },
+		copy:  func(dst, src *models.BookRequest) { *ref(dst) = *ref(src) },
+		equal: func(a, b *models.BookRequest) bool { return strings.EqualFold(*ref(a), *ref(b)) },
+	}
+}
+
+var bookFields = []bookField{
+	stringField("title", func(b *models.BookRequest) *string { return &b.Title }),
+	stringField("author", func(b *models.BookRequest) *string { return &b.Author }),
+	stringField("publisher", func(b *models.BookRequest) *string { return &b.Publisher }),
+	{
+		name:  "publish_date",
+		get:   func(b *models.BookRequest) interface{} { return b.PublishDate },
+		empty: func(b *models.BookRequest) bool { return b.PublishDate.IsZero() },
+		copy:  func(dst, src *models.BookRequest) { dst.PublishDate = src.PublishDate },
+		equal: func(a, b *models.BookRequest) bool { return a.PublishDate.Equal(b.PublishDate) },
+	},
+	stringField("category", func(b *models.BookRequest) *string { return &b.Category }),
+	stringField("language", func(b *models.BookRequest) *string { return &b.Language }),
+	stringField("description", func(b *models.BookRequest) *string { return &b.Description }),
+	stringField("cover_image", func(b *models.BookRequest) *string { return &b.CoverImage }),
+	{
+		name:  "tags",
+		get:   func(b *models.BookRequest) interface{} { return b.Tags },
+		empty: func(b *models.BookRequest) bool { return len(b.Tags) == 0 },
+		copy:  func(dst, src *models.BookRequest) { dst.Tags = append([]string(nil), src.Tags...) },
+		equal: func(a, b *models.BookRequest) bool {
+			return strings.Join(a.Tags, "\x00") == strings.Join(b.Tags, "\x00")
+		},
+	},
+}
+
+func parseDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02", "2006-01", "January 2, 2006", "Jan 2, 2006", "January 2006", "2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	
+	if year := yearPattern.FindString(value); year != "" {
+		t, _ := time.Parse("2006", year)
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,174 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+)
+
+const testISBN = "9780140444308"
+
+type failingProvider struct {
+	err   error
+	calls int
+}
+
+func (p *failingProvider) Name() string {
+	return "failing"
+}
+
+func (p *failingProvider) Lookup(ctx context.Context, isbn string) (*models.BookRequest, error) {
+	p.calls++
+	return nil, p.err
+}
+
+func TestChainFallbackOrder(t *testing.T) {
+	first := NewStaticProvider("first", map[string]models.BookRequest{
+		testISBN: {Title: "Les Misérables"},
+	})
+	broken := &failingProvider{err: errors.New("upstream timeout")}
+	second := NewStaticProvider("second", map[string]models.BookRequest{
+		testISBN: {Title: "Les Miserables", Author: "Victor Hugo", Publisher: "Penguin"},
+	})
+	
+	chain := NewChain(first, broken, second)
+	if got, want := chain.Providers(), []string{"first", "failing", "second"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("providers = %v, want %v", got, want)
+	}
+	
+	result, err := chain.Lookup(context.Background(), testISBN)
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	
+	if result.BookData.Title != "Les Misérables" || result.Sources["title"] != "first" {
+		t.Errorf("title = %q from %q, want the first provider's", result.BookData.Title, result.Sources["title"])
+	}
+	if result.BookData.Author != "Victor Hugo" || result.Sources["author"] != "second" {
+		t.Errorf("author = %q from %q, want the second provider's", result.BookData.Author, result.Sources["author"])
+	}
+	if result.Sources["publisher"] != "second" {
+		t.Errorf("publisher source = %q, want second", result.Sources["publisher"])
+	}
+	if result.BookData.ISBN != testISBN {
+		t.Errorf("isbn = %q, want %q", result.BookData.ISBN, testISBN)
+	}
+	if first.Calls() != 1 || broken.calls != 1 || second.Calls() != 1 {
+		t.Errorf("calls = %d, %d, %d, want one each", first.Calls(), broken.calls, second.Calls())
+	}
+}
+
+func TestChainStopsWhenComplete(t *testing.T) {
+	complete := models.BookRequest{
+		Title:       "Les Misérables",
+		Author:      "Victor Hugo",
+		Publisher:   "Penguin",
+		PublishDate: time.Date(1982, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Category:    "Fiction",
+		Language:    "en",
+		Description: "Jean Valjean's story.",
+		CoverImage:  "https://covers.example/1.jpg",
+		Tags:        []string{"France"},
+	}
+	first := NewStaticProvider("first", map[string]models.BookRequest{testISBN: complete})
+	second := NewStaticProvider("second", nil)
+	
+	if _, err := NewChain(first, second).Lookup(context.Background(), testISBN); err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if second.Calls() != 0 {
+		t.Errorf("second provider called %d times after the first filled every field", second.Calls())
+	}
+}
+
+func TestChainNotFound(t *testing.T) {
+	empty := NewStaticProvider("empty", nil)
+	if _, err := NewChain(empty).Lookup(context.Background(), testISBN); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error = %v, want %v", err, ErrNotFound)
+	}
+	
+	upstream := errors.New("upstream timeout")
+	if _, err := NewChain(empty, &failingProvider{err: upstream}).Lookup(context.Background(), testISBN); !errors.Is(err, upstream) {
+		t.Errorf("error = %v, want the provider error", err)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	result := &Result{
+		ISBN: testISBN,
+		BookData: models.BookRequest{
+			ISBN:      testISBN,
+			Title:     "Les Misérables",
+			Author:    "Victor Hugo",
+			Publisher: "Penguin Classics",
+			Category:  "Fiction",
+		},
+		Sources: map[string]string{
+			"title":     "first",
+			"author":    "second",
+			"publisher": "second",
+			"category":  "first",
+		},
+	}
+	current := models.BookRequest{
+		Title:     "Les Miserables",
+		Author:    "VICTOR HUGO",
+		Publisher: "Penguin",
+	}
+	
+	tests := []struct {
+		name      string
+		overwrite bool
+		title     string
+		publisher string
+		actions   map[string]string
+	}{
+		{
+			name:      "fill only",
+			title:     "Les Miserables",
+			publisher: "Penguin",
+			actions:   map[string]string{"title": ActionKeep, "publisher": ActionKeep, "category": ActionFill},
+		},
+		{
+			name:      "overwrite",
+			overwrite: true,
+			title:     "Les Misérables",
+			publisher: "Penguin Classics",
+			actions:   map[string]string{"title": ActionReplace, "publisher": ActionReplace, "category": ActionFill},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, changes := Preview(current, result, tt.overwrite)
+			
+			actions := map[string]string{}
+			for _, change := range changes {
+				actions[change.Field] = change.Action
+				if change.Source != result.Sources[change.Field] {
+					t.Errorf("%s source = %q, want %q", change.Field, change.Source, result.Sources[change.Field])
+				}
+			}
+			if !reflect.DeepEqual(actions, tt.actions) {
+				t.Errorf("actions = %v, want %v", actions, tt.actions)
+			}
+			
+			if merged.Title != tt.title || merged.Publisher != tt.publisher {
+				t.Errorf("merged title/publisher = %q/%q, want %q/%q", merged.Title, merged.Publisher, tt.title, tt.publisher)
+			}
+			if merged.Author != current.Author {
+				t.Errorf("author differing only in case was changed to %q", merged.Author)
+			}
+			if merged.Category != "Fiction" {
+				t.Errorf("category = %q, want it filled", merged.Category)
+			}
+			if merged.ISBN != testISBN {
+				t.Errorf("isbn = %q, want %q", merged.ISBN, testISBN)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/library-management-system/server/internal/marc"
+	"github.com/library-management-system/server/internal/models"
+)
+
+type SRUProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewSRUProvider(baseURL string, timeout time.Duration) *SRUProvider {
+	return &SRUProvider{client: newHTTPClient(timeout), baseURL: baseURL}
+}
+
+func (p *SRUProvider) Name() string {
+	return "sru"
+}
+
+func (p *SRUProvider) Lookup(ctx context.Context, isbn string) (*models.BookRequest, error) {
+	params := url.Values{}
+	params.Set("operation", "searchRetrieve")
+	params.Set("version", "1.1")
+	params.Set("query", "bath.isbn="+isbn)
+	params.Set("recordSchema", "marcxml")
+	params.Set("maximumRecords", "1")
+	
+	body, err := get(ctx, p.client, p.baseURL+"?"+params.Encode(), "application/xml")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	
+	records, err := marc.ReadXML(body)
+	if err != nil {
+		return nil, err
+	}
+	
+	if len(records) == 0 {
+		return nil, ErrNotFound
+	}
+	
+	found := marc.ToBook(records[0])
+	return &models.BookRequest{
+		ISBN:        isbn,
+		Title:       found.Title,
+		Author:      found.Author,
+		Publisher:   found.Publisher,
+		PublishDate: found.PublishDate,
+		Category:    found.Category,
+		Language:    found.Language,
+		Description: found.Description,
+		Tags:        found.Tags,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package metadata
+
+import (
+	"context"
+	"sync"
+
+	"github.com/library-management-system/server/internal/models"
+)
+
+type StaticProvider struct {
+	name    string
+	mu      sync.RWMutex
+	records map[string]models.BookRequest
+	calls   int
+}
+
+func NewStaticProvider(name string, records map[string]models.BookRequest) *StaticProvider {
+	if records == nil {
+		records = make(map[string]models.BookRequest)
+	}
+	return &StaticProvider{name: name, records: records}
+}
+
+func (p *StaticProvider) Name() string {
+	return p.name
+}
+
+func (p *StaticProvider) Add(isbn string, book models.BookRequest) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.records[isbn] = book
+}
+
+func (p *StaticProvider) Calls() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.calls
+}
+
+func (p *StaticProvider) Lookup(ctx context.Context, isbn string) (*models.BookRequest, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	
+	book, ok := p.records[isbn]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &book, nil
+}
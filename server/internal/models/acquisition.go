@@ -0,0 +1,281 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrderStatus string
+
+const (
+	OrderDraft             OrderStatus = "draft"
+	OrderPlaced            OrderStatus = "ordered"
+	OrderPartiallyReceived OrderStatus = "partially_received"
+	OrderReceived          OrderStatus = "received"
+	OrderCancelled         OrderStatus = "cancelled"
+)
+
+type InvoiceStatus string
+
+const (
+	InvoiceOpen      InvoiceStatus = "open"
+	InvoicePaid      InvoiceStatus = "paid"
+	InvoiceCancelled InvoiceStatus = "cancelled"
+)
+
+type Vendor struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Code          string         `gorm:"type:varchar(20);uniqueIndex;not null" json:"code"`
+	Name          string         `gorm:"not null" json:"name"`
+	ContactName   string         `json:"contact_name"`
+	Email         string         `json:"email"`
+	Phone         string         `json:"phone"`
+	Address       string         `json:"address"`
+	AccountNumber string         `json:"account_number"`
+	IsActive      bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type Fund struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Code       string         `gorm:"type:varchar(20);not null;uniqueIndex:idx_funds_code_year" json:"code"`
+	Name       string         `gorm:"not null" json:"name"`
+	FiscalYear int            `gorm:"not null;uniqueIndex:idx_funds_code_year;index" json:"fiscal_year"`
+	BranchID   *uuid.UUID     `gorm:"type:uuid;index" json:"branch_id"`
+	Allocated  float64        `gorm:"default:0" json:"allocated"`
+	IsActive   bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type PurchaseOrder struct {
+	ID          uuid.UUID   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Number      string      `gorm:"type:varchar(30);uniqueIndex;not null" json:"number"`
+	VendorID    uuid.UUID   `gorm:"type:uuid;not null;index" json:"vendor_id"`
+	BranchID    uuid.UUID   `gorm:"type:uuid;not null;index" json:"branch_id"`
+	Status      OrderStatus `gorm:"type:varchar(20);default:'draft';index" json:"status"`
+	Notes       string      `gorm:"type:text" json:"notes"`
+	CreatedByID uuid.UUID   `gorm:"type:uuid;not null" json:"created_by_id"`
+	OrderedAt   *time.Time  `json:"ordered_at"`
+	ClosedAt    *time.Time  `json:"closed_at"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	
+	Vendor Vendor              `gorm:"foreignKey:VendorID" json:"vendor,omitempty"`
+	Lines  []PurchaseOrderLine `gorm:"foreignKey:OrderID" json:"lines,omitempty"`
+}
+
+type PurchaseOrderLine struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrderID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"order_id"`
+	FundID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"fund_id"`
+	BookID         *uuid.UUID `gorm:"type:uuid;index" json:"book_id"`
+	ISBN           string     `gorm:"index" json:"isbn"`
+	Title          string     `gorm:"not null" json:"title"`
+	Author         string     `json:"author"`
+	Publisher      string     `json:"publisher"`
+	CallNumber     string     `json:"call_number"`
+	Classification string     `gorm:"type:varchar(10)" json:"classification"`
+	Quantity       int        `gorm:"not null" json:"quantity"`
+	UnitPrice      float64    `gorm:"default:0" json:"unit_price"`
+	Received       int        `gorm:"default:0" json:"received"`
+	Cancelled      int        `gorm:"default:0" json:"cancelled"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type Invoice struct {
+	ID          uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	VendorID    uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_invoices_vendor_number" json:"vendor_id"`
+	OrderID     *uuid.UUID    `gorm:"type:uuid;index" json:"order_id"`
+	Number      string        `gorm:"not null;uniqueIndex:idx_invoices_vendor_number" json:"number"`
+	InvoiceDate time.Time     `json:"invoice_date"`
+	Status      InvoiceStatus `gorm:"type:varchar(20);default:'open';index" json:"status"`
+	Total       float64       `gorm:"default:0" json:"total"`
+	PaidAt      *time.Time    `json:"paid_at"`
+	CreatedByID uuid.UUID     `gorm:"type:uuid;not null" json:"created_by_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	
+	Lines []InvoiceLine `gorm:"foreignKey:InvoiceID" json:"lines,omitempty"`
+}
+
+type InvoiceLine struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	InvoiceID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"invoice_id"`
+	OrderLineID *uuid.UUID `gorm:"type:uuid;index" json:"order_line_id"`
+	FundID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"fund_id"`
+	Description string     `json:"description"`
+	Quantity    int        `gorm:"default:1" json:"quantity"`
+	Amount      float64    `gorm:"not null" json:"amount"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (v *Vendor) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
+func (f *Fund) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
+
+func (o *PurchaseOrder) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return nil
+}
+
+func (l *PurchaseOrderLine) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+func (i *Invoice) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+func (l *InvoiceLine) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+func (l *PurchaseOrderLine) Outstanding() int {
+	outstanding := l.Quantity - l.Received - l.Cancelled
+	if outstanding < 0 {
+		return 0
+	}
+	return outstanding
+}
+
+func (l *PurchaseOrderLine) Cost() float64 {
+	return float64(l.Quantity-l.Cancelled) * l.UnitPrice
+}
+
+func (o *PurchaseOrder) IsOpen() bool {
+	return o.Status == OrderPlaced || o.Status == OrderPartiallyReceived
+}
+
+func (o *PurchaseOrder) Total() float64 {
+	total := 0.0
+	for i := range o.Lines {
+		total += o.Lines[i].Cost()
+	}
+	return total
+}
+
+type VendorRequest struct {
+	Code          string `json:"code" binding:"required,max=20"`
+	Name          string `json:"name" binding:"required"`
+	ContactName   string `json:"contact_name"`
+	Email         string `json:"email"`
+	Phone         string `json:"phone"`
+	Address       string `json:"address"`
+	AccountNumber string `json:"account_number"`
+	IsActive      *bool  `json:"is_active"`
+}
+
+type FundRequest struct {
+	Code       string  `json:"code" binding:"required,max=20"`
+	Name       string  `json:"name" binding:"required"`
+	FiscalYear int     `json:"fiscal_year" binding:"required,min=1900"`
+	BranchID   string  `json:"branch_id"`
+	Allocated  float64 `json:"allocated" binding:"min=0"`
+	IsActive   *bool   `json:"is_active"`
+}
+
+type OrderLineRequest struct {
+	FundID         string  `json:"fund_id"`
+	BookID         string  `json:"book_id"`
+	ISBN           string  `json:"isbn"`
+	Title          string  `json:"title"`
+	Author         string  `json:"author"`
+	Publisher      string  `json:"publisher"`
+	CallNumber     string  `json:"call_number"`
+	Classification string  `json:"classification"`
+	Quantity       int     `json:"quantity" binding:"min=1"`
+	UnitPrice      float64 `json:"unit_price" binding:"min=0"`
+}
+
+type OrderRequest struct {
+	VendorID string             `json:"vendor_id" binding:"required"`
+	BranchID string             `json:"branch_id"`
+	FundID   string             `json:"fund_id"`
+	Notes    string             `json:"notes"`
+	Lines    []OrderLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type ReceiveLineRequest struct {
+	LineID   string `json:"line_id" binding:"required"`
+	Quantity int    `json:"quantity" binding:"min=1"`
+}
+
+type ReceiveRequest struct {
+	OrderID    string               `json:"order_id" binding:"required"`
+	LocationID string               `json:"location_id"`
+	Lines      []ReceiveLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type InvoiceLineRequest struct {
+	OrderLineID string  `json:"order_line_id"`
+	FundID      string  `json:"fund_id"`
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	Amount      float64 `json:"amount" binding:"min=0"`
+}
+
+type InvoiceRequest struct {
+	VendorID    string               `json:"vendor_id" binding:"required"`
+	OrderID     string               `json:"order_id"`
+	Number      string               `json:"number" binding:"required"`
+	InvoiceDate time.Time            `json:"invoice_date"`
+	Lines       []InvoiceLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type ReceivedLine struct {
+	LineID      string  `json:"line_id"`
+	BookID      string  `json:"book_id"`
+	Title       string  `json:"title"`
+	Quantity    int     `json:"quantity"`
+	Created     bool    `json:"created"`
+	HoldsFilled int     `json:"holds_filled"`
+	Suggestions int     `json:"suggestions_fulfilled"`
+	Location    string  `json:"location,omitempty"`
+	Outstanding int     `json:"outstanding"`
+	Cost        float64 `json:"cost"`
+}
+
+type ReceiveResult struct {
+	Order *PurchaseOrder `json:"order"`
+	Lines []ReceivedLine `json:"lines"`
+}
+
+type FundSummary struct {
+	FundID     string  `json:"fund_id"`
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	FiscalYear int     `json:"fiscal_year"`
+	Allocated  float64 `json:"allocated"`
+	Encumbered float64 `json:"encumbered"`
+	Expended   float64 `json:"expended"`
+	Paid       float64 `json:"paid"`
+	Available  float64 `json:"available"`
+}
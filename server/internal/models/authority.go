@@ -0,0 +1,236 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type AuthorityType string
+
+const (
+	AuthorityAuthor    AuthorityType = "author"
+	AuthorityPublisher AuthorityType = "publisher"
+	AuthoritySeries    AuthorityType = "series"
+	AuthoritySubject   AuthorityType = "subject"
+)
+
+type ContributorRole string
+
+const (
+	ContributorAuthor      ContributorRole = "author"
+	ContributorEditor      ContributorRole = "editor"
+	ContributorTranslator  ContributorRole = "translator"
+	ContributorIllustrator ContributorRole = "illustrator"
+)
+
+type Author struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name       string         `gorm:"not null" json:"name"`
+	SortName   string         `gorm:"index" json:"sort_name"`
+	NameKey    string         `gorm:"not null;uniqueIndex" json:"-"`
+	AltNames   pq.StringArray `gorm:"type:text[]" json:"alt_names"`
+	AltKeys    pq.StringArray `gorm:"type:text[]" json:"-"`
+	Dates      string         `json:"dates"`
+	Identifier string         `json:"identifier"`
+	Notes      string         `gorm:"type:text" json:"notes"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+type Publisher struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name       string         `gorm:"not null" json:"name"`
+	SortName   string         `gorm:"index" json:"sort_name"`
+	NameKey    string         `gorm:"not null;uniqueIndex" json:"-"`
+	AltNames   pq.StringArray `gorm:"type:text[]" json:"alt_names"`
+	AltKeys    pq.StringArray `gorm:"type:text[]" json:"-"`
+	Place      string         `json:"place"`
+	Identifier string         `json:"identifier"`
+	Notes      string         `gorm:"type:text" json:"notes"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+type Series struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name       string         `gorm:"not null" json:"name"`
+	SortName   string         `gorm:"index" json:"sort_name"`
+	NameKey    string         `gorm:"not null;uniqueIndex" json:"-"`
+	AltNames   pq.StringArray `gorm:"type:text[]" json:"alt_names"`
+	AltKeys    pq.StringArray `gorm:"type:text[]" json:"-"`
+	Identifier string         `json:"identifier"`
+	Notes      string         `gorm:"type:text" json:"notes"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+type Subject struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name       string         `gorm:"not null" json:"name"`
+	SortName   string         `gorm:"index" json:"sort_name"`
+	NameKey    string         `gorm:"not null;uniqueIndex" json:"-"`
+	AltNames   pq.StringArray `gorm:"type:text[]" json:"alt_names"`
+	AltKeys    pq.StringArray `gorm:"type:text[]" json:"-"`
+	Scheme     string         `gorm:"type:varchar(20);default:'local';index" json:"scheme"`
+	Identifier string         `json:"identifier"`
+	Notes      string         `gorm:"type:text" json:"notes"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+type BookAuthor struct {
+	BookID   uuid.UUID       `gorm:"type:uuid;primaryKey" json:"book_id"`
+	AuthorID uuid.UUID       `gorm:"type:uuid;primaryKey;index" json:"author_id"`
+	Role     ContributorRole `gorm:"type:varchar(20);primaryKey;default:'author'" json:"role"`
+	Position int             `gorm:"default:0" json:"position"`
+}
+
+type BookPublisher struct {
+	BookID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"book_id"`
+	PublisherID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"publisher_id"`
+	Position    int       `gorm:"default:0" json:"position"`
+}
+
+type BookSeries struct {
+	BookID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"book_id"`
+	SeriesID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"series_id"`
+	Volume   float64   `gorm:"default:0" json:"volume"`
+	Position int       `gorm:"default:0" json:"position"`
+}
+
+type BookSubject struct {
+	BookID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"book_id"`
+	SubjectID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"subject_id"`
+	Position  int       `gorm:"default:0" json:"position"`
+}
+
+func (a *Author) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+func (p *Publisher) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *Series) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *Subject) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t AuthorityType) IsValid() bool {
+	switch t {
+	case AuthorityAuthor, AuthorityPublisher, AuthoritySeries, AuthoritySubject:
+		return true
+	}
+	return false
+}
+
+func (r ContributorRole) IsValid() bool {
+	switch r {
+	case ContributorAuthor, ContributorEditor, ContributorTranslator, ContributorIllustrator:
+		return true
+	}
+	return false
+}
+
+type AuthorityRef struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+type ContributorRef struct {
+	AuthorityRef
+	Role ContributorRole `json:"role,omitempty"`
+}
+
+type SeriesRef struct {
+	AuthorityRef
+	Volume float64 `json:"volume,omitempty"`
+}
+
+type AuthorityRequest struct {
+	Type       AuthorityType `json:"type" binding:"required"`
+	ID         string        `json:"id" binding:"required"`
+	Name       string        `json:"name" binding:"required"`
+	SortName   string        `json:"sort_name"`
+	AltNames   []string      `json:"alt_names"`
+	Dates      string        `json:"dates"`
+	Place      string        `json:"place"`
+	Scheme     string        `json:"scheme"`
+	Identifier string        `json:"identifier"`
+	Notes      string        `json:"notes"`
+}
+
+type AuthorityResponse struct {
+	ID         string         `json:"id"`
+	Type       AuthorityType  `json:"type" gorm:"-"`
+	Name       string         `json:"name"`
+	SortName   string         `json:"sort_name"`
+	AltNames   pq.StringArray `json:"alt_names"`
+	Dates      string         `json:"dates,omitempty"`
+	Place      string         `json:"place,omitempty"`
+	Scheme     string         `json:"scheme,omitempty"`
+	Identifier string         `json:"identifier,omitempty"`
+	Notes      string         `json:"notes,omitempty"`
+	BookCount  int64          `json:"book_count"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+func (r AuthorityResponse) CursorID() string {
+	return r.ID
+}
+
+func (r AuthorityResponse) SortValue(field string) interface{} {
+	if field == "sort_name" {
+		return r.SortName
+	}
+	return r.CreatedAt
+}
+
+type AuthorityBook struct {
+	ID          string          `json:"id"`
+	Title       string          `json:"title"`
+	ISBN        string          `json:"isbn"`
+	PublishDate time.Time       `json:"publish_date"`
+	CoverImage  string          `json:"cover_image"`
+	Role        ContributorRole `json:"role,omitempty"`
+	Volume      float64         `json:"volume,omitempty"`
+}
+
+type AuthorityVariant struct {
+	Type     AuthorityType `json:"type"`
+	Name     string        `json:"name"`
+	Variants []string      `json:"variants"`
+}
+
+type AuthorityMigrationReport struct {
+	DryRun      bool                    `json:"dry_run"`
+	BooksLinked int                     `json:"books_linked"`
+	Created     map[AuthorityType]int64 `json:"created"`
+	Variants    []AuthorityVariant      `json:"variants"`
+}
+
+type DuplicateSuggestion struct {
+	Type       AuthorityType  `json:"type"`
+	Reason     string         `json:"reason"`
+	Similarity float64        `json:"similarity"`
+	Records    []AuthorityRef `json:"records"`
+}
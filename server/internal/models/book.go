@@ -22,16 +22,25 @@ type Book struct {
 	ID              uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	Title           string         `gorm:"not null;index" json:"title"`
 	Author          string         `gorm:"not null;index" json:"author"`
-	ISBN            string         `gorm:"uniqueIndex" json:"isbn"`
+	ISBN            string         `gorm:"uniqueIndex:idx_books_isbn_unique,where:isbn <> ''" json:"isbn"`
+	Barcode         string         `gorm:"uniqueIndex:idx_books_barcode_unique,where:barcode <> ''" json:"barcode"`
 	Publisher       string         `json:"publisher"`
 	PublishDate     time.Time      `json:"publish_date"`
 	Category        string         `gorm:"index" json:"category"`
+	Language        string         `gorm:"type:varchar(10);default:'en';index" json:"language"`
 	Description     string         `gorm:"type:text" json:"description"`
 	CoverImage      string         `json:"cover_image"`
+	CoverVersion    string         `gorm:"type:varchar(32)" json:"cover_version,omitempty"`
 	TotalCopies     int            `gorm:"default:1" json:"total_copies"`
 	AvailableCopies int            `gorm:"default:1" json:"available_copies"`
+	ReplacementCost float64        `gorm:"default:0" json:"replacement_cost"`
 	Location        string         `json:"location"`
+	CallNumber      string         `json:"call_number"`
+	Classification  string         `gorm:"type:varchar(10)" json:"classification"`
+	CallNumberSort  string         `gorm:"index" json:"-"`
 	Status          BookStatus     `gorm:"type:varchar(20);default:'available'" json:"status"`
+	ItemType        ItemType       `gorm:"type:varchar(20);default:'book';index" json:"item_type"`
+	Attributes      ItemAttributes `gorm:"type:jsonb;default:'{}'" json:"attributes"`
 	Tags            pq.StringArray `gorm:"type:text[]" json:"tags"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
@@ -41,6 +50,15 @@ type Book struct {
 	Reservations    []Reservation  `gorm:"foreignKey:BookID" json:"reservations,omitempty"`
 }
 
+// BookCopy gives a single physical copy of a title its own barcode, so
+// circulation and stocktakes can tell copies apart.
+type BookCopy struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BookID    uuid.UUID `gorm:"type:uuid;not null;index" json:"book_id"`
+	Barcode   string    `gorm:"not null;uniqueIndex" json:"barcode"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 func (b *Book) BeforeCreate(tx *gorm.DB) error {
 	if b.ID == uuid.Nil {
 		b.ID = uuid.New()
@@ -48,6 +66,13 @@ func (b *Book) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+func (c *BookCopy) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
 func (b *Book) IsAvailable() bool {
 	return b.AvailableCopies > 0 && b.Status == BookStatusAvailable
 }
@@ -63,24 +88,79 @@ func (b *Book) DecrementAvailable() {
 
 func (b *Book) IncrementAvailable() {
 	b.AvailableCopies++
-	if b.AvailableCopies > 0 && b.Status == BookStatusLoaned {
+	if b.AvailableCopies > 0 && (b.Status == BookStatusLoaned || b.Status == BookStatusReserved || b.Status == BookStatusDamaged) {
 		b.Status = BookStatusAvailable
 	}
 }
 
+func (b *Book) WithdrawCopy() {
+	if b.TotalCopies > 0 {
+		b.TotalCopies--
+	}
+	if b.TotalCopies == 0 {
+		b.Status = BookStatusLost
+	}
+}
+
+func (b *Book) RestoreCopy() {
+	b.TotalCopies++
+	if b.Status == BookStatusLost || b.Status == BookStatusDamaged {
+		b.Status = BookStatusLoaned
+	}
+}
+
+func (b *Book) LoseShelfCopy() {
+	if b.AvailableCopies > 0 {
+		b.AvailableCopies--
+	}
+	b.WithdrawCopy()
+	if b.AvailableCopies == 0 && b.Status == BookStatusAvailable {
+		b.Status = BookStatusLoaned
+	}
+}
+
+func (b *Book) PullShelfCopy() {
+	if b.AvailableCopies > 0 {
+		b.AvailableCopies--
+	}
+	if b.AvailableCopies == 0 && b.Status == BookStatusAvailable {
+		b.Status = BookStatusLoaned
+	}
+}
+
+func (b *Book) HoldReturnedCopy() {
+	if b.AvailableCopies == 0 {
+		b.Status = BookStatusReserved
+	}
+}
+
 type BookRequest struct {
 	Title           string    `json:"title" binding:"required"`
-	Author          string    `json:"author" binding:"required"`
-	ISBN            string    `json:"isbn" binding:"required"`
+	Author          string    `json:"author" binding:"required_without=Contributors"`
+	ISBN            string    `json:"isbn"`
+	Barcode         string    `json:"barcode"`
+	CopyBarcodes    []string  `json:"copy_barcodes"`
 	Publisher       string    `json:"publisher"`
 	PublishDate     time.Time `json:"publish_date"`
 	Category        string    `json:"category"`
+	Language        string    `json:"language"`
 	Description     string    `json:"description"`
 	CoverImage      string    `json:"cover_image"`
 	TotalCopies     int       `json:"total_copies" binding:"min=1"`
 	AvailableCopies int       `json:"available_copies"`
-	Location        string    `json:"location"`
-	Tags            []string  `json:"tags"`
+	ReplacementCost float64   `json:"replacement_cost" binding:"min=0"`
+	Location        string           `json:"location"`
+	LocationID      string           `json:"location_id"`
+	CallNumber      string           `json:"call_number"`
+	Classification  string           `json:"classification"`
+	BranchID        string           `json:"branch_id"`
+	ItemType        ItemType         `json:"item_type"`
+	Attributes      ItemAttributes   `json:"attributes"`
+	Tags            []string         `json:"tags"`
+	Contributors    []ContributorRef `json:"contributors"`
+	Publishers      []AuthorityRef   `json:"publishers"`
+	Series          []SeriesRef      `json:"series"`
+	Subjects        []AuthorityRef   `json:"subjects"`
 }
 
 type BookResponse struct {
@@ -88,22 +168,75 @@ type BookResponse struct {
 	Title           string         `json:"title"`
 	Author          string         `json:"author"`
 	ISBN            string         `json:"isbn"`
+	ISBN10          string         `json:"isbn10,omitempty"`
+	Barcode         string         `json:"barcode"`
+	CopyBarcodes    []string       `json:"copy_barcodes,omitempty"`
 	Publisher       string         `json:"publisher"`
 	PublishDate     time.Time      `json:"publish_date"`
 	Category        string         `json:"category"`
+	Language        string         `json:"language"`
 	Description     string         `json:"description"`
 	CoverImage      string         `json:"cover_image"`
 	TotalCopies     int            `json:"total_copies"`
 	AvailableCopies int            `json:"available_copies"`
+	ReplacementCost float64        `json:"replacement_cost"`
 	Location        string         `json:"location"`
+	CallNumber      string         `json:"call_number,omitempty"`
+	Classification  string         `json:"classification,omitempty"`
+	ShelfOrder      string         `json:"shelf_order,omitempty"`
 	Status          string         `json:"status"`
 	Tags            []string       `json:"tags"`
+	ItemType        ItemType       `json:"item_type"`
+	Attributes      ItemAttributes `json:"attributes,omitempty"`
+	Contributors    []ContributorRef `json:"contributors,omitempty"`
+	Publishers      []AuthorityRef   `json:"publishers,omitempty"`
+	Series          []SeriesRef      `json:"series,omitempty"`
+	Subjects        []AuthorityRef   `json:"subjects,omitempty"`
 	IsAvailable     bool           `json:"is_available"`
 	CurrentLoanID   *string        `json:"current_loan_id,omitempty"`
 	ReservationIDs  []string       `json:"reservation_queue,omitempty"`
+	Rank            float64        `json:"rank,omitempty"`
+	Highlight       *BookHighlight `json:"highlight,omitempty"`
 	CreatedAt       time.Time      `json:"created_at"`
 }
 
+func (r BookResponse) CursorID() string {
+	return r.ID
+}
+
+func (r BookResponse) SortValue(field string) interface{} {
+	switch field {
+	case "title":
+		return r.Title
+	case "author":
+		return r.Author
+	case "isbn":
+		return r.ISBN
+	case "publish_date":
+		return r.PublishDate
+	case "available_copies":
+		return r.AvailableCopies
+	case "shelf_order":
+		return r.ShelfOrder
+	case "relevance":
+		return r.Rank
+	default:
+		return r.CreatedAt
+	}
+}
+
+type BookHighlight struct {
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+}
+
+type ShelfList struct {
+	CallNumber     string         `json:"call_number"`
+	Classification string         `json:"classification"`
+	Before         []BookResponse `json:"before"`
+	After          []BookResponse `json:"after"`
+}
+
 type BookStatistics struct {
 	TotalBooks      int64 `json:"total_books"`
 	AvailableBooks  int64 `json:"available_books"`
@@ -113,4 +246,23 @@ type BookStatistics struct {
 	DamagedBooks    int64 `json:"damaged_books"`
 	TotalCopies     int64 `json:"total_copies"`
 	AvailableCopies int64 `json:"available_copies"`
+}
+
+type ISBNIssue struct {
+	BookID  string `json:"book_id"`
+	Title   string `json:"title"`
+	ISBN    string `json:"isbn"`
+	Error   string `json:"error,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+type ISBNConflict struct {
+	ISBN  string      `json:"isbn"`
+	Books []ISBNIssue `json:"books"`
+}
+
+type ISBNReport struct {
+	Normalized int            `json:"normalized"`
+	Invalid    []ISBNIssue    `json:"invalid"`
+	Conflicts  []ISBNConflict `json:"conflicts"`
 }
\ No newline at end of file
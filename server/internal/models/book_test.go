@@ -0,0 +1,55 @@
+package models
+
+import "testing"
+
+func TestDamageRepairCheckout(t *testing.T) {
+	tests := []struct {
+		name   string
+		book   Book
+		damage func(b *Book)
+	}{
+		{
+			name:   "last shelf copy pulled for repair",
+			book:   Book{TotalCopies: 1, AvailableCopies: 1, Status: BookStatusAvailable},
+			damage: (*Book).PullShelfCopy,
+		},
+		{
+			name:   "one of two shelf copies pulled for repair",
+			book:   Book{TotalCopies: 2, AvailableCopies: 1, Status: BookStatusAvailable},
+			damage: (*Book).PullShelfCopy,
+		},
+		{
+			name:   "damaged copy returned while none are on the shelf",
+			book:   Book{TotalCopies: 1, AvailableCopies: 0, Status: BookStatusLoaned},
+			damage: func(b *Book) {},
+		},
+		{
+			name:   "title left damaged by an earlier release",
+			book:   Book{TotalCopies: 1, AvailableCopies: 0, Status: BookStatusDamaged},
+			damage: func(b *Book) {},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := tt.book
+			tt.damage(&book)
+			if book.AvailableCopies == 0 && book.IsAvailable() {
+				t.Fatalf("book with no shelf copies reported available")
+			}
+			if book.Status == BookStatusDamaged && tt.book.Status != BookStatusDamaged {
+				t.Fatalf("one damaged copy marked the whole title damaged")
+			}
+			
+			book.IncrementAvailable()
+			if !book.IsAvailable() {
+				t.Fatalf("repaired copy not available: status %q, %d available", book.Status, book.AvailableCopies)
+			}
+			
+			book.DecrementAvailable()
+			if book.AvailableCopies == 0 && book.Status != BookStatusLoaned {
+				t.Fatalf("checkout of last copy left status %q", book.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TransferStatus string
+
+const (
+	TransferRequested TransferStatus = "requested"
+	TransferInTransit TransferStatus = "in_transit"
+	TransferReceived  TransferStatus = "received"
+	TransferCancelled TransferStatus = "cancelled"
+)
+
+type TransferReason string
+
+const (
+	TransferReasonReturn  TransferReason = "return"
+	TransferReasonHold    TransferReason = "hold"
+	TransferReasonRequest TransferReason = "request"
+)
+
+type Branch struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Code      string         `gorm:"type:varchar(20);uniqueIndex;not null" json:"code"`
+	Name      string         `gorm:"not null" json:"name"`
+	Address   string         `json:"address"`
+	Phone     string         `json:"phone"`
+	Email     string         `json:"email"`
+	IsDefault bool           `gorm:"default:false" json:"is_default"`
+	IsActive  bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type BookHolding struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BookID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_book_holdings_book_branch" json:"book_id"`
+	BranchID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_book_holdings_book_branch;index" json:"branch_id"`
+	LocationID  *uuid.UUID `gorm:"type:uuid;index" json:"location_id"`
+	TotalCopies int        `gorm:"default:0" json:"total_copies"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	
+	Branch   Branch    `gorm:"foreignKey:BranchID" json:"branch,omitempty"`
+	Location *Location `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+}
+
+type Transfer struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BookID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"book_id"`
+	OwningBranchID uuid.UUID      `gorm:"type:uuid;not null;index" json:"owning_branch_id"`
+	FromBranchID   uuid.UUID      `gorm:"type:uuid;not null;index" json:"from_branch_id"`
+	ToBranchID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"to_branch_id"`
+	Reason         TransferReason `gorm:"type:varchar(20);not null" json:"reason"`
+	Status         TransferStatus `gorm:"type:varchar(20);default:'requested';index" json:"status"`
+	LoanID         *uuid.UUID     `gorm:"type:uuid" json:"loan_id"`
+	ReservationID  *uuid.UUID     `gorm:"type:uuid" json:"reservation_id"`
+	RequestedByID  *uuid.UUID     `gorm:"type:uuid" json:"requested_by_id"`
+	ReceivedByID   *uuid.UUID     `gorm:"type:uuid" json:"received_by_id"`
+	RequestedAt    time.Time      `gorm:"not null" json:"requested_at"`
+	ShippedAt      *time.Time     `json:"shipped_at"`
+	ReceivedAt     *time.Time     `json:"received_at"`
+	Notes          string         `gorm:"type:text" json:"notes"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	
+	Book       Book   `gorm:"foreignKey:BookID" json:"book,omitempty"`
+	FromBranch Branch `gorm:"foreignKey:FromBranchID" json:"from_branch,omitempty"`
+	ToBranch   Branch `gorm:"foreignKey:ToBranchID" json:"to_branch,omitempty"`
+}
+
+func (b *Branch) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
+
+func (h *BookHolding) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t *Transfer) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	
+	if t.RequestedAt.IsZero() {
+		t.RequestedAt = time.Now()
+	}
+	
+	return nil
+}
+
+func (t *Transfer) Ship() {
+	now := time.Now()
+	t.Status = TransferInTransit
+	t.ShippedAt = &now
+}
+
+func (t *Transfer) Receive(receivedByID uuid.UUID) {
+	now := time.Now()
+	t.Status = TransferReceived
+	t.ReceivedAt = &now
+	t.ReceivedByID = &receivedByID
+}
+
+type BranchRequest struct {
+	Code      string `json:"code" binding:"required,max=20"`
+	Name      string `json:"name" binding:"required"`
+	Address   string `json:"address"`
+	Phone     string `json:"phone"`
+	Email     string `json:"email"`
+	IsDefault bool   `json:"is_default"`
+	IsActive  *bool  `json:"is_active"`
+}
+
+type HoldingRequest struct {
+	BookID      string `json:"book_id" binding:"required"`
+	BranchID    string `json:"branch_id" binding:"required"`
+	TotalCopies int    `json:"total_copies" binding:"min=0"`
+}
+
+type TransferRequest struct {
+	BookID       string `json:"book_id" binding:"required"`
+	FromBranchID string `json:"from_branch_id"`
+	ToBranchID   string `json:"to_branch_id" binding:"required"`
+	Notes        string `json:"notes"`
+}
+
+type HoldingResponse struct {
+	BranchID    string `json:"branch_id"`
+	BranchCode  string `json:"branch_code"`
+	BranchName  string `json:"branch_name"`
+	Location    string `json:"location"`
+	TotalCopies int64  `json:"total_copies"`
+	OnShelf     int64  `json:"on_shelf"`
+	OnLoan      int64  `json:"on_loan"`
+	InTransit   int64  `json:"in_transit"`
+	OnHoldShelf int64  `json:"on_hold_shelf"`
+	InRepair    int64  `json:"in_repair"`
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DeskSessionStatus string
+
+const (
+	DeskSessionOpen   DeskSessionStatus = "open"
+	DeskSessionClosed DeskSessionStatus = "closed"
+)
+
+type DeskAction string
+
+const (
+	DeskActionCheckout DeskAction = "checkout"
+	DeskActionCheckin  DeskAction = "checkin"
+)
+
+type CirculationAlertType string
+
+const (
+	AlertHoldWaiting CirculationAlertType = "hold_waiting"
+	AlertHoldTrapped CirculationAlertType = "hold_trapped"
+	AlertRouteItem   CirculationAlertType = "route_item"
+	AlertBlocked     CirculationAlertType = "blocked"
+	AlertFines       CirculationAlertType = "outstanding_fines"
+	AlertOverdue     CirculationAlertType = "overdue"
+	AlertLostFound   CirculationAlertType = "lost_item_found"
+)
+
+type CirculationAlert struct {
+	Type    CirculationAlertType `json:"type"`
+	Message string               `json:"message"`
+}
+
+type DeskSession struct {
+	ID           uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MemberID     *uuid.UUID        `gorm:"type:uuid;index" json:"member_id"`
+	OpenedByID   uuid.UUID         `gorm:"type:uuid;not null" json:"opened_by_id"`
+	DeskLocation string            `json:"desk_location"`
+	BranchID     *uuid.UUID        `gorm:"type:uuid;index" json:"branch_id"`
+	Status       DeskSessionStatus `gorm:"type:varchar(20);default:'open';index" json:"status"`
+	OpenedAt     time.Time         `gorm:"not null" json:"opened_at"`
+	ClosedAt     *time.Time        `json:"closed_at"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt    `gorm:"index" json:"-"`
+	
+	Member       *Member           `gorm:"foreignKey:MemberID" json:"member,omitempty"`
+	Transactions []DeskTransaction `gorm:"foreignKey:SessionID" json:"transactions,omitempty"`
+}
+
+func (d *DeskSession) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	
+	if d.OpenedAt.IsZero() {
+		d.OpenedAt = time.Now()
+	}
+	
+	return nil
+}
+
+func (d *DeskSession) IsOpen() bool {
+	return d.Status == DeskSessionOpen
+}
+
+func (d *DeskSession) Close() {
+	now := time.Now()
+	d.Status = DeskSessionClosed
+	d.ClosedAt = &now
+}
+
+type DeskTransaction struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SessionID uuid.UUID  `gorm:"type:uuid;not null;index" json:"session_id"`
+	Action    DeskAction `gorm:"type:varchar(20);not null" json:"action"`
+	ItemCode  string     `gorm:"not null" json:"item_code"`
+	BookID    *uuid.UUID `gorm:"type:uuid" json:"book_id"`
+	LoanID    *uuid.UUID `gorm:"type:uuid" json:"loan_id"`
+	Title     string     `json:"title"`
+	DueDate   *time.Time `json:"due_date"`
+	Success   bool       `gorm:"default:false" json:"success"`
+	Message   string     `json:"message"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (d *DeskTransaction) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+type OpenDeskSessionRequest struct {
+	MembershipID string `json:"membership_id"`
+	DeskLocation string `json:"desk_location"`
+	BranchID     string `json:"branch_id"`
+}
+
+type DeskScanRequest struct {
+	SessionID string   `json:"session_id" binding:"required"`
+	Items     []string `json:"items" binding:"required,min=1"`
+}
+
+type DeskSessionRequest struct {
+	SessionID string `json:"session_id" binding:"required"`
+}
+
+type DeskScanResult struct {
+	ItemCode string             `json:"item_code"`
+	Success  bool               `json:"success"`
+	Message  string             `json:"message,omitempty"`
+	BookID   string             `json:"book_id,omitempty"`
+	Title    string             `json:"title,omitempty"`
+	LoanID   string             `json:"loan_id,omitempty"`
+	DueDate  *time.Time         `json:"due_date,omitempty"`
+	Alerts   []CirculationAlert `json:"alerts,omitempty"`
+}
+
+type DeskSlip struct {
+	SessionID    string            `json:"session_id"`
+	MembershipID string            `json:"membership_id,omitempty"`
+	PatronName   string            `json:"patron_name,omitempty"`
+	DeskLocation string            `json:"desk_location"`
+	OpenedAt     time.Time         `json:"opened_at"`
+	ClosedAt     *time.Time        `json:"closed_at"`
+	CheckedOut   []DeskTransaction `json:"checked_out"`
+	CheckedIn    []DeskTransaction `json:"checked_in"`
+	Failed       []DeskTransaction `json:"failed"`
+	Outstanding  float64           `json:"outstanding_fines"`
+	Text         string            `json:"text"`
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DigitalFormat string
+
+const (
+	DigitalFormatEbook     DigitalFormat = "ebook"
+	DigitalFormatAudiobook DigitalFormat = "audiobook"
+)
+
+type DigitalCheckoutStatus string
+
+const (
+	DigitalCheckoutActive   DigitalCheckoutStatus = "active"
+	DigitalCheckoutReturned DigitalCheckoutStatus = "returned"
+	DigitalCheckoutExpired  DigitalCheckoutStatus = "expired"
+)
+
+type DigitalHoldStatus string
+
+const (
+	DigitalHoldWaiting   DigitalHoldStatus = "waiting"
+	DigitalHoldAvailable DigitalHoldStatus = "available"
+	DigitalHoldFulfilled DigitalHoldStatus = "fulfilled"
+	DigitalHoldExpired   DigitalHoldStatus = "expired"
+	DigitalHoldCancelled DigitalHoldStatus = "cancelled"
+)
+
+type DigitalItem struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BookID      *uuid.UUID     `gorm:"type:uuid;index" json:"book_id"`
+	Title       string         `gorm:"not null;index" json:"title"`
+	Author      string         `gorm:"index" json:"author"`
+	Format      DigitalFormat  `gorm:"type:varchar(20);not null;index" json:"format"`
+	Publisher   string         `json:"publisher"`
+	Language    string         `gorm:"type:varchar(10);default:'en'" json:"language"`
+	Description string         `gorm:"type:text" json:"description"`
+	Licenses    int            `gorm:"default:1" json:"licenses"`
+	LoanDays    int            `gorm:"default:0" json:"loan_days"`
+	FileKey     string         `json:"-"`
+	FileName    string         `json:"file_name"`
+	ContentType string         `json:"content_type"`
+	FileSize    int64          `gorm:"default:0" json:"file_size"`
+	IsActive    bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type DigitalCheckout struct {
+	ID             uuid.UUID             `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	DigitalItemID  uuid.UUID             `gorm:"type:uuid;not null;index" json:"digital_item_id"`
+	MemberID       uuid.UUID             `gorm:"type:uuid;not null;index" json:"member_id"`
+	CheckedOutAt   time.Time             `gorm:"not null" json:"checked_out_at"`
+	ExpiresAt      time.Time             `gorm:"not null;index" json:"expires_at"`
+	ReturnedAt     *time.Time            `json:"returned_at"`
+	Status         DigitalCheckoutStatus `gorm:"type:varchar(20);default:'active';index" json:"status"`
+	Downloads      int                   `gorm:"default:0" json:"downloads"`
+	LastDownloadAt *time.Time            `json:"last_download_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+	
+	DigitalItem DigitalItem `gorm:"foreignKey:DigitalItemID" json:"digital_item,omitempty"`
+}
+
+type DigitalHold struct {
+	ID            uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	DigitalItemID uuid.UUID         `gorm:"type:uuid;not null;index" json:"digital_item_id"`
+	MemberID      uuid.UUID         `gorm:"type:uuid;not null;index" json:"member_id"`
+	Status        DigitalHoldStatus `gorm:"type:varchar(20);default:'waiting';index" json:"status"`
+	QueuePosition int               `gorm:"default:0" json:"queue_position"`
+	AvailableAt   *time.Time        `json:"available_at"`
+	ExpiresAt     *time.Time        `json:"expires_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	
+	DigitalItem DigitalItem `gorm:"foreignKey:DigitalItemID" json:"digital_item,omitempty"`
+}
+
+func (d *DigitalItem) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+func (c *DigitalCheckout) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+func (h *DigitalHold) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
+
+func (f DigitalFormat) Valid() bool {
+	return f == DigitalFormatEbook || f == DigitalFormatAudiobook
+}
+
+func (d *DigitalItem) HasFile() bool {
+	return d.FileKey != ""
+}
+
+func (c *DigitalCheckout) IsActive() bool {
+	return c.Status == DigitalCheckoutActive && time.Now().Before(c.ExpiresAt)
+}
+
+func (h *DigitalHold) IsOpen() bool {
+	return h.Status == DigitalHoldWaiting || h.Status == DigitalHoldAvailable
+}
+
+type DigitalItemRequest struct {
+	BookID      string        `json:"book_id"`
+	Title       string        `json:"title" binding:"required"`
+	Author      string        `json:"author"`
+	Format      DigitalFormat `json:"format" binding:"required"`
+	Publisher   string        `json:"publisher"`
+	Language    string        `json:"language"`
+	Description string        `json:"description"`
+	Licenses    int           `json:"licenses" binding:"min=0"`
+	LoanDays    int           `json:"loan_days" binding:"min=0"`
+	IsActive    *bool         `json:"is_active"`
+}
+
+type DigitalAvailability struct {
+	Item          DigitalItem `json:"item"`
+	LicensesInUse int64       `json:"licenses_in_use"`
+	Available     int64       `json:"available"`
+	HoldsWaiting  int64       `json:"holds_waiting"`
+}
+
+type DownloadLink struct {
+	CheckoutID string    `json:"checkout_id"`
+	URL        string    `json:"url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	FileName   string    `json:"file_name"`
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type ImportJobStatus string
+
+const (
+	ImportJobQueued    ImportJobStatus = "queued"
+	ImportJobRunning   ImportJobStatus = "running"
+	ImportJobCompleted ImportJobStatus = "completed"
+	ImportJobFailed    ImportJobStatus = "failed"
+)
+
+type DuplicatePolicy string
+
+const (
+	DuplicateSkip   DuplicatePolicy = "skip"
+	DuplicateUpdate DuplicatePolicy = "update"
+	DuplicateMerge  DuplicatePolicy = "merge"
+)
+
+type ImportRowAction string
+
+const (
+	ImportRowCreated ImportRowAction = "created"
+	ImportRowUpdated ImportRowAction = "updated"
+	ImportRowMerged  ImportRowAction = "merged"
+	ImportRowSkipped ImportRowAction = "skipped"
+	ImportRowInvalid ImportRowAction = "invalid"
+	ImportRowFailed  ImportRowAction = "failed"
+)
+
+type ImportJob struct {
+	ID              uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Format          string          `gorm:"type:varchar(10);not null" json:"format"`
+	FileName        string          `json:"file_name"`
+	DuplicatePolicy DuplicatePolicy `gorm:"type:varchar(10);default:'skip'" json:"duplicate_policy"`
+	DryRun          bool            `gorm:"default:false" json:"dry_run"`
+	Status          ImportJobStatus `gorm:"type:varchar(20);default:'queued';index" json:"status"`
+	TotalRows       int             `gorm:"default:0" json:"total_rows"`
+	ProcessedRows   int             `gorm:"default:0" json:"processed_rows"`
+	CreatedCount    int             `gorm:"default:0" json:"created"`
+	UpdatedCount    int             `gorm:"default:0" json:"updated"`
+	MergedCount     int             `gorm:"default:0" json:"merged"`
+	SkippedCount    int             `gorm:"default:0" json:"skipped"`
+	InvalidCount    int             `gorm:"default:0" json:"invalid"`
+	FailedCount     int             `gorm:"default:0" json:"failed"`
+	Error           string          `gorm:"type:text" json:"error,omitempty"`
+	StartedByID     uuid.UUID       `gorm:"type:uuid;not null" json:"started_by_id"`
+	StartedAt       *time.Time      `json:"started_at"`
+	CompletedAt     *time.Time      `json:"completed_at"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt  `gorm:"index" json:"-"`
+	
+	Rows []ImportRow `gorm:"foreignKey:JobID" json:"rows,omitempty"`
+}
+
+type ImportRow struct {
+	ID        uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	JobID     uuid.UUID       `gorm:"type:uuid;not null;index" json:"job_id"`
+	RowNumber int             `gorm:"not null" json:"row"`
+	ISBN      string          `json:"isbn"`
+	Title     string          `json:"title"`
+	Action    ImportRowAction `gorm:"type:varchar(20);index" json:"action"`
+	BookID    *uuid.UUID      `gorm:"type:uuid" json:"book_id"`
+	Errors    pq.StringArray  `gorm:"type:text[]" json:"errors,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (j *ImportJob) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *ImportRow) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (j *ImportJob) Record(action ImportRowAction) {
+	j.ProcessedRows++
+	switch action {
+	case ImportRowCreated:
+		j.CreatedCount++
+	case ImportRowUpdated:
+		j.UpdatedCount++
+	case ImportRowMerged:
+		j.MergedCount++
+	case ImportRowSkipped:
+		j.SkippedCount++
+	case ImportRowInvalid:
+		j.InvalidCount++
+	case ImportRowFailed:
+		j.FailedCount++
+	}
+}
+
+func (j *ImportJob) Progress() float64 {
+	if j.TotalRows == 0 {
+		return 100
+	}
+	return float64(j.ProcessedRows) * 100 / float64(j.TotalRows)
+}
+
+func (j *ImportJob) IsFinished() bool {
+	return j.Status == ImportJobCompleted || j.Status == ImportJobFailed
+}
+
+type ImportJobResponse struct {
+	ImportJob
+	Progress float64 `json:"progress"`
+}
@@ -0,0 +1,177 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ItemType string
+
+const (
+	ItemTypeBook      ItemType = "book"
+	ItemTypeDVD       ItemType = "dvd"
+	ItemTypeEquipment ItemType = "equipment"
+	ItemTypeRoom      ItemType = "room"
+)
+
+var ItemTypes = []ItemType{ItemTypeBook, ItemTypeDVD, ItemTypeEquipment, ItemTypeRoom}
+
+var itemAttributeKeys = map[ItemType][]string{
+	ItemTypeBook:      {"edition", "format", "pages"},
+	ItemTypeDVD:       {"format", "region", "runtime", "rating", "upc"},
+	ItemTypeEquipment: {"model", "manufacturer", "serial_number", "accessories"},
+	ItemTypeRoom:      {"capacity", "floor", "features"},
+}
+
+var numericItemAttributes = map[string]bool{
+	"pages":    true,
+	"runtime":  true,
+	"capacity": true,
+}
+
+type BookingStatus string
+
+const (
+	BookingConfirmed BookingStatus = "confirmed"
+	BookingCancelled BookingStatus = "cancelled"
+)
+
+type ItemAttributes map[string]string
+
+func (a ItemAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(a)
+	return string(data), err
+}
+
+func (a *ItemAttributes) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = ItemAttributes{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported item attributes value")
+	}
+	return json.Unmarshal(data, a)
+}
+
+type ItemTypeRule struct {
+	ItemType        ItemType  `gorm:"type:varchar(20);primary_key" json:"item_type"`
+	Lendable        bool      `gorm:"default:true" json:"lendable"`
+	LoanDays        int       `gorm:"default:14" json:"loan_days"`
+	MaxRenewals     int       `gorm:"default:2" json:"max_renewals"`
+	FinePerDay      float64   `gorm:"default:0" json:"fine_per_day"`
+	MaxPerMember    int       `gorm:"default:0" json:"max_per_member"`
+	MaxBookingHours int       `gorm:"default:0" json:"max_booking_hours"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type RoomBooking struct {
+	ID          uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	RoomID      uuid.UUID     `gorm:"type:uuid;not null;index" json:"room_id"`
+	MemberID    uuid.UUID     `gorm:"type:uuid;not null;index" json:"member_id"`
+	BookedByID  uuid.UUID     `gorm:"type:uuid;not null" json:"booked_by_id"`
+	StartTime   time.Time     `gorm:"not null;index" json:"start_time"`
+	EndTime     time.Time     `gorm:"not null;index" json:"end_time"`
+	Status      BookingStatus `gorm:"type:varchar(20);default:'confirmed';index" json:"status"`
+	Purpose     string        `json:"purpose"`
+	Attendees   int           `gorm:"default:1" json:"attendees"`
+	CancelledAt *time.Time    `json:"cancelled_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	
+	Room   Book   `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+	Member Member `gorm:"foreignKey:MemberID" json:"member,omitempty"`
+}
+
+func (b *RoomBooking) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
+
+func (b *RoomBooking) Overlaps(start, end time.Time) bool {
+	return b.StartTime.Before(end) && b.EndTime.After(start)
+}
+
+func (t ItemType) Valid() bool {
+	_, ok := itemAttributeKeys[t]
+	return ok
+}
+
+func (t ItemType) AttributeKeys() []string {
+	return itemAttributeKeys[t]
+}
+
+func (t ItemType) AllowsAttribute(key string) bool {
+	for _, allowed := range itemAttributeKeys[t] {
+		if allowed == key {
+			return true
+		}
+	}
+	return false
+}
+
+func IsNumericAttribute(key string) bool {
+	return numericItemAttributes[key]
+}
+
+type ItemTypeRuleRequest struct {
+	ItemType        ItemType `json:"item_type" binding:"required"`
+	Lendable        *bool    `json:"lendable"`
+	LoanDays        int      `json:"loan_days" binding:"min=0"`
+	MaxRenewals     int      `json:"max_renewals" binding:"min=0"`
+	FinePerDay      float64  `json:"fine_per_day" binding:"min=0"`
+	MaxPerMember    int      `json:"max_per_member" binding:"min=0"`
+	MaxBookingHours int      `json:"max_booking_hours" binding:"min=0"`
+}
+
+type ItemTypeInfo struct {
+	ItemType   ItemType     `json:"item_type"`
+	Attributes []string     `json:"attributes"`
+	Rule       ItemTypeRule `json:"rule"`
+	Items      int64        `json:"items"`
+}
+
+type RoomBookingRequest struct {
+	RoomID       string    `json:"room_id" binding:"required"`
+	MemberID     string    `json:"member_id"`
+	MembershipID string    `json:"membership_id"`
+	StartTime    time.Time `json:"start_time" binding:"required"`
+	EndTime      time.Time `json:"end_time" binding:"required"`
+	Purpose      string    `json:"purpose"`
+	Attendees    int       `json:"attendees" binding:"min=0"`
+}
+
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type RoomDay struct {
+	Date     string        `json:"date"`
+	Bookings []RoomBooking `json:"bookings"`
+	Free     []TimeSlot    `json:"free"`
+}
+
+type RoomCalendar struct {
+	RoomID     string         `json:"room_id"`
+	Title      string         `json:"title"`
+	Location   string         `json:"location"`
+	Attributes ItemAttributes `json:"attributes"`
+	Days       []RoomDay      `json:"days"`
+}
@@ -16,11 +16,24 @@ const (
 	LoanStatusLost     LoanStatus = "lost"
 )
 
+type RenewalDenial string
+
+const (
+	RenewalDeniedNotActive    RenewalDenial = "loan_not_active"
+	RenewalDeniedLimitReached RenewalDenial = "renewal_limit_reached"
+	RenewalDeniedOverdue      RenewalDenial = "loan_overdue"
+	RenewalDeniedReserved     RenewalDenial = "reserved_by_other_members"
+)
+
 type Loan struct {
 	ID               uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	BookID           uuid.UUID      `gorm:"type:uuid;not null;index" json:"book_id"`
+	CopyBarcode      string         `gorm:"index" json:"copy_barcode,omitempty"`
 	MemberID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"member_id"`
 	IssuedByID       uuid.UUID      `gorm:"type:uuid;not null" json:"issued_by_id"`
+	BranchID         *uuid.UUID     `gorm:"type:uuid;index" json:"branch_id"`
+	OwningBranchID   *uuid.UUID     `gorm:"type:uuid;index" json:"owning_branch_id"`
+	ReturnBranchID   *uuid.UUID     `gorm:"type:uuid" json:"return_branch_id"`
 	LoanDate         time.Time      `gorm:"not null" json:"loan_date"`
 	DueDate          time.Time      `gorm:"not null" json:"due_date"`
 	ReturnDate       *time.Time     `json:"return_date"`
@@ -28,8 +41,14 @@ type Loan struct {
 	Status           LoanStatus     `gorm:"type:varchar(20);default:'active';index" json:"status"`
 	RenewalCount     int            `gorm:"default:0" json:"renewal_count"`
 	MaxRenewals      int            `gorm:"default:2" json:"max_renewals"`
+	LastRenewedAt    *time.Time     `json:"last_renewed_at"`
+	AutoRenewed      bool           `gorm:"default:false" json:"auto_renewed"`
 	FineAmount       float64        `gorm:"default:0" json:"fine_amount"`
 	FinePaid         bool           `gorm:"default:false" json:"fine_paid"`
+	LostDate         *time.Time     `json:"lost_date"`
+	FoundDate        *time.Time     `json:"found_date"`
+	ReplacementFee   float64        `gorm:"default:0" json:"replacement_fee"`
+	ProcessingFee    float64        `gorm:"default:0" json:"processing_fee"`
 	Notes            string         `gorm:"type:text" json:"notes"`
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
@@ -57,7 +76,7 @@ func (l *Loan) BeforeCreate(tx *gorm.DB) error {
 }
 
 func (l *Loan) IsOverdue() bool {
-	if l.Status == LoanStatusReturned {
+	if l.Status == LoanStatusReturned || l.Status == LoanStatusLost {
 		return false
 	}
 	return time.Now().After(l.DueDate)
@@ -82,27 +101,70 @@ func (l *Loan) CalculateFine(finePerDay float64) float64 {
 }
 
 func (l *Loan) CanRenew() bool {
-	return l.Status == LoanStatusActive && l.RenewalCount < l.MaxRenewals && !l.IsOverdue()
+	return l.RenewalDenial() == ""
+}
+
+func (l *Loan) RenewalDenial() RenewalDenial {
+	switch {
+	case l.Status != LoanStatusActive:
+		return RenewalDeniedNotActive
+	case l.RenewalCount >= l.MaxRenewals:
+		return RenewalDeniedLimitReached
+	case l.IsOverdue():
+		return RenewalDeniedOverdue
+	default:
+		return ""
+	}
 }
 
 func (l *Loan) Renew(days int) {
 	if l.CanRenew() {
+		now := time.Now()
 		l.DueDate = l.DueDate.AddDate(0, 0, days)
 		l.RenewalCount++
+		l.LastRenewedAt = &now
 	}
 }
 
+func (l *Loan) IsOutstanding() bool {
+	return l.Status == LoanStatusActive || l.Status == LoanStatusOverdue
+}
+
+func (l *Loan) MarkLost(replacementFee, processingFee float64) {
+	now := time.Now()
+	l.Status = LoanStatusLost
+	l.LostDate = &now
+	l.ReplacementFee = replacementFee
+	l.ProcessingFee = processingFee
+}
+
+func (l *Loan) MarkFound(returnDate time.Time) float64 {
+	credit := l.ReplacementFee
+	l.Status = LoanStatusReturned
+	l.FoundDate = &returnDate
+	l.ReturnDate = &returnDate
+	l.ActualReturnDate = &returnDate
+	l.ReplacementFee = 0
+	return credit
+}
+
 type LoanRequest struct {
-	BookID   string `json:"book_id" binding:"required"`
-	MemberID string `json:"member_id" binding:"required"`
-	DueDate  string `json:"due_date"`
-	Notes    string `json:"notes"`
+	BookID      string `json:"book_id" binding:"required"`
+	MemberID    string `json:"member_id" binding:"required"`
+	BranchID    string `json:"branch_id"`
+	CopyBarcode string `json:"copy_barcode"`
+	DueDate     string `json:"due_date"`
+	Notes       string `json:"notes"`
 }
 
 type LoanResponse struct {
 	ID               string     `json:"id"`
 	BookID           string     `json:"book_id"`
+	CopyBarcode      string     `json:"copy_barcode,omitempty"`
 	MemberID         string     `json:"member_id"`
+	BranchID         *string    `json:"branch_id"`
+	OwningBranchID   *string    `json:"owning_branch_id"`
+	ReturnBranchID   *string    `json:"return_branch_id"`
 	LoanDate         time.Time  `json:"loan_date"`
 	DueDate          time.Time  `json:"due_date"`
 	ReturnDate       *time.Time `json:"return_date"`
@@ -112,22 +174,50 @@ type LoanResponse struct {
 	RenewalCount     int        `json:"renewal_count"`
 	MaxRenewals      int        `json:"max_renewals"`
 	CanRenew         bool       `json:"can_renew"`
+	RenewalDenial    string     `json:"renewal_denied_reason,omitempty"`
+	AutoRenewed      bool       `json:"auto_renewed"`
 	FineAmount       float64    `json:"fine_amount"`
 	FinePaid         bool       `json:"fine_paid"`
+	LostDate         *time.Time `json:"lost_date,omitempty"`
+	FoundDate        *time.Time `json:"found_date,omitempty"`
+	ReplacementFee   float64    `json:"replacement_fee"`
+	ProcessingFee    float64    `json:"processing_fee"`
 	Notes            string     `json:"notes"`
 	Book             *BookResponse   `json:"book,omitempty"`
 	Member           *MemberResponse `json:"member,omitempty"`
 	CreatedAt        time.Time  `json:"created_at"`
 }
 
+func (r LoanResponse) CursorID() string {
+	return r.ID
+}
+
+func (r LoanResponse) SortValue(field string) interface{} {
+	switch field {
+	case "loan_date":
+		return r.LoanDate
+	case "due_date":
+		return r.DueDate
+	default:
+		return r.CreatedAt
+	}
+}
+
 type ReturnBookRequest struct {
 	LoanID           string    `json:"loan_id" binding:"required"`
+	BranchID         string    `json:"branch_id"`
 	ActualReturnDate time.Time `json:"actual_return_date"`
 }
 
 type RenewLoanRequest struct {
 	LoanID        string    `json:"loan_id" binding:"required"`
 	NewReturnDate time.Time `json:"new_return_date"`
+	Override      bool      `json:"override"`
+}
+
+type DeclareLostRequest struct {
+	LoanID string `json:"loan_id" binding:"required"`
+	Notes  string `json:"notes"`
 }
 
 type UpdateFineRequest struct {
@@ -141,6 +231,7 @@ type LoanStatistics struct {
 	ActiveLoans      int64   `json:"active_loans"`
 	OverdueLoans     int64   `json:"overdue_loans"`
 	ReturnedLoans    int64   `json:"returned_loans"`
+	LostLoans        int64   `json:"lost_loans"`
 	TotalFines       float64 `json:"total_fines"`
 	CollectedFines   float64 `json:"collected_fines"`
 	AverageLoadDays  float64 `json:"average_loan_days"`
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoanRenewalDenial(t *testing.T) {
+	future := time.Now().AddDate(0, 0, 3)
+	past := time.Now().AddDate(0, 0, -3)
+	
+	tests := []struct {
+		name string
+		loan Loan
+		want RenewalDenial
+	}{
+		{
+			name: "active loan under the limit",
+			loan: Loan{Status: LoanStatusActive, DueDate: future, RenewalCount: 0, MaxRenewals: 2},
+		},
+		{
+			name: "last renewal allowed",
+			loan: Loan{Status: LoanStatusActive, DueDate: future, RenewalCount: 1, MaxRenewals: 2},
+		},
+		{
+			name: "renewal limit reached",
+			loan: Loan{Status: LoanStatusActive, DueDate: future, RenewalCount: 2, MaxRenewals: 2},
+			want: RenewalDeniedLimitReached,
+		},
+		{
+			name: "no renewals allowed for the item type",
+			loan: Loan{Status: LoanStatusActive, DueDate: future, MaxRenewals: 0},
+			want: RenewalDeniedLimitReached,
+		},
+		{
+			name: "past the due date",
+			loan: Loan{Status: LoanStatusActive, DueDate: past, MaxRenewals: 2},
+			want: RenewalDeniedOverdue,
+		},
+		{
+			name: "past the due date with a fine accruing",
+			loan: Loan{Status: LoanStatusActive, DueDate: past, MaxRenewals: 2, FineAmount: 1.5},
+			want: RenewalDeniedOverdue,
+		},
+		{
+			name: "marked overdue by the scheduler",
+			loan: Loan{Status: LoanStatusOverdue, DueDate: past, MaxRenewals: 2},
+			want: RenewalDeniedNotActive,
+		},
+		{
+			name: "already returned",
+			loan: Loan{Status: LoanStatusReturned, DueDate: future, MaxRenewals: 2},
+			want: RenewalDeniedNotActive,
+		},
+		{
+			name: "declared lost",
+			loan: Loan{Status: LoanStatusLost, DueDate: future, MaxRenewals: 2},
+			want: RenewalDeniedNotActive,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := tt.loan
+			if got := loan.RenewalDenial(); got != tt.want {
+				t.Fatalf("RenewalDenial() = %q, want %q", got, tt.want)
+			}
+			if got := loan.CanRenew(); got != (tt.want == "") {
+				t.Fatalf("CanRenew() = %v with denial %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoanRenew(t *testing.T) {
+	due := time.Now().AddDate(0, 0, 2)
+	
+	loan := Loan{Status: LoanStatusActive, DueDate: due, MaxRenewals: 1}
+	loan.Renew(14)
+	if want := due.AddDate(0, 0, 14); !loan.DueDate.Equal(want) {
+		t.Fatalf("due date = %v, want %v", loan.DueDate, want)
+	}
+	if loan.RenewalCount != 1 || loan.LastRenewedAt == nil {
+		t.Fatalf("renewal not recorded: count %d, last renewed %v", loan.RenewalCount, loan.LastRenewedAt)
+	}
+	
+	renewed := loan.DueDate
+	loan.Renew(14)
+	if !loan.DueDate.Equal(renewed) || loan.RenewalCount != 1 {
+		t.Fatalf("renewal past the limit changed the loan: due %v, count %d", loan.DueDate, loan.RenewalCount)
+	}
+}
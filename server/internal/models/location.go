@@ -0,0 +1,134 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/pkg/callnumber"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LocationLevel string
+
+const (
+	LocationFloor LocationLevel = "floor"
+	LocationRoom  LocationLevel = "room"
+	LocationRange LocationLevel = "range"
+	LocationShelf LocationLevel = "shelf"
+)
+
+type Location struct {
+	ID              uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BranchID        uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_locations_branch_path" json:"branch_id"`
+	ParentID        *uuid.UUID    `gorm:"type:uuid;index" json:"parent_id"`
+	Level           LocationLevel `gorm:"type:varchar(10);not null" json:"level"`
+	Code            string        `gorm:"type:varchar(40);not null" json:"code"`
+	Name            string        `json:"name"`
+	Path            string        `gorm:"not null;uniqueIndex:idx_locations_branch_path" json:"path"`
+	CallNumberStart string        `json:"call_number_start"`
+	CallNumberEnd   string        `json:"call_number_end"`
+	StartSort       string        `json:"-"`
+	EndSort         string        `json:"-"`
+	MapURL          string        `json:"map_url,omitempty"`
+	MapX            *float64      `json:"map_x"`
+	MapY            *float64      `json:"map_y"`
+	IsActive        bool          `gorm:"default:true" json:"is_active"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+func (l *Location) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+func (l LocationLevel) Depth() int {
+	switch l {
+	case LocationFloor:
+		return 1
+	case LocationRoom:
+		return 2
+	case LocationRange:
+		return 3
+	case LocationShelf:
+		return 4
+	}
+	return 0
+}
+
+func (l LocationLevel) IsValid() bool {
+	return l.Depth() > 0
+}
+
+func (l *Location) HasCoordinates() bool {
+	return l.MapX != nil && l.MapY != nil
+}
+
+func (l *Location) Contains(other *Location) bool {
+	return l.ID == other.ID || strings.HasPrefix(other.Path, l.Path+"/")
+}
+
+func (l *Location) Covers(sortKey string) bool {
+	if l.StartSort == "" && l.EndSort == "" {
+		return true
+	}
+	return sortKey != "" && callnumber.InRange(sortKey, l.StartSort, l.EndSort)
+}
+
+type LocationRequest struct {
+	BranchID        string        `json:"branch_id"`
+	ParentID        string        `json:"parent_id"`
+	Level           LocationLevel `json:"level" binding:"required"`
+	Code            string        `json:"code" binding:"required,max=40"`
+	Name            string        `json:"name"`
+	CallNumberStart string        `json:"call_number_start"`
+	CallNumberEnd   string        `json:"call_number_end"`
+	MapURL          string        `json:"map_url"`
+	MapX            *float64      `json:"map_x"`
+	MapY            *float64      `json:"map_y"`
+	IsActive        *bool         `json:"is_active"`
+}
+
+type RelocateRequest struct {
+	ToLocationID   string   `json:"to_location_id" binding:"required"`
+	FromLocationID string   `json:"from_location_id"`
+	FromText       string   `json:"from_text"`
+	BookIDs        []string `json:"book_ids"`
+}
+
+type LocationNode struct {
+	ID              string        `json:"id"`
+	Level           LocationLevel `json:"level"`
+	Code            string        `json:"code"`
+	Name            string        `json:"name"`
+	CallNumberStart string        `json:"call_number_start,omitempty"`
+	CallNumberEnd   string        `json:"call_number_end,omitempty"`
+}
+
+type LocationTree struct {
+	Location
+	Children []*LocationTree `json:"children"`
+	Items    int64           `json:"items"`
+}
+
+type BookPlacement struct {
+	BranchID   string         `json:"branch_id"`
+	BranchCode string         `json:"branch_code"`
+	BranchName string         `json:"branch_name"`
+	Location   string         `json:"location"`
+	Path       []LocationNode `json:"path"`
+	MapURL     string         `json:"map_url,omitempty"`
+	MapX       *float64       `json:"map_x"`
+	MapY       *float64       `json:"map_y"`
+	OnShelf    int64          `json:"on_shelf"`
+}
+
+type RelocationReport struct {
+	Moved    int      `json:"moved"`
+	Location string   `json:"location"`
+	Skipped  []string `json:"skipped"`
+}
@@ -28,6 +28,8 @@ type Member struct {
 	TotalFineAmount    float64        `gorm:"default:0" json:"total_fine_amount"`
 	FinesPaid          float64        `gorm:"default:0" json:"fines_paid"`
 	IsActive           bool           `gorm:"default:true" json:"is_active"`
+	HomeBranchID       *uuid.UUID     `gorm:"type:uuid;index" json:"home_branch_id"`
+	AutoRenew          bool           `gorm:"default:false" json:"auto_renew"`
 	Address            string         `json:"address"`
 	City               string         `json:"city"`
 	State              string         `json:"state"`
@@ -107,6 +109,8 @@ type MemberResponse struct {
 	OutstandingFines   float64        `json:"outstanding_fines"`
 	IsActive           bool           `json:"is_active"`
 	IsExpired          bool           `json:"is_expired"`
+	HomeBranchID       *string        `json:"home_branch_id"`
+	AutoRenew          bool           `json:"auto_renew"`
 	Address            string         `json:"address"`
 	City               string         `json:"city"`
 	State              string         `json:"state"`
@@ -114,6 +118,23 @@ type MemberResponse struct {
 	CreatedAt          time.Time      `json:"created_at"`
 }
 
+func (r MemberResponse) CursorID() string {
+	return r.ID
+}
+
+func (r MemberResponse) SortValue(field string) interface{} {
+	switch field {
+	case "name":
+		return r.Name
+	case "membership_id":
+		return r.MembershipID
+	case "join_date":
+		return r.JoinDate
+	default:
+		return r.CreatedAt
+	}
+}
+
 type MemberStatistics struct {
 	TotalMembers       int64   `json:"total_members"`
 	ActiveMembers      int64   `json:"active_members"`
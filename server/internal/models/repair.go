@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type RepairStatus string
+
+const (
+	RepairStatusQueued    RepairStatus = "queued"
+	RepairStatusInRepair  RepairStatus = "in_repair"
+	RepairStatusRepaired  RepairStatus = "repaired"
+	RepairStatusWithdrawn RepairStatus = "withdrawn"
+)
+
+type Repair struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BookID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"book_id"`
+	LoanID        *uuid.UUID     `gorm:"type:uuid;index" json:"loan_id"`
+	BranchID      *uuid.UUID     `gorm:"type:uuid;index" json:"branch_id"`
+	ReportedByID  uuid.UUID      `gorm:"type:uuid;not null" json:"reported_by_id"`
+	Status        RepairStatus   `gorm:"type:varchar(20);default:'queued';index" json:"status"`
+	DamageCharge  float64        `gorm:"default:0" json:"damage_charge"`
+	Notes         string         `gorm:"type:text" json:"notes"`
+	Photos        pq.StringArray `gorm:"type:text[]" json:"photos"`
+	ResolvedDate  *time.Time     `json:"resolved_date"`
+	ResolvedNotes string         `gorm:"type:text" json:"resolved_notes"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	
+	Book Book `gorm:"foreignKey:BookID" json:"book,omitempty"`
+}
+
+func (r *Repair) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *Repair) IsOpen() bool {
+	return r.Status == RepairStatusQueued || r.Status == RepairStatusInRepair
+}
+
+func (r *Repair) Resolve(status RepairStatus, notes string) {
+	now := time.Now()
+	r.Status = status
+	r.ResolvedDate = &now
+	r.ResolvedNotes = notes
+}
+
+type ReportDamageRequest struct {
+	BookID       string   `json:"book_id" binding:"required"`
+	LoanID       string   `json:"loan_id"`
+	BranchID     string   `json:"branch_id"`
+	DamageCharge float64  `json:"damage_charge" binding:"min=0"`
+	Notes        string   `json:"notes"`
+	Photos       []string `json:"photos"`
+}
+
+type UpdateRepairRequest struct {
+	RepairID string `json:"repair_id" binding:"required"`
+	Status   string `json:"status" binding:"required"`
+	Notes    string `json:"notes"`
+}
+
+type RepairResponse struct {
+	ID            string        `json:"id"`
+	BookID        string        `json:"book_id"`
+	LoanID        *string       `json:"loan_id,omitempty"`
+	BranchID      *string       `json:"branch_id,omitempty"`
+	Status        string        `json:"status"`
+	DamageCharge  float64       `json:"damage_charge"`
+	Notes         string        `json:"notes"`
+	Photos        []string      `json:"photos"`
+	ResolvedDate  *time.Time    `json:"resolved_date"`
+	ResolvedNotes string        `json:"resolved_notes"`
+	Book          *BookResponse `json:"book,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
@@ -10,10 +10,12 @@ import (
 type ReservationStatus string
 
 const (
-	ReservationStatusPending   ReservationStatus = "pending"
-	ReservationStatusFulfilled ReservationStatus = "fulfilled"
-	ReservationStatusCancelled ReservationStatus = "cancelled"
-	ReservationStatusExpired   ReservationStatus = "expired"
+	ReservationStatusPending        ReservationStatus = "pending"
+	ReservationStatusInTransit      ReservationStatus = "in_transit"
+	ReservationStatusReadyForPickup ReservationStatus = "ready_for_pickup"
+	ReservationStatusFulfilled      ReservationStatus = "fulfilled"
+	ReservationStatusCancelled      ReservationStatus = "cancelled"
+	ReservationStatusExpired        ReservationStatus = "expired"
 )
 
 type Reservation struct {
@@ -24,6 +26,11 @@ type Reservation struct {
 	ExpiryDate       time.Time         `gorm:"not null" json:"expiry_date"`
 	FulfilledDate    *time.Time        `json:"fulfilled_date"`
 	CancelledDate    *time.Time        `json:"cancelled_date"`
+	TrappedDate      *time.Time        `json:"trapped_date"`
+	PickupDeadline   *time.Time        `gorm:"index" json:"pickup_deadline"`
+	PickupLocation   string            `gorm:"index" json:"pickup_location"`
+	PickupBranchID   *uuid.UUID        `gorm:"type:uuid;index" json:"pickup_branch_id"`
+	ItemBranchID     *uuid.UUID        `gorm:"type:uuid;index" json:"item_branch_id"`
 	Status           ReservationStatus `gorm:"type:varchar(20);default:'pending';index" json:"status"`
 	QueuePosition    int               `gorm:"default:1" json:"queue_position"`
 	NotificationSent bool              `gorm:"default:false" json:"notification_sent"`
@@ -56,6 +63,36 @@ func (r *Reservation) IsExpired() bool {
 	return r.Status == ReservationStatusPending && time.Now().After(r.ExpiryDate)
 }
 
+func (r *Reservation) IsReadyForPickup() bool {
+	return r.Status == ReservationStatusReadyForPickup
+}
+
+func (r *Reservation) IsPickupExpired() bool {
+	return r.IsReadyForPickup() && r.PickupDeadline != nil && time.Now().After(*r.PickupDeadline)
+}
+
+func (r *Reservation) Trap(location string, pickupDays int) {
+	now := time.Now()
+	deadline := now.AddDate(0, 0, pickupDays)
+	r.Status = ReservationStatusReadyForPickup
+	r.TrappedDate = &now
+	r.PickupDeadline = &deadline
+	r.QueuePosition = 0
+	r.NotificationSent = false
+	if r.PickupLocation == "" {
+		r.PickupLocation = location
+	}
+}
+
+func (r *Reservation) StartTransit() {
+	r.Status = ReservationStatusInTransit
+	r.QueuePosition = 0
+}
+
+func (r *Reservation) ExpirePickup() {
+	r.Status = ReservationStatusExpired
+}
+
 func (r *Reservation) Cancel() {
 	now := time.Now()
 	r.Status = ReservationStatusCancelled
@@ -73,9 +110,11 @@ func (r *Reservation) UpdateQueuePosition(position int) {
 }
 
 type ReservationRequest struct {
-	BookID   string `json:"book_id" binding:"required"`
-	MemberID string `json:"member_id"`
-	Notes    string `json:"notes"`
+	BookID         string `json:"book_id" binding:"required"`
+	MemberID       string `json:"member_id"`
+	PickupLocation string `json:"pickup_location"`
+	PickupBranchID string `json:"pickup_branch_id"`
+	Notes          string `json:"notes"`
 }
 
 type ReservationResponse struct {
@@ -86,6 +125,10 @@ type ReservationResponse struct {
 	ExpiryDate      time.Time       `json:"expiry_date"`
 	FulfilledDate   *time.Time      `json:"fulfilled_date"`
 	CancelledDate   *time.Time      `json:"cancelled_date"`
+	TrappedDate     *time.Time      `json:"trapped_date"`
+	PickupDeadline  *time.Time      `json:"pickup_deadline"`
+	PickupLocation  string          `json:"pickup_location"`
+	PickupBranchID  *string         `json:"pickup_branch_id"`
 	Status          string          `json:"status"`
 	QueuePosition   int             `json:"queue_position"`
 	IsExpired       bool            `json:"is_expired"`
@@ -95,6 +138,23 @@ type ReservationResponse struct {
 	CreatedAt       time.Time       `json:"created_at"`
 }
 
+func (r ReservationResponse) CursorID() string {
+	return r.ID
+}
+
+func (r ReservationResponse) SortValue(field string) interface{} {
+	switch field {
+	case "reservation_date":
+		return r.ReservationDate
+	case "expiry_date":
+		return r.ExpiryDate
+	case "queue_position":
+		return r.QueuePosition
+	default:
+		return r.CreatedAt
+	}
+}
+
 type CancelReservationRequest struct {
 	ReservationID string `json:"reservation_id" binding:"required"`
 	Reason        string `json:"reason"`
@@ -103,6 +163,7 @@ type CancelReservationRequest struct {
 type ReservationStatistics struct {
 	TotalReservations     int64 `json:"total_reservations"`
 	PendingReservations   int64 `json:"pending_reservations"`
+	ReadyForPickup        int64 `json:"ready_for_pickup"`
 	FulfilledReservations int64 `json:"fulfilled_reservations"`
 	CancelledReservations int64 `json:"cancelled_reservations"`
 	ExpiredReservations   int64 `json:"expired_reservations"`
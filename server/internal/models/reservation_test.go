@@ -0,0 +1,150 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReservationTrap(t *testing.T) {
+	tests := []struct {
+		name           string
+		pickupLocation string
+		wantLocation   string
+	}{
+		{name: "no pickup location falls back to the desk", pickupLocation: "", wantLocation: "Front Desk"},
+		{name: "requested pickup location is kept", pickupLocation: "Branch Lobby", wantLocation: "Branch Lobby"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hold := Reservation{
+				Status:           ReservationStatusPending,
+				QueuePosition:    3,
+				NotificationSent: true,
+				PickupLocation:   tt.pickupLocation,
+			}
+			
+			before := time.Now()
+			hold.Trap("Front Desk", 5)
+			
+			if !hold.IsReadyForPickup() {
+				t.Fatalf("status = %q, want %q", hold.Status, ReservationStatusReadyForPickup)
+			}
+			if hold.QueuePosition != 0 {
+				t.Errorf("queue position = %d, want 0", hold.QueuePosition)
+			}
+			if hold.NotificationSent {
+				t.Errorf("notification still marked sent after trapping")
+			}
+			if hold.PickupLocation != tt.wantLocation {
+				t.Errorf("pickup location = %q, want %q", hold.PickupLocation, tt.wantLocation)
+			}
+			if hold.TrappedDate == nil || hold.TrappedDate.Before(before) {
+				t.Errorf("trapped date = %v, want a time after %v", hold.TrappedDate, before)
+			}
+			if hold.PickupDeadline == nil {
+				t.Fatalf("pickup deadline not set")
+			}
+			if want := hold.TrappedDate.AddDate(0, 0, 5); !hold.PickupDeadline.Equal(want) {
+				t.Errorf("pickup deadline = %v, want %v", *hold.PickupDeadline, want)
+			}
+			if hold.IsPickupExpired() {
+				t.Errorf("freshly trapped hold reported as expired")
+			}
+		})
+	}
+}
+
+func TestReservationIsPickupExpired(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	
+	tests := []struct {
+		name     string
+		status   ReservationStatus
+		deadline *time.Time
+		want     bool
+	}{
+		{name: "on the shelf past the deadline", status: ReservationStatusReadyForPickup, deadline: &past, want: true},
+		{name: "on the shelf before the deadline", status: ReservationStatusReadyForPickup, deadline: &future},
+		{name: "on the shelf without a deadline", status: ReservationStatusReadyForPickup},
+		{name: "pending hold with an old deadline", status: ReservationStatusPending, deadline: &past},
+		{name: "in transit with an old deadline", status: ReservationStatusInTransit, deadline: &past},
+		{name: "already expired", status: ReservationStatusExpired, deadline: &past},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hold := Reservation{Status: tt.status, PickupDeadline: tt.deadline}
+			if got := hold.IsPickupExpired(); got != tt.want {
+				t.Fatalf("IsPickupExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldShelfCopyAccounting(t *testing.T) {
+	tests := []struct {
+		name string
+		book Book
+		// steps replays what the circulation service does to the book as the
+		// copy moves between the hold shelf and the open shelf.
+		steps         []func(b *Book)
+		wantAvailable int
+		wantStatus    BookStatus
+	}{
+		{
+			name:          "last copy returned and trapped for a hold",
+			book:          Book{TotalCopies: 1, AvailableCopies: 0, Status: BookStatusLoaned},
+			steps:         []func(b *Book){(*Book).HoldReturnedCopy},
+			wantAvailable: 0,
+			wantStatus:    BookStatusReserved,
+		},
+		{
+			name:          "trapped copy expires with nobody else waiting",
+			book:          Book{TotalCopies: 1, AvailableCopies: 0, Status: BookStatusLoaned},
+			steps:         []func(b *Book){(*Book).HoldReturnedCopy, (*Book).IncrementAvailable},
+			wantAvailable: 1,
+			wantStatus:    BookStatusAvailable,
+		},
+		{
+			name:          "trapped copy expires and passes to the next hold",
+			book:          Book{TotalCopies: 1, AvailableCopies: 0, Status: BookStatusLoaned},
+			steps:         []func(b *Book){(*Book).HoldReturnedCopy, (*Book).HoldReturnedCopy},
+			wantAvailable: 0,
+			wantStatus:    BookStatusReserved,
+		},
+		{
+			name:          "copy trapped while another copy is on the shelf",
+			book:          Book{TotalCopies: 2, AvailableCopies: 1, Status: BookStatusAvailable},
+			steps:         []func(b *Book){(*Book).HoldReturnedCopy},
+			wantAvailable: 1,
+			wantStatus:    BookStatusAvailable,
+		},
+		{
+			name:          "second copy expires back to the shelf",
+			book:          Book{TotalCopies: 2, AvailableCopies: 1, Status: BookStatusAvailable},
+			steps:         []func(b *Book){(*Book).HoldReturnedCopy, (*Book).IncrementAvailable},
+			wantAvailable: 2,
+			wantStatus:    BookStatusAvailable,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := tt.book
+			for _, step := range tt.steps {
+				step(&book)
+			}
+			if book.AvailableCopies != tt.wantAvailable {
+				t.Errorf("available copies = %d, want %d", book.AvailableCopies, tt.wantAvailable)
+			}
+			if book.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", book.Status, tt.wantStatus)
+			}
+			if book.AvailableCopies > book.TotalCopies {
+				t.Errorf("available copies %d exceed total copies %d", book.AvailableCopies, book.TotalCopies)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SerialFrequency string
+
+const (
+	FrequencyWeekly    SerialFrequency = "weekly"
+	FrequencyMonthly   SerialFrequency = "monthly"
+	FrequencyQuarterly SerialFrequency = "quarterly"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionActive    SubscriptionStatus = "active"
+	SubscriptionCancelled SubscriptionStatus = "cancelled"
+)
+
+type IssueStatus string
+
+const (
+	IssueExpected IssueStatus = "expected"
+	IssueReceived IssueStatus = "received"
+	IssueClaimed  IssueStatus = "claimed"
+	IssueMissing  IssueStatus = "missing"
+)
+
+type Serial struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Title       string          `gorm:"not null;index" json:"title"`
+	ISSN        string          `gorm:"type:varchar(9);uniqueIndex;not null" json:"issn"`
+	Publisher   string          `json:"publisher"`
+	Category    string          `gorm:"index" json:"category"`
+	Language    string          `gorm:"type:varchar(10);default:'en'" json:"language"`
+	Frequency   SerialFrequency `gorm:"type:varchar(20);not null" json:"frequency"`
+	Circulates  bool            `gorm:"default:true" json:"circulates"`
+	LoanDays    int             `gorm:"default:7" json:"loan_days"`
+	MaxRenewals int             `gorm:"default:1" json:"max_renewals"`
+	FinePerDay  float64         `gorm:"default:0" json:"fine_per_day"`
+	IsActive    bool            `gorm:"default:true" json:"is_active"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"-"`
+}
+
+type SerialSubscription struct {
+	ID              uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SerialID        uuid.UUID          `gorm:"type:uuid;not null;index" json:"serial_id"`
+	BranchID        uuid.UUID          `gorm:"type:uuid;not null;index" json:"branch_id"`
+	VendorID        *uuid.UUID         `gorm:"type:uuid;index" json:"vendor_id"`
+	Status          SubscriptionStatus `gorm:"type:varchar(20);default:'active';index" json:"status"`
+	Frequency       SerialFrequency    `gorm:"type:varchar(20);not null" json:"frequency"`
+	StartDate       time.Time          `gorm:"not null" json:"start_date"`
+	EndDate         *time.Time         `json:"end_date"`
+	FirstIssueDate  time.Time          `gorm:"not null" json:"first_issue_date"`
+	FirstVolume     int                `gorm:"default:1" json:"first_volume"`
+	FirstNumber     int                `gorm:"default:1" json:"first_number"`
+	IssuesPerVolume int                `gorm:"default:0" json:"issues_per_volume"`
+	Copies          int                `gorm:"default:1" json:"copies"`
+	ClaimAfterDays  int                `gorm:"default:14" json:"claim_after_days"`
+	Notes           string             `gorm:"type:text" json:"notes"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	
+	Serial Serial `gorm:"foreignKey:SerialID" json:"serial,omitempty"`
+}
+
+type SerialIssue struct {
+	ID              uuid.UUID   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SerialID        uuid.UUID   `gorm:"type:uuid;not null;index" json:"serial_id"`
+	SubscriptionID  uuid.UUID   `gorm:"type:uuid;not null;index" json:"subscription_id"`
+	Volume          int         `json:"volume"`
+	Number          int         `json:"number"`
+	Label           string      `json:"label"`
+	ExpectedDate    time.Time   `gorm:"not null;index" json:"expected_date"`
+	ReceivedDate    *time.Time  `json:"received_date"`
+	Status          IssueStatus `gorm:"type:varchar(20);default:'expected';index" json:"status"`
+	Barcode         string      `gorm:"index" json:"barcode"`
+	Copies          int         `gorm:"default:0" json:"copies"`
+	AvailableCopies int         `gorm:"default:0" json:"available_copies"`
+	ClaimCount      int         `gorm:"default:0" json:"claim_count"`
+	LastClaimedAt   *time.Time  `json:"last_claimed_at"`
+	Notes           string      `gorm:"type:text" json:"notes"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+	
+	Serial Serial `gorm:"foreignKey:SerialID" json:"serial,omitempty"`
+}
+
+type IssueLoan struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	IssueID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"issue_id"`
+	MemberID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"member_id"`
+	IssuedByID       uuid.UUID  `gorm:"type:uuid;not null" json:"issued_by_id"`
+	BranchID         uuid.UUID  `gorm:"type:uuid;index" json:"branch_id"`
+	LoanDate         time.Time  `gorm:"not null" json:"loan_date"`
+	DueDate          time.Time  `gorm:"not null" json:"due_date"`
+	ActualReturnDate *time.Time `json:"actual_return_date"`
+	Status           LoanStatus `gorm:"type:varchar(20);default:'active';index" json:"status"`
+	RenewalCount     int        `gorm:"default:0" json:"renewal_count"`
+	MaxRenewals      int        `gorm:"default:0" json:"max_renewals"`
+	FineAmount       float64    `gorm:"default:0" json:"fine_amount"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	
+	Issue  SerialIssue `gorm:"foreignKey:IssueID" json:"issue,omitempty"`
+	Member Member      `gorm:"foreignKey:MemberID" json:"member,omitempty"`
+}
+
+func (s *Serial) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *SerialSubscription) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (i *SerialIssue) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+func (l *IssueLoan) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+func (f SerialFrequency) Valid() bool {
+	return f == FrequencyWeekly || f == FrequencyMonthly || f == FrequencyQuarterly
+}
+
+func (f SerialFrequency) months() int {
+	switch f {
+	case FrequencyWeekly:
+		return 0
+	case FrequencyQuarterly:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// IssueDate returns the expected date of the nth issue after the first
+// (n = 0 is the first issue). Monthly dates are counted from the first
+// issue rather than chained, so an issue due on the 31st falls on the last
+// day of shorter months without drifting earlier afterwards.
+func (f SerialFrequency) IssueDate(first time.Time, n int) time.Time {
+	step := f.months()
+	if step == 0 {
+		return first.AddDate(0, 0, 7*n)
+	}
+	
+	year, month, day := first.Date()
+	target := time.Date(year, month+time.Month(step*n), 1, first.Hour(), first.Minute(), first.Second(), first.Nanosecond(), first.Location())
+	if last := target.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return target.AddDate(0, 0, day-1)
+}
+
+// IssueIndex is the inverse of IssueDate: the position of the issue
+// expected on date.
+func (f SerialFrequency) IssueIndex(first, date time.Time) int {
+	step := f.months()
+	if step == 0 {
+		days := int(math.Round(date.Sub(first).Hours() / 24))
+		return days / 7
+	}
+	
+	months := (date.Year()-first.Year())*12 + int(date.Month()-first.Month())
+	return months / step
+}
+
+func (s *SerialSubscription) NextNumber(volume, number int) (int, int) {
+	number++
+	if s.IssuesPerVolume > 0 && number > s.IssuesPerVolume {
+		return volume + 1, 1
+	}
+	return volume, number
+}
+
+func (s *SerialSubscription) Covers(date time.Time) bool {
+	return !date.Before(s.StartDate) && (s.EndDate == nil || !date.After(*s.EndDate))
+}
+
+func (i *SerialIssue) IsOpen() bool {
+	return i.Status == IssueExpected || i.Status == IssueClaimed
+}
+
+func (i *SerialIssue) Receive(copies int, date time.Time) {
+	i.Status = IssueReceived
+	i.ReceivedDate = &date
+	i.Copies = copies
+	i.AvailableCopies = copies
+}
+
+func (l *IssueLoan) IsOverdue() bool {
+	return l.Status == LoanStatusActive && time.Now().After(l.DueDate)
+}
+
+func (l *IssueLoan) CalculateFine(finePerDay float64, returnDate time.Time) float64 {
+	if !returnDate.After(l.DueDate) {
+		return 0
+	}
+	days := int(returnDate.Sub(l.DueDate).Hours() / 24)
+	if days < 1 {
+		days = 1
+	}
+	return float64(days) * finePerDay
+}
+
+func IssueLabel(volume, number int, date time.Time) string {
+	return fmt.Sprintf("Vol. %d, No. %d (%s)", volume, number, date.Format("Jan 2006"))
+}
+
+type SerialRequest struct {
+	Title       string          `json:"title" binding:"required"`
+	ISSN        string          `json:"issn" binding:"required"`
+	Publisher   string          `json:"publisher"`
+	Category    string          `json:"category"`
+	Language    string          `json:"language"`
+	Frequency   SerialFrequency `json:"frequency" binding:"required"`
+	Circulates  *bool           `json:"circulates"`
+	LoanDays    int             `json:"loan_days" binding:"min=0"`
+	MaxRenewals int             `json:"max_renewals" binding:"min=0"`
+	FinePerDay  float64         `json:"fine_per_day" binding:"min=0"`
+	IsActive    *bool           `json:"is_active"`
+}
+
+type SubscriptionRequest struct {
+	SerialID        string          `json:"serial_id" binding:"required"`
+	BranchID        string          `json:"branch_id"`
+	VendorID        string          `json:"vendor_id"`
+	Frequency       SerialFrequency `json:"frequency"`
+	StartDate       time.Time       `json:"start_date"`
+	EndDate         *time.Time      `json:"end_date"`
+	FirstIssueDate  time.Time       `json:"first_issue_date"`
+	FirstVolume     int             `json:"first_volume" binding:"min=0"`
+	FirstNumber     int             `json:"first_number" binding:"min=0"`
+	IssuesPerVolume int             `json:"issues_per_volume" binding:"min=0"`
+	Copies          int             `json:"copies" binding:"min=0"`
+	ClaimAfterDays  int             `json:"claim_after_days" binding:"min=0"`
+	Notes           string          `json:"notes"`
+}
+
+type CheckInRequest struct {
+	IssueID        string    `json:"issue_id"`
+	SubscriptionID string    `json:"subscription_id"`
+	Volume         int       `json:"volume"`
+	Number         int       `json:"number"`
+	Label          string    `json:"label"`
+	Barcode        string    `json:"barcode"`
+	Copies         int       `json:"copies" binding:"min=0"`
+	ReceivedDate   time.Time `json:"received_date"`
+	Notes          string    `json:"notes"`
+}
+
+type IssueActionRequest struct {
+	IssueIDs []string `json:"issue_ids" binding:"required,min=1"`
+	Notes    string   `json:"notes"`
+}
+
+type IssueCheckoutRequest struct {
+	IssueID      string `json:"issue_id"`
+	Barcode      string `json:"barcode"`
+	MembershipID string `json:"membership_id"`
+	MemberID     string `json:"member_id"`
+	BranchID     string `json:"branch_id"`
+}
+
+type IssueClaim struct {
+	Issue       SerialIssue `json:"issue"`
+	SerialTitle string      `json:"serial_title"`
+	ISSN        string      `json:"issn"`
+	VendorID    *uuid.UUID  `json:"vendor_id"`
+	DaysLate    int         `json:"days_late"`
+}
+
+type IssueActionResult struct {
+	Issues  []SerialIssue `json:"issues"`
+	Skipped []string      `json:"skipped"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSerialIssueDates(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	
+	tests := []struct {
+		name      string
+		frequency SerialFrequency
+		first     time.Time
+		want      []time.Time
+	}{
+		{
+			name:      "monthly from the 31st clamps and recovers",
+			frequency: FrequencyMonthly,
+			first:     date(2024, time.January, 31),
+			want: []time.Time{
+				date(2024, time.January, 31), date(2024, time.February, 29), date(2024, time.March, 31),
+				date(2024, time.April, 30), date(2024, time.May, 31),
+			},
+		},
+		{
+			name:      "monthly from the 30th across a common year",
+			frequency: FrequencyMonthly,
+			first:     date(2023, time.January, 30),
+			want: []time.Time{
+				date(2023, time.January, 30), date(2023, time.February, 28), date(2023, time.March, 30),
+			},
+		},
+		{
+			name:      "quarterly from the 31st",
+			frequency: FrequencyQuarterly,
+			first:     date(2024, time.August, 31),
+			want: []time.Time{
+				date(2024, time.August, 31), date(2024, time.November, 30), date(2025, time.February, 28),
+				date(2025, time.May, 31),
+			},
+		},
+		{
+			name:      "weekly across a year end",
+			frequency: FrequencyWeekly,
+			first:     date(2024, time.December, 24),
+			want: []time.Time{
+				date(2024, time.December, 24), date(2024, time.December, 31), date(2025, time.January, 7),
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for n, want := range tt.want {
+				got := tt.frequency.IssueDate(tt.first, n)
+				if !got.Equal(want) {
+					t.Errorf("issue %d = %s, want %s", n, got.Format("2006-01-02"), want.Format("2006-01-02"))
+				}
+				if index := tt.frequency.IssueIndex(tt.first, got); index != n {
+					t.Errorf("index of %s = %d, want %d", got.Format("2006-01-02"), index, n)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StocktakeStatus string
+
+const (
+	StocktakeOpen      StocktakeStatus = "open"
+	StocktakeCompleted StocktakeStatus = "completed"
+	StocktakeCancelled StocktakeStatus = "cancelled"
+)
+
+type StocktakeFinding string
+
+const (
+	FindingOK          StocktakeFinding = "ok"
+	FindingMissing     StocktakeFinding = "missing"
+	FindingMisshelved  StocktakeFinding = "misshelved"
+	FindingUnexpected  StocktakeFinding = "unexpected"
+	FindingWrongStatus StocktakeFinding = "wrong_status"
+	FindingUnknown     StocktakeFinding = "unknown"
+	FindingDuplicate   StocktakeFinding = "duplicate"
+)
+
+type Stocktake struct {
+	ID              uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BranchID        uuid.UUID       `gorm:"type:uuid;not null;index" json:"branch_id"`
+	LocationID      *uuid.UUID      `gorm:"type:uuid;index" json:"location_id"`
+	Scope           string          `json:"scope"`
+	CallNumberStart string          `json:"call_number_start"`
+	CallNumberEnd   string          `json:"call_number_end"`
+	StartSort       string          `json:"-"`
+	EndSort         string          `json:"-"`
+	Status          StocktakeStatus `gorm:"type:varchar(20);default:'open';index" json:"status"`
+	Notes           string          `gorm:"type:text" json:"notes"`
+	StartedByID     uuid.UUID       `gorm:"type:uuid;not null" json:"started_by_id"`
+	CompletedAt     *time.Time      `json:"completed_at"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+func (s *Stocktake) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *Stocktake) IsOpen() bool {
+	return s.Status == StocktakeOpen
+}
+
+func (s *Stocktake) Close(status StocktakeStatus) {
+	now := time.Now()
+	s.Status = status
+	s.CompletedAt = &now
+}
+
+type StocktakeScan struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	StocktakeID uuid.UUID  `gorm:"type:uuid;not null;index" json:"stocktake_id"`
+	Barcode     string     `gorm:"not null" json:"barcode"`
+	BookID      *uuid.UUID `gorm:"type:uuid;index" json:"book_id"`
+	LocationID  *uuid.UUID `gorm:"type:uuid" json:"location_id"`
+	ScannedByID uuid.UUID  `gorm:"type:uuid;not null" json:"scanned_by_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (s *StocktakeScan) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+type StocktakeRequest struct {
+	BranchID        string `json:"branch_id"`
+	LocationID      string `json:"location_id"`
+	CallNumberStart string `json:"call_number_start"`
+	CallNumberEnd   string `json:"call_number_end"`
+	Notes           string `json:"notes"`
+}
+
+type StocktakeScanRequest struct {
+	StocktakeID string   `json:"stocktake_id" binding:"required"`
+	LocationID  string   `json:"location_id"`
+	Barcodes    []string `json:"barcodes" binding:"required"`
+}
+
+type StocktakeActionRequest struct {
+	StocktakeID string   `json:"stocktake_id" binding:"required"`
+	BookIDs     []string `json:"book_ids"`
+}
+
+type StocktakeScanResult struct {
+	Barcode string           `json:"barcode"`
+	BookID  *string          `json:"book_id,omitempty"`
+	Title   string           `json:"title,omitempty"`
+	Finding StocktakeFinding `json:"finding"`
+	Message string           `json:"message,omitempty"`
+}
+
+type StocktakeItem struct {
+	BookID     string   `json:"book_id"`
+	Title      string   `json:"title"`
+	Barcode    string   `json:"barcode"`
+	CallNumber string   `json:"call_number"`
+	Location   string   `json:"location"`
+	FoundAt    []string `json:"found_at,omitempty"`
+	Expected   int64    `json:"expected"`
+	Scanned    int64    `json:"scanned"`
+	Copies     int64    `json:"copies"`
+	Reason     string   `json:"reason,omitempty"`
+}
+
+type StocktakeReport struct {
+	Stocktake   Stocktake       `json:"stocktake"`
+	Expected    int64           `json:"expected"`
+	Scanned     int64           `json:"scanned"`
+	Missing     []StocktakeItem `json:"missing"`
+	Misshelved  []StocktakeItem `json:"misshelved"`
+	Unexpected  []StocktakeItem `json:"unexpected"`
+	WrongStatus []StocktakeItem `json:"wrong_status"`
+	Unknown     []string        `json:"unknown"`
+}
+
+type StocktakeActionReport struct {
+	Updated int      `json:"updated"`
+	Copies  int64    `json:"copies"`
+	Skipped []string `json:"skipped"`
+}
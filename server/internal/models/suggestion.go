@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SuggestionStatus string
+
+const (
+	SuggestionPending   SuggestionStatus = "pending"
+	SuggestionApproved  SuggestionStatus = "approved"
+	SuggestionOrdered   SuggestionStatus = "ordered"
+	SuggestionRejected  SuggestionStatus = "rejected"
+	SuggestionFulfilled SuggestionStatus = "fulfilled"
+)
+
+type PurchaseSuggestion struct {
+	ID              uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MemberID        uuid.UUID        `gorm:"type:uuid;not null;index" json:"member_id"`
+	ISBN            string           `gorm:"index" json:"isbn"`
+	Title           string           `gorm:"not null" json:"title"`
+	Author          string           `json:"author"`
+	Publisher       string           `json:"publisher"`
+	Reason          string           `gorm:"type:text" json:"reason"`
+	Status          SuggestionStatus `gorm:"type:varchar(20);default:'pending';index" json:"status"`
+	DuplicateOfID   *uuid.UUID       `gorm:"type:uuid;index" json:"duplicate_of_id"`
+	ReviewedByID    *uuid.UUID       `gorm:"type:uuid" json:"reviewed_by_id"`
+	ReviewedAt      *time.Time       `json:"reviewed_at"`
+	RejectionReason string           `gorm:"type:text" json:"rejection_reason"`
+	OrderLineID     *uuid.UUID       `gorm:"type:uuid;index" json:"order_line_id"`
+	BookID          *uuid.UUID       `gorm:"type:uuid;index" json:"book_id"`
+	ReservationID   *uuid.UUID       `gorm:"type:uuid" json:"reservation_id"`
+	FulfilledAt     *time.Time       `json:"fulfilled_at"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+	
+	Member Member `gorm:"foreignKey:MemberID" json:"member,omitempty"`
+}
+
+func (s *PurchaseSuggestion) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *PurchaseSuggestion) IsOpen() bool {
+	return s.Status == SuggestionPending || s.Status == SuggestionApproved || s.Status == SuggestionOrdered
+}
+
+func (s *PurchaseSuggestion) Review(status SuggestionStatus, reviewerID uuid.UUID) {
+	now := time.Now()
+	s.Status = status
+	s.ReviewedByID = &reviewerID
+	s.ReviewedAt = &now
+}
+
+type SuggestionRequest struct {
+	ISBN      string `json:"isbn"`
+	Title     string `json:"title" binding:"required"`
+	Author    string `json:"author"`
+	Publisher string `json:"publisher"`
+	Reason    string `json:"reason"`
+}
+
+type SuggestionReviewRequest struct {
+	SuggestionID string `json:"suggestion_id" binding:"required"`
+	Reason       string `json:"reason"`
+	OrderLineID  string `json:"order_line_id"`
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WeedingStatus string
+
+const (
+	WeedingProposed  WeedingStatus = "proposed"
+	WeedingWithdrawn WeedingStatus = "withdrawn"
+	WeedingKept      WeedingStatus = "kept"
+)
+
+type WeedingCandidate struct {
+	ID           uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BookID       uuid.UUID     `gorm:"type:uuid;not null;index" json:"book_id"`
+	BranchID     uuid.UUID     `gorm:"type:uuid;not null;index" json:"branch_id"`
+	Copies       int           `gorm:"default:0" json:"copies"`
+	Criteria     string        `json:"criteria"`
+	Status       WeedingStatus `gorm:"type:varchar(20);default:'proposed';index" json:"status"`
+	Reason       string        `gorm:"type:text" json:"reason"`
+	Withdrawn    int           `gorm:"default:0" json:"withdrawn"`
+	ProposedByID uuid.UUID     `gorm:"type:uuid;not null" json:"proposed_by_id"`
+	DecidedByID  *uuid.UUID    `gorm:"type:uuid" json:"decided_by_id"`
+	DecidedAt    *time.Time    `json:"decided_at"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	
+	Book Book `gorm:"foreignKey:BookID" json:"book,omitempty"`
+}
+
+func (w *WeedingCandidate) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+func (w *WeedingCandidate) Decide(status WeedingStatus, reason string, deciderID uuid.UUID) {
+	now := time.Now()
+	w.Status = status
+	w.Reason = reason
+	w.DecidedByID = &deciderID
+	w.DecidedAt = &now
+}
+
+type WeedingProposalRequest struct {
+	BranchID string   `json:"branch_id"`
+	BookIDs  []string `json:"book_ids"`
+	Years    int      `json:"years"`
+	Category string   `json:"category"`
+	Copies   int      `json:"copies" binding:"min=0"`
+	Criteria string   `json:"criteria"`
+}
+
+type WeedingDecisionRequest struct {
+	CandidateIDs []string `json:"candidate_ids" binding:"required,min=1"`
+	Reason       string   `json:"reason"`
+}
+
+type DormantItem struct {
+	BookID       string     `json:"book_id"`
+	Title        string     `json:"title"`
+	Author       string     `json:"author"`
+	CallNumber   string     `json:"call_number"`
+	Category     string     `json:"category"`
+	PublishDate  time.Time  `json:"publish_date"`
+	TotalCopies  int        `json:"total_copies"`
+	Loans        int64      `json:"loans"`
+	LastLoanDate *time.Time `json:"last_loan_date"`
+}
+
+type CategoryTurnover struct {
+	Category string  `json:"category"`
+	Titles   int64   `json:"titles"`
+	Copies   int64   `json:"copies"`
+	Loans    int64   `json:"loans"`
+	Turnover float64 `json:"turnover"`
+	Low      bool    `json:"low"`
+}
+
+type TurnoverReport struct {
+	Years      int                `json:"years"`
+	Overall    float64            `json:"overall"`
+	Threshold  float64            `json:"threshold"`
+	Categories []CategoryTurnover `json:"categories"`
+}
+
+type AgeBand struct {
+	Label  string  `json:"label"`
+	Titles int64   `json:"titles"`
+	Copies int64   `json:"copies"`
+	Share  float64 `json:"share"`
+}
+
+type CategoryAge struct {
+	Category   string  `json:"category"`
+	Titles     int64   `json:"titles"`
+	AverageAge float64 `json:"average_age"`
+	MedianYear int     `json:"median_year"`
+	OldestYear int     `json:"oldest_year"`
+}
+
+type CollectionAgeReport struct {
+	AverageAge float64       `json:"average_age"`
+	Bands      []AgeBand     `json:"bands"`
+	Categories []CategoryAge `json:"categories"`
+}
+
+type DemandRatio struct {
+	BookID        string  `json:"book_id"`
+	Title         string  `json:"title"`
+	Author        string  `json:"author"`
+	TotalCopies   int     `json:"total_copies"`
+	Holds         int64   `json:"holds"`
+	CopiesPerHold float64 `json:"copies_per_hold"`
+	HoldsPerCopy  float64 `json:"holds_per_copy"`
+	CopiesNeeded  int     `json:"copies_needed"`
+}
+
+type WeedingResult struct {
+	Candidates []WeedingCandidate `json:"candidates"`
+	Copies     int                `json:"copies"`
+	Skipped    []string           `json:"skipped"`
+}
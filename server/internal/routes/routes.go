@@ -4,6 +4,7 @@ import (
 	"github.com/library-management-system/server/internal/config"
 	"github.com/library-management-system/server/internal/handlers"
 	"github.com/library-management-system/server/internal/middleware"
+	"github.com/library-management-system/server/internal/models"
 	"github.com/library-management-system/server/pkg/auth"
 	"github.com/library-management-system/server/pkg/logger"
 	
@@ -16,7 +17,23 @@ func Setup(router *gin.RouterGroup, db *gorm.DB, redis *redis.Client, cfg *confi
 	auth.InitJWT(cfg.JWT.Secret)
 	
 	authHandler := handlers.NewAuthHandler(db, cfg)
-	bookHandler := handlers.NewBookHandler(db, cfg)
+	bookHandler := handlers.NewBookHandler(db, redis, cfg)
+	loanHandler := handlers.NewLoanHandler(db, cfg)
+	reservationHandler := handlers.NewReservationHandler(db, cfg)
+	repairHandler := handlers.NewRepairHandler(db, cfg)
+	circulationHandler := handlers.NewCirculationHandler(db, cfg)
+	memberHandler := handlers.NewMemberHandler(db, cfg)
+	importHandler := handlers.NewImportHandler(db, redis, cfg)
+	authorityHandler := handlers.NewAuthorityHandler(db, redis, cfg)
+	branchHandler := handlers.NewBranchHandler(db, cfg)
+	locationHandler := handlers.NewLocationHandler(db, cfg)
+	stocktakeHandler := handlers.NewStocktakeHandler(db, cfg)
+	acquisitionHandler := handlers.NewAcquisitionHandler(db, redis, cfg)
+	suggestionHandler := handlers.NewSuggestionHandler(db, cfg)
+	weedingHandler := handlers.NewWeedingHandler(db, cfg)
+	serialHandler := handlers.NewSerialHandler(db, cfg)
+	itemHandler := handlers.NewItemHandler(db, cfg)
+	digitalHandler := handlers.NewDigitalHandler(db, cfg)
 	
 	method := router.Group("/method")
 	{
@@ -35,12 +52,209 @@ func Setup(router *gin.RouterGroup, db *gorm.DB, redis *redis.Client, cfg *confi
 			bookRoutes.GET("/get_book", bookHandler.GetBook)
 			bookRoutes.GET("/get_available_books", bookHandler.GetAvailableBooks)
 			bookRoutes.GET("/search_books", bookHandler.SearchBooks)
+			bookRoutes.GET("/autocomplete", bookHandler.Autocomplete)
+			bookRoutes.GET("/browse_shelf", bookHandler.BrowseShelf)
+			bookRoutes.GET("/get_cover", bookHandler.GetCover)
+			bookRoutes.GET("/faceted_search", bookHandler.FacetedSearch)
+			bookRoutes.POST("/upload_cover", middleware.AuthRequired(db), middleware.LibrarianRequired(), bookHandler.UploadCover)
+			bookRoutes.GET("/lookup_metadata", middleware.AuthRequired(db), middleware.LibrarianRequired(), bookHandler.LookupMetadata)
+			bookRoutes.POST("/preview_metadata", middleware.AuthRequired(db), middleware.LibrarianRequired(), bookHandler.PreviewMetadata)
+			bookRoutes.GET("/get_isbn_report", middleware.AuthRequired(db), middleware.LibrarianRequired(), bookHandler.GetISBNReport)
+			bookRoutes.POST("/normalize_isbns", middleware.AuthRequired(db), middleware.LibrarianRequired(), bookHandler.NormalizeISBNs)
 			bookRoutes.GET("/get_book_statistics", middleware.AuthRequired(db), middleware.LibrarianRequired(), bookHandler.GetBookStatistics)
 			
 			bookRoutes.POST("/create_book", middleware.AuthRequired(db), middleware.LibrarianRequired(), bookHandler.CreateBook)
 			bookRoutes.POST("/update_book", middleware.AuthRequired(db), middleware.LibrarianRequired(), bookHandler.UpdateBook)
 			bookRoutes.POST("/delete_book", middleware.AuthRequired(db), middleware.AdminRequired(), bookHandler.DeleteBook)
+			bookRoutes.POST("/import_books", middleware.AuthRequired(db), middleware.LibrarianRequired(), importHandler.ImportBooks)
+			bookRoutes.GET("/get_import_job", middleware.AuthRequired(db), middleware.LibrarianRequired(), importHandler.GetImportJob)
+			bookRoutes.GET("/export_marc", middleware.AuthRequired(db), middleware.LibrarianRequired(), importHandler.ExportMARC)
 			bookRoutes.POST("/reserve_book", middleware.AuthRequired(db), bookHandler.ReserveBook)
 		}
+		
+		loanRoutes := method.Group("/library_management.api.loans")
+		{
+			loanRoutes.GET("/get_loans", middleware.AuthRequired(db), loanHandler.GetLoans)
+			loanRoutes.POST("/create_loan", middleware.AuthRequired(db), middleware.LibrarianRequired(), loanHandler.CreateLoan)
+			loanRoutes.POST("/return_book", middleware.AuthRequired(db), middleware.LibrarianRequired(), loanHandler.ReturnBook)
+			loanRoutes.POST("/renew_loan", middleware.AuthRequired(db), loanHandler.RenewLoan)
+			loanRoutes.POST("/declare_lost", middleware.AuthRequired(db), middleware.LibrarianRequired(), loanHandler.DeclareLost)
+		}
+		
+		memberRoutes := method.Group("/library_management.api.members")
+		{
+			memberRoutes.GET("/get_members", middleware.AuthRequired(db), middleware.LibrarianRequired(), memberHandler.GetMembers)
+			memberRoutes.POST("/update_home_branch", middleware.AuthRequired(db), memberHandler.UpdateHomeBranch)
+			memberRoutes.POST("/set_auto_renew", middleware.AuthRequired(db), memberHandler.SetAutoRenew)
+		}
+		
+		circulationRoutes := method.Group("/library_management.api.circulation")
+		{
+			circulationRoutes.POST("/open_session", middleware.AuthRequired(db), middleware.LibrarianRequired(), circulationHandler.OpenSession)
+			circulationRoutes.POST("/checkout_items", middleware.AuthRequired(db), middleware.LibrarianRequired(), circulationHandler.CheckoutItems)
+			circulationRoutes.POST("/checkin_items", middleware.AuthRequired(db), middleware.LibrarianRequired(), circulationHandler.CheckinItems)
+			circulationRoutes.POST("/close_session", middleware.AuthRequired(db), middleware.LibrarianRequired(), circulationHandler.CloseSession)
+			circulationRoutes.GET("/get_slip", middleware.AuthRequired(db), middleware.LibrarianRequired(), circulationHandler.GetSlip)
+		}
+		
+		repairRoutes := method.Group("/library_management.api.repairs")
+		{
+			repairRoutes.GET("/get_repair_queue", middleware.AuthRequired(db), middleware.LibrarianRequired(), repairHandler.GetRepairQueue)
+			repairRoutes.POST("/report_damage", middleware.AuthRequired(db), middleware.LibrarianRequired(), repairHandler.ReportDamage)
+			repairRoutes.POST("/update_repair", middleware.AuthRequired(db), middleware.LibrarianRequired(), repairHandler.UpdateRepair)
+		}
+		
+		reservationRoutes := method.Group("/library_management.api.reservations")
+		{
+			reservationRoutes.GET("/get_reservations", middleware.AuthRequired(db), reservationHandler.GetReservations)
+			reservationRoutes.GET("/get_hold_shelf", middleware.AuthRequired(db), middleware.LibrarianRequired(), reservationHandler.GetHoldShelf)
+			reservationRoutes.POST("/expire_holds", middleware.AuthRequired(db), middleware.LibrarianRequired(), reservationHandler.ExpireHolds)
+		}
+		
+		authorityRoutes := method.Group("/library_management.api.authorities")
+		{
+			authorityRoutes.GET("/browse_authors", authorityHandler.Browse(models.AuthorityAuthor))
+			authorityRoutes.GET("/browse_publishers", authorityHandler.Browse(models.AuthorityPublisher))
+			authorityRoutes.GET("/browse_series", authorityHandler.Browse(models.AuthoritySeries))
+			authorityRoutes.GET("/browse_subjects", authorityHandler.Browse(models.AuthoritySubject))
+			authorityRoutes.GET("/get_author", authorityHandler.Get(models.AuthorityAuthor))
+			authorityRoutes.GET("/get_publisher", authorityHandler.Get(models.AuthorityPublisher))
+			authorityRoutes.GET("/get_series", authorityHandler.Get(models.AuthoritySeries))
+			authorityRoutes.GET("/get_subject", authorityHandler.Get(models.AuthoritySubject))
+			authorityRoutes.POST("/update_authority", middleware.AuthRequired(db), middleware.LibrarianRequired(), authorityHandler.UpdateAuthority)
+			authorityRoutes.POST("/merge_authorities", middleware.AuthRequired(db), middleware.LibrarianRequired(), authorityHandler.MergeAuthorities)
+			authorityRoutes.GET("/get_duplicate_suggestions", middleware.AuthRequired(db), middleware.LibrarianRequired(), authorityHandler.GetDuplicateSuggestions)
+			authorityRoutes.POST("/migrate_authorities", middleware.AuthRequired(db), middleware.LibrarianRequired(), authorityHandler.MigrateAuthorities)
+		}
+		
+		branchRoutes := method.Group("/library_management.api.branches")
+		{
+			branchRoutes.GET("/get_branches", branchHandler.GetBranches)
+			branchRoutes.GET("/get_holdings", branchHandler.GetHoldings)
+			branchRoutes.POST("/create_branch", middleware.AuthRequired(db), middleware.AdminRequired(), branchHandler.CreateBranch)
+			branchRoutes.POST("/update_branch", middleware.AuthRequired(db), middleware.AdminRequired(), branchHandler.UpdateBranch)
+			branchRoutes.POST("/update_holding", middleware.AuthRequired(db), middleware.LibrarianRequired(), branchHandler.UpdateHolding)
+			branchRoutes.GET("/get_transfers", middleware.AuthRequired(db), middleware.LibrarianRequired(), branchHandler.GetTransfers)
+			branchRoutes.POST("/request_transfer", middleware.AuthRequired(db), middleware.LibrarianRequired(), branchHandler.RequestTransfer)
+			branchRoutes.POST("/ship_transfer", middleware.AuthRequired(db), middleware.LibrarianRequired(), branchHandler.ShipTransfer)
+			branchRoutes.POST("/receive_transfer", middleware.AuthRequired(db), middleware.LibrarianRequired(), branchHandler.ReceiveTransfer)
+			branchRoutes.POST("/cancel_transfer", middleware.AuthRequired(db), middleware.LibrarianRequired(), branchHandler.CancelTransfer)
+		}
+		
+		locationRoutes := method.Group("/library_management.api.locations")
+		{
+			locationRoutes.GET("/get_locations", locationHandler.GetLocations)
+			locationRoutes.GET("/where_is", locationHandler.WhereIs)
+			locationRoutes.POST("/create_location", middleware.AuthRequired(db), middleware.LibrarianRequired(), locationHandler.CreateLocation)
+			locationRoutes.POST("/update_location", middleware.AuthRequired(db), middleware.LibrarianRequired(), locationHandler.UpdateLocation)
+			locationRoutes.POST("/move_location", middleware.AuthRequired(db), middleware.LibrarianRequired(), locationHandler.MoveLocation)
+			locationRoutes.POST("/relocate_items", middleware.AuthRequired(db), middleware.LibrarianRequired(), locationHandler.RelocateItems)
+		}
+		
+		stocktakeRoutes := method.Group("/library_management.api.stocktakes")
+		{
+			stocktakeRoutes.GET("/get_stocktakes", middleware.AuthRequired(db), middleware.LibrarianRequired(), stocktakeHandler.GetStocktakes)
+			stocktakeRoutes.GET("/get_stocktake_report", middleware.AuthRequired(db), middleware.LibrarianRequired(), stocktakeHandler.GetStocktakeReport)
+			stocktakeRoutes.POST("/start_stocktake", middleware.AuthRequired(db), middleware.LibrarianRequired(), stocktakeHandler.StartStocktake)
+			stocktakeRoutes.POST("/scan_items", middleware.AuthRequired(db), middleware.LibrarianRequired(), stocktakeHandler.ScanItems)
+			stocktakeRoutes.POST("/complete_stocktake", middleware.AuthRequired(db), middleware.LibrarianRequired(), stocktakeHandler.CompleteStocktake)
+			stocktakeRoutes.POST("/cancel_stocktake", middleware.AuthRequired(db), middleware.LibrarianRequired(), stocktakeHandler.CancelStocktake)
+			stocktakeRoutes.POST("/mark_missing_lost", middleware.AuthRequired(db), middleware.LibrarianRequired(), stocktakeHandler.MarkMissingLost)
+			stocktakeRoutes.POST("/fix_locations", middleware.AuthRequired(db), middleware.LibrarianRequired(), stocktakeHandler.FixLocations)
+		}
+		
+		acquisitionRoutes := method.Group("/library_management.api.acquisitions")
+		{
+			acquisitionRoutes.GET("/get_vendors", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.GetVendors)
+			acquisitionRoutes.POST("/create_vendor", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.CreateVendor)
+			acquisitionRoutes.POST("/update_vendor", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.UpdateVendor)
+			acquisitionRoutes.GET("/get_funds", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.GetFunds)
+			acquisitionRoutes.GET("/get_fund_report", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.GetFundReport)
+			acquisitionRoutes.POST("/create_fund", middleware.AuthRequired(db), middleware.AdminRequired(), acquisitionHandler.CreateFund)
+			acquisitionRoutes.POST("/update_fund", middleware.AuthRequired(db), middleware.AdminRequired(), acquisitionHandler.UpdateFund)
+			acquisitionRoutes.GET("/get_orders", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.GetOrders)
+			acquisitionRoutes.GET("/get_order", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.GetOrder)
+			acquisitionRoutes.POST("/create_order", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.CreateOrder)
+			acquisitionRoutes.POST("/update_order", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.UpdateOrder)
+			acquisitionRoutes.POST("/place_order", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.PlaceOrder)
+			acquisitionRoutes.POST("/receive_items", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.ReceiveItems)
+			acquisitionRoutes.POST("/cancel_order", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.CancelOrder)
+			acquisitionRoutes.GET("/get_invoices", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.GetInvoices)
+			acquisitionRoutes.POST("/create_invoice", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.CreateInvoice)
+			acquisitionRoutes.POST("/pay_invoice", middleware.AuthRequired(db), middleware.AdminRequired(), acquisitionHandler.PayInvoice)
+			acquisitionRoutes.POST("/cancel_invoice", middleware.AuthRequired(db), middleware.LibrarianRequired(), acquisitionHandler.CancelInvoice)
+		}
+		
+		suggestionRoutes := method.Group("/library_management.api.suggestions")
+		{
+			suggestionRoutes.GET("/get_my_suggestions", middleware.AuthRequired(db), suggestionHandler.GetMySuggestions)
+			suggestionRoutes.POST("/suggest_title", middleware.AuthRequired(db), suggestionHandler.SuggestTitle)
+			suggestionRoutes.GET("/get_suggestions", middleware.AuthRequired(db), middleware.LibrarianRequired(), suggestionHandler.GetSuggestions)
+			suggestionRoutes.POST("/approve_suggestion", middleware.AuthRequired(db), middleware.LibrarianRequired(), suggestionHandler.ApproveSuggestion)
+			suggestionRoutes.POST("/reject_suggestion", middleware.AuthRequired(db), middleware.LibrarianRequired(), suggestionHandler.RejectSuggestion)
+			suggestionRoutes.POST("/mark_suggestion_ordered", middleware.AuthRequired(db), middleware.LibrarianRequired(), suggestionHandler.MarkSuggestionOrdered)
+		}
+		
+		weedingRoutes := method.Group("/library_management.api.weeding")
+		{
+			weedingRoutes.GET("/get_dormant_items", middleware.AuthRequired(db), middleware.LibrarianRequired(), weedingHandler.GetDormantItems)
+			weedingRoutes.GET("/get_turnover_report", middleware.AuthRequired(db), middleware.LibrarianRequired(), weedingHandler.GetTurnoverReport)
+			weedingRoutes.GET("/get_collection_age", middleware.AuthRequired(db), middleware.LibrarianRequired(), weedingHandler.GetCollectionAge)
+			weedingRoutes.GET("/get_demand_report", middleware.AuthRequired(db), middleware.LibrarianRequired(), weedingHandler.GetDemandReport)
+			weedingRoutes.GET("/get_weeding_candidates", middleware.AuthRequired(db), middleware.LibrarianRequired(), weedingHandler.GetWeedingCandidates)
+			weedingRoutes.POST("/propose_weeding", middleware.AuthRequired(db), middleware.LibrarianRequired(), weedingHandler.ProposeWeeding)
+			weedingRoutes.POST("/withdraw_candidates", middleware.AuthRequired(db), middleware.AdminRequired(), weedingHandler.WithdrawCandidates)
+			weedingRoutes.POST("/keep_candidates", middleware.AuthRequired(db), middleware.LibrarianRequired(), weedingHandler.KeepCandidates)
+		}
+		
+		serialRoutes := method.Group("/library_management.api.serials")
+		{
+			serialRoutes.GET("/get_serials", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.GetSerials)
+			serialRoutes.POST("/create_serial", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.CreateSerial)
+			serialRoutes.POST("/update_serial", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.UpdateSerial)
+			serialRoutes.GET("/get_subscriptions", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.GetSubscriptions)
+			serialRoutes.POST("/create_subscription", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.CreateSubscription)
+			serialRoutes.POST("/cancel_subscription", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.CancelSubscription)
+			serialRoutes.POST("/predict_issues", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.PredictIssues)
+			serialRoutes.GET("/get_issues", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.GetIssues)
+			serialRoutes.POST("/check_in_issue", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.CheckInIssue)
+			serialRoutes.GET("/get_claims_due", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.GetClaimsDue)
+			serialRoutes.POST("/claim_issues", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.ClaimIssues)
+			serialRoutes.POST("/mark_issues_missing", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.MarkIssuesMissing)
+			serialRoutes.POST("/checkout_issue", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.CheckoutIssue)
+			serialRoutes.POST("/return_issue", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.ReturnIssue)
+			serialRoutes.POST("/renew_issue", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.RenewIssue)
+			serialRoutes.GET("/get_issue_loans", middleware.AuthRequired(db), middleware.LibrarianRequired(), serialHandler.GetIssueLoans)
+		}
+		
+		itemRoutes := method.Group("/library_management.api.items")
+		{
+			itemRoutes.GET("/get_item_types", middleware.AuthRequired(db), itemHandler.GetItemTypes)
+			itemRoutes.POST("/update_item_type_rule", middleware.AuthRequired(db), middleware.AdminRequired(), itemHandler.UpdateItemTypeRule)
+			itemRoutes.GET("/get_rooms", middleware.AuthRequired(db), itemHandler.GetRooms)
+			itemRoutes.GET("/get_room_calendar", middleware.AuthRequired(db), itemHandler.GetRoomCalendar)
+			itemRoutes.GET("/get_room_bookings", middleware.AuthRequired(db), middleware.LibrarianRequired(), itemHandler.GetRoomBookings)
+			itemRoutes.GET("/get_my_room_bookings", middleware.AuthRequired(db), itemHandler.GetMyRoomBookings)
+			itemRoutes.POST("/book_room", middleware.AuthRequired(db), itemHandler.BookRoom)
+			itemRoutes.POST("/cancel_room_booking", middleware.AuthRequired(db), itemHandler.CancelRoomBooking)
+		}
+		
+		digitalRoutes := method.Group("/library_management.api.digital")
+		{
+			digitalRoutes.GET("/get_digital_items", digitalHandler.GetDigitalItems)
+			digitalRoutes.POST("/create_digital_item", middleware.AuthRequired(db), middleware.LibrarianRequired(), digitalHandler.CreateDigitalItem)
+			digitalRoutes.POST("/update_digital_item", middleware.AuthRequired(db), middleware.LibrarianRequired(), digitalHandler.UpdateDigitalItem)
+			digitalRoutes.POST("/upload_digital_file", middleware.AuthRequired(db), middleware.LibrarianRequired(), digitalHandler.UploadDigitalFile)
+			digitalRoutes.GET("/get_digital_checkouts", middleware.AuthRequired(db), middleware.LibrarianRequired(), digitalHandler.GetDigitalCheckouts)
+			digitalRoutes.GET("/get_digital_holds", middleware.AuthRequired(db), middleware.LibrarianRequired(), digitalHandler.GetDigitalHolds)
+			digitalRoutes.POST("/checkout_digital", middleware.AuthRequired(db), digitalHandler.CheckoutDigital)
+			digitalRoutes.POST("/return_digital", middleware.AuthRequired(db), digitalHandler.ReturnDigital)
+			digitalRoutes.GET("/get_my_digital_checkouts", middleware.AuthRequired(db), digitalHandler.GetMyDigitalCheckouts)
+			digitalRoutes.POST("/place_digital_hold", middleware.AuthRequired(db), digitalHandler.PlaceDigitalHold)
+			digitalRoutes.POST("/cancel_digital_hold", middleware.AuthRequired(db), digitalHandler.CancelDigitalHold)
+			digitalRoutes.GET("/get_my_digital_holds", middleware.AuthRequired(db), digitalHandler.GetMyDigitalHolds)
+			digitalRoutes.GET("/get_download_link", middleware.AuthRequired(db), digitalHandler.GetDownloadLink)
+			digitalRoutes.GET("/download", digitalHandler.Download)
+		}
 	}
 }
\ No newline at end of file
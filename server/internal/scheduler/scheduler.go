@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/internal/storage"
+	"github.com/library-management-system/server/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+type Scheduler struct {
+	circulation *services.CirculationService
+	serials     *services.SerialService
+	digital     *services.DigitalService
+	log         *logger.Logger
+	interval    time.Duration
+	stop        chan struct{}
+}
+
+func New(db *gorm.DB, cfg *config.Config, log *logger.Logger) *Scheduler {
+	return &Scheduler{
+		circulation: services.NewCirculationService(db, cfg),
+		serials:     services.NewSerialService(db, cfg),
+		digital:     services.NewDigitalService(db, storage.NewFromConfig(cfg.Storage), cfg),
+		log:         log,
+		interval:    cfg.Scheduler.Interval,
+		stop:        make(chan struct{}),
+	}
+}
+
+func (s *Scheduler) Start() {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		
+		s.run()
+		for {
+			select {
+			case <-ticker.C:
+				s.run()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	
+	s.log.Info("Scheduler started", "interval", s.interval)
+}
+
+func (s *Scheduler) Stop() {
+	close(s.stop)
+}
+
+func (s *Scheduler) run() {
+	expired, err := s.circulation.ExpireHolds()
+	if err != nil {
+		s.log.Error("Failed to expire holds", "error", err)
+	} else if expired > 0 {
+		s.log.Info("Expired uncollected holds", "count", expired)
+	}
+	
+	renewed, err := s.circulation.AutoRenew()
+	if err != nil {
+		s.log.Error("Failed to auto-renew loans", "error", err)
+	} else if renewed > 0 {
+		s.log.Info("Auto-renewed loans", "count", renewed)
+	}
+	
+	lost, err := s.circulation.AutoDeclareLost()
+	if err != nil {
+		s.log.Error("Failed to declare long overdue loans lost", "error", err)
+	} else if lost > 0 {
+		s.log.Info("Declared long overdue loans lost", "count", lost)
+	}
+	
+	predicted, err := s.serials.PredictDue()
+	if err != nil {
+		s.log.Error("Failed to predict serial issues", "error", err)
+	} else if predicted > 0 {
+		s.log.Info("Predicted expected serial issues", "count", predicted)
+	}
+	
+	returned, err := s.digital.ExpireDue()
+	if err != nil {
+		s.log.Error("Failed to expire digital checkouts", "error", err)
+	} else if returned > 0 {
+		s.log.Info("Returned expired digital checkouts", "count", returned)
+	}
+}
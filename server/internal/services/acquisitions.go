@@ -0,0 +1,734 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/pkg/isbn"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrVendorNotFound     = errors.New("vendor not found")
+	ErrVendorInactive     = errors.New("vendor is inactive")
+	ErrVendorExists       = errors.New("vendor code already exists")
+	ErrFundNotFound       = errors.New("fund not found")
+	ErrFundInactive       = errors.New("fund is inactive")
+	ErrFundExists         = errors.New("a fund with this code already exists for the fiscal year")
+	ErrFundRequired       = errors.New("every order line needs a fund")
+	ErrFundOverspent      = errors.New("order exceeds the available balance of fund")
+	ErrOrderNotFound      = errors.New("purchase order not found")
+	ErrOrderNotDraft      = errors.New("only draft orders can be changed or placed")
+	ErrOrderNotOpen       = errors.New("purchase order is not open for receiving")
+	ErrOrderClosed        = errors.New("purchase order is already closed")
+	ErrOrderLineNotFound  = errors.New("order line not found on this order")
+	ErrOrderLineTitle     = errors.New("order lines need a book_id or an isbn and title")
+	ErrOrderLineInvalid   = errors.New("invalid order line")
+	ErrOverReceipt        = errors.New("quantity exceeds the copies still outstanding on the line")
+	ErrInvoiceNotFound    = errors.New("invoice not found")
+	ErrInvoiceExists      = errors.New("this vendor already has an invoice with that number")
+	ErrInvoiceNotOpen     = errors.New("invoice is not open")
+	ErrInvoiceOrderVendor = errors.New("invoice order belongs to a different vendor")
+)
+
+var encumberingStatuses = []models.OrderStatus{models.OrderPlaced, models.OrderPartiallyReceived, models.OrderReceived}
+
+type AcquisitionService struct {
+	db          *gorm.DB
+	redis       *redis.Client
+	config      *config.Config
+	circulation *CirculationService
+	locations   *LocationService
+	authorities *AuthorityService
+	suggestions *PurchaseSuggestionService
+}
+
+func NewAcquisitionService(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *AcquisitionService {
+	return &AcquisitionService{
+		db:          db,
+		redis:       redisClient,
+		config:      cfg,
+		circulation: NewCirculationService(db, cfg),
+		locations:   NewLocationService(db, cfg),
+		authorities: NewAuthorityService(db, redisClient),
+		suggestions: NewPurchaseSuggestionService(db, cfg),
+	}
+}
+
+func (s *AcquisitionService) Orders(vendorID, status string) ([]models.PurchaseOrder, error) {
+	query := s.db.Preload("Vendor").Preload("Lines")
+	if vendorID != "" {
+		query = query.Where("vendor_id = ?", vendorID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	
+	var orders []models.PurchaseOrder
+	err := query.Order("created_at DESC").Find(&orders).Error
+	return orders, err
+}
+
+func (s *AcquisitionService) Order(id string) (*models.PurchaseOrder, error) {
+	return s.findOrder(s.db, id)
+}
+
+func (s *AcquisitionService) CreateOrder(req models.OrderRequest, createdByID uuid.UUID) (*models.PurchaseOrder, error) {
+	order := models.PurchaseOrder{
+		Status:      models.OrderDraft,
+		CreatedByID: createdByID,
+	}
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.applyOrderRequest(tx, &order, req); err != nil {
+			return err
+		}
+		
+		order.Number = nextOrderNumber(tx)
+		return tx.Omit("Vendor").Create(&order).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return s.findOrder(s.db, order.ID.String())
+}
+
+func (s *AcquisitionService) UpdateOrder(id string, req models.OrderRequest) (*models.PurchaseOrder, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		order, err := s.lockOrder(tx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != models.OrderDraft {
+			return ErrOrderNotDraft
+		}
+		
+		if err := tx.Where("order_id = ?", order.ID).Delete(&models.PurchaseOrderLine{}).Error; err != nil {
+			return err
+		}
+		if err := s.applyOrderRequest(tx, order, req); err != nil {
+			return err
+		}
+		if err := tx.Omit("Lines", "Vendor").Save(order).Error; err != nil {
+			return err
+		}
+		return tx.Create(&order.Lines).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return s.findOrder(s.db, id)
+}
+
+func (s *AcquisitionService) PlaceOrder(id string) (*models.PurchaseOrder, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		order, err := s.lockOrder(tx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != models.OrderDraft {
+			return ErrOrderNotDraft
+		}
+		
+		costs := make(map[uuid.UUID]float64)
+		for _, line := range order.Lines {
+			costs[line.FundID] += line.Cost()
+		}
+		
+		for fundID, cost := range costs {
+			fund, err := s.activeFund(tx, fundID.String())
+			if err != nil {
+				return err
+			}
+			summary, err := s.summarize(tx, []models.Fund{*fund})
+			if err != nil {
+				return err
+			}
+			if cost > summary[0].Available+0.005 {
+				return fmt.Errorf("%w %s (%.2f available)", ErrFundOverspent, fund.Code, summary[0].Available)
+			}
+		}
+		
+		now := time.Now()
+		order.Status = models.OrderPlaced
+		order.OrderedAt = &now
+		return tx.Omit("Lines", "Vendor").Save(order).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	invalidateAutocomplete(context.Background(), s.redis)
+		return s.findOrder(s.db, id)
+}
+
+func (s *AcquisitionService) CancelOrder(id string) (*models.PurchaseOrder, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		order, err := s.lockOrder(tx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != models.OrderDraft && !order.IsOpen() {
+			return ErrOrderClosed
+		}
+		
+		received := false
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			if line.Received > 0 {
+				received = true
+			}
+			line.Cancelled = line.Quantity - line.Received
+			if err := tx.Model(line).Update("cancelled", line.Cancelled).Error; err != nil {
+				return err
+			}
+		}
+		
+		now := time.Now()
+		order.Status = models.OrderCancelled
+		if received {
+			order.Status = models.OrderReceived
+		}
+		order.ClosedAt = &now
+		return tx.Omit("Lines", "Vendor").Save(order).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return s.findOrder(s.db, id)
+}
+
+func (s *AcquisitionService) Receive(req models.ReceiveRequest) (*models.ReceiveResult, error) {
+	result := &models.ReceiveResult{}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		order, err := s.lockOrder(tx, req.OrderID)
+		if err != nil {
+			return err
+		}
+		if !order.IsOpen() {
+			return ErrOrderNotOpen
+		}
+		
+		var location *models.Location
+		if req.LocationID != "" {
+			if location, err = s.locations.Resolve(tx, order.BranchID, req.LocationID, ""); err != nil {
+				return err
+			}
+		}
+		
+		lines := make(map[string]*models.PurchaseOrderLine, len(order.Lines))
+		for i := range order.Lines {
+			lines[order.Lines[i].ID.String()] = &order.Lines[i]
+		}
+		
+		for _, item := range req.Lines {
+			line, ok := lines[item.LineID]
+			if !ok {
+				return ErrOrderLineNotFound
+			}
+			if item.Quantity > line.Outstanding() {
+				return ErrOverReceipt
+			}
+			
+			received, err := s.receiveLine(tx, order, line, item.Quantity, location)
+			if err != nil {
+				return err
+			}
+			result.Lines = append(result.Lines, received)
+		}
+		
+		order.Status = models.OrderReceived
+		for _, line := range order.Lines {
+			if line.Outstanding() > 0 {
+				order.Status = models.OrderPartiallyReceived
+				break
+			}
+		}
+		if order.Status == models.OrderReceived {
+			now := time.Now()
+			order.ClosedAt = &now
+		}
+		return tx.Omit("Lines", "Vendor").Save(order).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	
+	result.Order, err = s.findOrder(s.db, req.OrderID)
+	return result, err
+}
+
+func (s *AcquisitionService) Invoices(vendorID, status string) ([]models.Invoice, error) {
+	query := s.db.Preload("Lines")
+	if vendorID != "" {
+		query = query.Where("vendor_id = ?", vendorID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	
+	var invoices []models.Invoice
+	err := query.Order("invoice_date DESC").Find(&invoices).Error
+	return invoices, err
+}
+
+func (s *AcquisitionService) CreateInvoice(req models.InvoiceRequest, createdByID uuid.UUID) (*models.Invoice, error) {
+	invoice := models.Invoice{
+		Number:      strings.TrimSpace(req.Number),
+		InvoiceDate: req.InvoiceDate,
+		Status:      models.InvoiceOpen,
+		CreatedByID: createdByID,
+	}
+	if invoice.InvoiceDate.IsZero() {
+		invoice.InvoiceDate = time.Now()
+	}
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		vendor, err := s.findVendor(tx, req.VendorID)
+		if err != nil {
+			return err
+		}
+		invoice.VendorID = vendor.ID
+		
+		var existing int64
+		tx.Model(&models.Invoice{}).Where("vendor_id = ? AND number = ?", vendor.ID, invoice.Number).Count(&existing)
+		if existing > 0 {
+			return ErrInvoiceExists
+		}
+		
+		var order *models.PurchaseOrder
+		if req.OrderID != "" {
+			if order, err = s.findOrder(tx, req.OrderID); err != nil {
+				return err
+			}
+			if order.VendorID != vendor.ID {
+				return ErrInvoiceOrderVendor
+			}
+			invoice.OrderID = &order.ID
+		}
+		
+		for _, item := range req.Lines {
+			line := models.InvoiceLine{
+				Description: item.Description,
+				Quantity:    item.Quantity,
+				Amount:      item.Amount,
+			}
+			if line.Quantity == 0 {
+				line.Quantity = 1
+			}
+			
+			if item.OrderLineID != "" {
+				var orderLine models.PurchaseOrderLine
+				if err := tx.First(&orderLine, "id = ?", item.OrderLineID).Error; err != nil {
+					return ErrOrderLineNotFound
+				}
+				if order != nil && orderLine.OrderID != order.ID {
+					return ErrOrderLineNotFound
+				}
+				line.OrderLineID = &orderLine.ID
+				line.FundID = orderLine.FundID
+				if line.Description == "" {
+					line.Description = orderLine.Title
+				}
+			} else {
+				if item.FundID == "" {
+					return ErrFundRequired
+				}
+				fund, err := s.findFund(tx, item.FundID)
+				if err != nil {
+					return err
+				}
+				line.FundID = fund.ID
+			}
+			
+			invoice.Total += line.Amount
+			invoice.Lines = append(invoice.Lines, line)
+		}
+		
+		return tx.Create(&invoice).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (s *AcquisitionService) PayInvoice(id string) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := s.db.Preload("Lines").First(&invoice, "id = ?", id).Error; err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+	if invoice.Status != models.InvoiceOpen {
+		return nil, ErrInvoiceNotOpen
+	}
+	
+	now := time.Now()
+	invoice.Status = models.InvoicePaid
+	invoice.PaidAt = &now
+	if err := s.db.Omit("Lines").Save(&invoice).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (s *AcquisitionService) CancelInvoice(id string) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := s.db.Preload("Lines").First(&invoice, "id = ?", id).Error; err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+	if invoice.Status != models.InvoiceOpen {
+		return nil, ErrInvoiceNotOpen
+	}
+	
+	invoice.Status = models.InvoiceCancelled
+	if err := s.db.Omit("Lines").Save(&invoice).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (s *AcquisitionService) FundReport(fiscalYear int, branchID string) ([]models.FundSummary, error) {
+	query := s.db.Model(&models.Fund{})
+	if fiscalYear > 0 {
+		query = query.Where("fiscal_year = ?", fiscalYear)
+	}
+	if branchID != "" {
+		query = query.Where("branch_id = ?", branchID)
+	}
+	
+	var funds []models.Fund
+	if err := query.Order("fiscal_year DESC").Order("code ASC").Find(&funds).Error; err != nil {
+		return nil, err
+	}
+	return s.summarize(s.db, funds)
+}
+
+func (s *AcquisitionService) summarize(tx *gorm.DB, funds []models.Fund) ([]models.FundSummary, error) {
+	summaries := make([]models.FundSummary, len(funds))
+	if len(funds) == 0 {
+		return summaries, nil
+	}
+	
+	ids := make([]uuid.UUID, len(funds))
+	for i, fund := range funds {
+		ids[i] = fund.ID
+	}
+	
+	type fundAmount struct {
+		FundID uuid.UUID
+		Amount float64
+	}
+	
+	var encumbered []fundAmount
+	if err := tx.Raw(`SELECT l.fund_id, SUM(GREATEST((l.quantity - l.cancelled) * l.unit_price - COALESCE(inv.amount, 0), 0)) AS amount
+		FROM purchase_order_lines l
+		JOIN purchase_orders o ON o.id = l.order_id
+		LEFT JOIN (
+			SELECT il.order_line_id, SUM(il.amount) AS amount
+			FROM invoice_lines il
+			JOIN invoices i ON i.id = il.invoice_id
+			WHERE i.status <> ? AND il.order_line_id IS NOT NULL
+			GROUP BY il.order_line_id
+		) inv ON inv.order_line_id = l.id
+		WHERE o.status IN ? AND l.fund_id IN ?
+		GROUP BY l.fund_id`, models.InvoiceCancelled, encumberingStatuses, ids).Scan(&encumbered).Error; err != nil {
+		return nil, err
+	}
+	
+	var expended []fundAmount
+	if err := tx.Table("invoice_lines").
+		Select("invoice_lines.fund_id, SUM(invoice_lines.amount) AS amount").
+		Joins("JOIN invoices ON invoices.id = invoice_lines.invoice_id").
+		Where("invoices.status <> ? AND invoice_lines.fund_id IN ?", models.InvoiceCancelled, ids).
+		Group("invoice_lines.fund_id").
+		Scan(&expended).Error; err != nil {
+		return nil, err
+	}
+	
+	var paid []fundAmount
+	if err := tx.Table("invoice_lines").
+		Select("invoice_lines.fund_id, SUM(invoice_lines.amount) AS amount").
+		Joins("JOIN invoices ON invoices.id = invoice_lines.invoice_id").
+		Where("invoices.status = ? AND invoice_lines.fund_id IN ?", models.InvoicePaid, ids).
+		Group("invoice_lines.fund_id").
+		Scan(&paid).Error; err != nil {
+		return nil, err
+	}
+	
+	amounts := func(rows []fundAmount) map[uuid.UUID]float64 {
+		byFund := make(map[uuid.UUID]float64, len(rows))
+		for _, row := range rows {
+			byFund[row.FundID] = row.Amount
+		}
+		return byFund
+	}
+	encumberedByFund, expendedByFund, paidByFund := amounts(encumbered), amounts(expended), amounts(paid)
+	
+	for i, fund := range funds {
+		summaries[i] = models.FundSummary{
+			FundID:     fund.ID.String(),
+			Code:       fund.Code,
+			Name:       fund.Name,
+			FiscalYear: fund.FiscalYear,
+			Allocated:  fund.Allocated,
+			Encumbered: encumberedByFund[fund.ID],
+			Expended:   expendedByFund[fund.ID],
+			Paid:       paidByFund[fund.ID],
+		}
+		summaries[i].Available = fund.Allocated - summaries[i].Encumbered - summaries[i].Expended
+	}
+	return summaries, nil
+}
+
+func (s *AcquisitionService) receiveLine(tx *gorm.DB, order *models.PurchaseOrder, line *models.PurchaseOrderLine, quantity int, location *models.Location) (models.ReceivedLine, error) {
+	received := models.ReceivedLine{
+		LineID:   line.ID.String(),
+		Title:    line.Title,
+		Quantity: quantity,
+		Cost:     float64(quantity) * line.UnitPrice,
+	}
+	
+	var book models.Book
+	var err error
+	if line.BookID != nil {
+		err = tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", *line.BookID).Error
+	} else {
+		err = tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Where("isbn = ?", line.ISBN).First(&book).Error
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return received, err
+	}
+	
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		book = models.Book{
+			Title:           line.Title,
+			Author:          line.Author,
+			ISBN:            line.ISBN,
+			Publisher:       line.Publisher,
+			Language:        s.config.Search.DefaultLanguage,
+			TotalCopies:     quantity,
+			AvailableCopies: quantity,
+			ReplacementCost: line.UnitPrice,
+			Status:          models.BookStatusAvailable,
+		}
+		if callNumber, err := ParseCallNumber(line.CallNumber, line.Classification, s.config.Library.CallNumberScheme); err == nil {
+			ApplyCallNumber(&book, callNumber)
+		}
+		if err := tx.Create(&book).Error; err != nil {
+			return received, err
+		}
+		if err := AdjustHolding(tx, book.ID, order.BranchID, quantity); err != nil {
+			return received, err
+		}
+		if err := s.authorities.LinkBook(tx, &book, models.BookRequest{}); err != nil {
+			return received, err
+		}
+		received.Created = true
+	} else {
+		if book.DeletedAt.Valid {
+			if err := tx.Unscoped().Model(&book).Update("deleted_at", nil).Error; err != nil {
+				return received, err
+			}
+			book.DeletedAt = gorm.DeletedAt{}
+		}
+		if err := AdjustHolding(tx, book.ID, order.BranchID, quantity); err != nil {
+			return received, err
+		}
+		for i := 0; i < quantity; i++ {
+			book.RestoreCopy()
+			hold, _, err := s.circulation.trapNextHold(tx, &book, order.BranchID, order.BranchID)
+			if err != nil {
+				return received, err
+			}
+			if hold != nil {
+				received.HoldsFilled++
+			}
+		}
+	}
+	
+	shelf := location
+	if shelf == nil {
+		if shelf, err = s.locations.ShelfFor(tx, order.BranchID, book.CallNumberSort); err != nil {
+			return received, err
+		}
+	}
+	if shelf != nil {
+		if err := s.locations.Place(tx, book.ID, order.BranchID, shelf); err != nil {
+			return received, err
+		}
+		received.Location = shelf.Path
+	}
+	
+	if received.Suggestions, err = s.suggestions.Fulfill(tx, &book, order.BranchID); err != nil {
+		return received, err
+	}
+	
+	line.BookID = &book.ID
+	line.Received += quantity
+	if err := tx.Model(line).Updates(map[string]interface{}{
+		"book_id":  line.BookID,
+		"received": line.Received,
+	}).Error; err != nil {
+		return received, err
+	}
+	
+	received.BookID = book.ID.String()
+	received.Outstanding = line.Outstanding()
+	return received, nil
+}
+
+func (s *AcquisitionService) applyOrderRequest(tx *gorm.DB, order *models.PurchaseOrder, req models.OrderRequest) error {
+	vendor, err := s.findVendor(tx, req.VendorID)
+	if err != nil {
+		return err
+	}
+	if !vendor.IsActive {
+		return ErrVendorInactive
+	}
+	
+	branch, err := ResolveBranch(tx, req.BranchID)
+	if err != nil {
+		return err
+	}
+	
+	order.VendorID = vendor.ID
+	order.BranchID = branch.ID
+	order.Notes = req.Notes
+	order.Lines = make([]models.PurchaseOrderLine, 0, len(req.Lines))
+	
+	for _, item := range req.Lines {
+		fundID := item.FundID
+		if fundID == "" {
+			fundID = req.FundID
+		}
+		if fundID == "" {
+			return ErrFundRequired
+		}
+		fund, err := s.activeFund(tx, fundID)
+		if err != nil {
+			return err
+		}
+		
+		line := models.PurchaseOrderLine{
+			OrderID:   order.ID,
+			FundID:    fund.ID,
+			Title:     strings.TrimSpace(item.Title),
+			Author:    strings.TrimSpace(item.Author),
+			Publisher: strings.TrimSpace(item.Publisher),
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+		}
+		
+		if item.BookID != "" {
+			var book models.Book
+			if err := tx.First(&book, "id = ?", item.BookID).Error; err != nil {
+				return ErrBookNotFound
+			}
+			line.BookID = &book.ID
+			line.ISBN = book.ISBN
+			line.Title = book.Title
+			line.Author = book.Author
+			line.Publisher = book.Publisher
+			line.CallNumber = book.CallNumber
+			line.Classification = book.Classification
+		} else {
+			if line.Title == "" || strings.TrimSpace(item.ISBN) == "" {
+				return ErrOrderLineTitle
+			}
+			normalized, err := isbn.Normalize(item.ISBN)
+			if err != nil {
+				return fmt.Errorf("%w: isbn %q: %v", ErrOrderLineInvalid, item.ISBN, err)
+			}
+			line.ISBN = normalized
+			
+			var existing models.Book
+			if err := tx.Where("isbn = ?", normalized).First(&existing).Error; err == nil {
+				line.BookID = &existing.ID
+			}
+			
+			callNumber, err := ParseCallNumber(item.CallNumber, item.Classification, s.config.Library.CallNumberScheme)
+			if err != nil {
+				return fmt.Errorf("%w: call number %q: %v", ErrOrderLineInvalid, item.CallNumber, err)
+			}
+			line.CallNumber = callNumber.Display
+			line.Classification = string(callNumber.Scheme)
+		}
+		
+		order.Lines = append(order.Lines, line)
+	}
+	return nil
+}
+
+func (s *AcquisitionService) findOrder(tx *gorm.DB, id string) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	if err := tx.Preload("Vendor").Preload("Lines").First(&order, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (s *AcquisitionService) lockOrder(tx *gorm.DB, id string) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	if err := tx.Where("order_id = ?", order.ID).Order("created_at ASC").Find(&order.Lines).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (s *AcquisitionService) findVendor(tx *gorm.DB, id string) (*models.Vendor, error) {
+	var vendor models.Vendor
+	if err := tx.First(&vendor, "id = ?", id).Error; err != nil {
+		return nil, ErrVendorNotFound
+	}
+	return &vendor, nil
+}
+
+func (s *AcquisitionService) findFund(tx *gorm.DB, id string) (*models.Fund, error) {
+	var fund models.Fund
+	if err := tx.First(&fund, "id = ?", id).Error; err != nil {
+		return nil, ErrFundNotFound
+	}
+	return &fund, nil
+}
+
+func (s *AcquisitionService) activeFund(tx *gorm.DB, id string) (*models.Fund, error) {
+	fund, err := s.findFund(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !fund.IsActive {
+		return nil, ErrFundInactive
+	}
+	return fund, nil
+}
+
+func nextOrderNumber(tx *gorm.DB) string {
+	year := time.Now().Year()
+	
+	var count int64
+	tx.Model(&models.PurchaseOrder{}).Where("number LIKE ?", fmt.Sprintf("PO-%d-%%", year)).Count(&count)
+	return fmt.Sprintf("PO-%d-%05d", year, count+1)
+}
@@ -0,0 +1,930 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidAuthorityType = errors.New("invalid authority type, expected author, publisher, series or subject")
+	ErrAuthorityNotFound    = errors.New("authority record not found")
+	ErrAuthorityExists      = errors.New("another authority record already uses this name, merge them instead")
+	ErrAuthorityMergeSelf   = errors.New("cannot merge an authority record into itself")
+	ErrInvalidContributor   = errors.New("invalid contributor role, expected author, editor, translator or illustrator")
+	ErrEmptyAuthorityName   = errors.New("authority name required")
+)
+
+var (
+	authorSeparator = regexp.MustCompile(`(?i)\s*;\s*|\s+&\s+|\s+and\s+`)
+	leadingArticle  = regexp.MustCompile(`(?i)^(the|a|an)\s+`)
+)
+
+var errDryRun = errors.New("dry run")
+
+type authorityKind struct {
+	table      string
+	linkTable  string
+	linkColumn string
+}
+
+var authorityKinds = map[models.AuthorityType]authorityKind{
+	models.AuthorityAuthor:    {table: "authors", linkTable: "book_authors", linkColumn: "author_id"},
+	models.AuthorityPublisher: {table: "publishers", linkTable: "book_publishers", linkColumn: "publisher_id"},
+	models.AuthoritySeries:    {table: "series", linkTable: "book_series", linkColumn: "series_id"},
+	models.AuthoritySubject:   {table: "subjects", linkTable: "book_subjects", linkColumn: "subject_id"},
+}
+
+type authorityRecord struct {
+	ID       uuid.UUID
+	Name     string
+	NameKey  string
+	AltNames pq.StringArray
+	AltKeys  pq.StringArray
+}
+
+type bookLink struct {
+	BookID   uuid.UUID
+	ID       uuid.UUID
+	Name     string
+	Role     string
+	Volume   float64
+	Position int
+}
+
+type AuthorityService struct {
+	db    *gorm.DB
+	redis *redis.Client
+}
+
+func NewAuthorityService(db *gorm.DB, redisClient *redis.Client) *AuthorityService {
+	return &AuthorityService{db: db, redis: redisClient}
+}
+
+func (s *AuthorityService) LinkBook(tx *gorm.DB, book *models.Book, req models.BookRequest) error {
+	contributors := req.Contributors
+	if len(contributors) == 0 {
+		for _, name := range SplitAuthorNames(book.Author) {
+			contributors = append(contributors, models.ContributorRef{
+				AuthorityRef: models.AuthorityRef{Name: name},
+				Role:         models.ContributorAuthor,
+			})
+		}
+	}
+	if len(req.Contributors) > 0 || !s.linkedAs(tx, models.AuthorityAuthor, book.ID, book.Author) {
+		if err := s.linkContributors(tx, book.ID, contributors); err != nil {
+			return err
+		}
+	}
+	
+	publishers := req.Publishers
+	if len(publishers) == 0 && strings.TrimSpace(book.Publisher) != "" {
+		publishers = []models.AuthorityRef{{Name: book.Publisher}}
+	}
+	if len(req.Publishers) > 0 || !s.linkedAs(tx, models.AuthorityPublisher, book.ID, book.Publisher) {
+		if err := s.linkRefs(tx, models.AuthorityPublisher, book.ID, publishers); err != nil {
+			return err
+		}
+	}
+	
+	subjects := req.Subjects
+	if len(subjects) == 0 && strings.TrimSpace(book.Category) != "" {
+		subjects = []models.AuthorityRef{{Name: book.Category}}
+	}
+	if len(req.Subjects) > 0 || !s.linkedAs(tx, models.AuthoritySubject, book.ID, book.Category) {
+		if err := s.linkRefs(tx, models.AuthoritySubject, book.ID, subjects); err != nil {
+			return err
+		}
+	}
+	
+	if req.Series != nil {
+		if err := s.linkSeries(tx, book.ID, req.Series); err != nil {
+			return err
+		}
+	}
+	
+	return s.syncBook(tx, book)
+}
+
+func (s *AuthorityService) linkedAs(tx *gorm.DB, typ models.AuthorityType, bookID uuid.UUID, value string) bool {
+	links, err := s.bookLinks(tx, typ, bookID)
+	if err != nil {
+		return false
+	}
+	return bookString(typ, links) == strings.TrimSpace(value)
+}
+
+func (s *AuthorityService) linkContributors(tx *gorm.DB, bookID uuid.UUID, contributors []models.ContributorRef) error {
+	if err := tx.Where("book_id = ?", bookID).Delete(&models.BookAuthor{}).Error; err != nil {
+		return err
+	}
+	
+	seen := make(map[string]bool)
+	for i, contributor := range contributors {
+		role := contributor.Role
+		if role == "" {
+			role = models.ContributorAuthor
+		}
+		if !role.IsValid() {
+			return ErrInvalidContributor
+		}
+		
+		record, err := s.resolve(tx, models.AuthorityAuthor, contributor.AuthorityRef)
+		if errors.Is(err, ErrEmptyAuthorityName) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		
+		key := record.ID.String() + "/" + string(role)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		
+		link := models.BookAuthor{BookID: bookID, AuthorID: record.ID, Role: role, Position: i}
+		if err := tx.Create(&link).Error; err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (s *AuthorityService) linkRefs(tx *gorm.DB, typ models.AuthorityType, bookID uuid.UUID, refs []models.AuthorityRef) error {
+	kind := authorityKinds[typ]
+	if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE book_id = ?", kind.linkTable), bookID).Error; err != nil {
+		return err
+	}
+	
+	seen := make(map[uuid.UUID]bool)
+	for i, ref := range refs {
+		record, err := s.resolve(tx, typ, ref)
+		if errors.Is(err, ErrEmptyAuthorityName) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		
+		if seen[record.ID] {
+			continue
+		}
+		seen[record.ID] = true
+		
+		statement := fmt.Sprintf("INSERT INTO %s (book_id, %s, position) VALUES (?, ?, ?)", kind.linkTable, kind.linkColumn)
+		if err := tx.Exec(statement, bookID, record.ID, i).Error; err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (s *AuthorityService) linkSeries(tx *gorm.DB, bookID uuid.UUID, series []models.SeriesRef) error {
+	if err := tx.Where("book_id = ?", bookID).Delete(&models.BookSeries{}).Error; err != nil {
+		return err
+	}
+	
+	seen := make(map[uuid.UUID]bool)
+	for i, ref := range series {
+		record, err := s.resolve(tx, models.AuthoritySeries, ref.AuthorityRef)
+		if errors.Is(err, ErrEmptyAuthorityName) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		
+		if seen[record.ID] {
+			continue
+		}
+		seen[record.ID] = true
+		
+		link := models.BookSeries{BookID: bookID, SeriesID: record.ID, Volume: ref.Volume, Position: i}
+		if err := tx.Create(&link).Error; err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (s *AuthorityService) resolve(tx *gorm.DB, typ models.AuthorityType, ref models.AuthorityRef) (*authorityRecord, error) {
+	kind := authorityKinds[typ]
+	
+	if ref.ID != "" {
+		id, err := uuid.Parse(ref.ID)
+		if err != nil {
+			return nil, ErrAuthorityNotFound
+		}
+		return s.record(tx, typ, id)
+	}
+	
+	name := strings.Join(strings.Fields(ref.Name), " ")
+	key := AuthorityKey(typ, name)
+	if key == "" {
+		return nil, ErrEmptyAuthorityName
+	}
+	
+	record, err := s.findByKey(tx, typ, key)
+	if err != nil {
+		return nil, err
+	}
+	
+	if record == nil {
+		now := time.Now()
+		statement := fmt.Sprintf(`INSERT INTO %s (id, name, sort_name, name_key, alt_names, alt_keys, created_at, updated_at)
+			VALUES (?, ?, ?, ?, '{}', '{}', ?, ?) ON CONFLICT (name_key) DO NOTHING`, kind.table)
+		if err := tx.Exec(statement, uuid.New(), name, SortName(typ, name), key, now, now).Error; err != nil {
+			return nil, err
+		}
+		
+		record, err = s.findByKey(tx, typ, key)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			return nil, ErrAuthorityNotFound
+		}
+		return record, nil
+	}
+	
+	if name != record.Name && !containsString(record.AltNames, name) {
+		record.AltNames = append(record.AltNames, name)
+		if key != record.NameKey && !containsString(record.AltKeys, key) {
+			record.AltKeys = append(record.AltKeys, key)
+		}
+		if err := s.saveAltNames(tx, typ, record); err != nil {
+			return nil, err
+		}
+	}
+	
+	return record, nil
+}
+
+func (s *AuthorityService) record(tx *gorm.DB, typ models.AuthorityType, id uuid.UUID) (*authorityRecord, error) {
+	var records []authorityRecord
+	statement := fmt.Sprintf("SELECT id, name, name_key, alt_names, alt_keys FROM %s WHERE id = ?", authorityKinds[typ].table)
+	if err := tx.Raw(statement, id).Scan(&records).Error; err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrAuthorityNotFound
+	}
+	return &records[0], nil
+}
+
+func (s *AuthorityService) findByKey(tx *gorm.DB, typ models.AuthorityType, key string) (*authorityRecord, error) {
+	var records []authorityRecord
+	statement := fmt.Sprintf(`SELECT id, name, name_key, alt_names, alt_keys FROM %s
+		WHERE name_key = ? OR ? = ANY(alt_keys)
+		ORDER BY name_key = ? DESC, created_at ASC
+		LIMIT 1`, authorityKinds[typ].table)
+	if err := tx.Raw(statement, key, key, key).Scan(&records).Error; err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return &records[0], nil
+}
+
+func (s *AuthorityService) saveAltNames(tx *gorm.DB, typ models.AuthorityType, record *authorityRecord) error {
+	if record.AltNames == nil {
+		record.AltNames = pq.StringArray{}
+	}
+	if record.AltKeys == nil {
+		record.AltKeys = pq.StringArray{}
+	}
+	
+	statement := fmt.Sprintf("UPDATE %s SET alt_names = ?, alt_keys = ?, updated_at = ? WHERE id = ?", authorityKinds[typ].table)
+	return tx.Exec(statement, record.AltNames, record.AltKeys, time.Now(), record.ID).Error
+}
+
+func (s *AuthorityService) bookLinks(tx *gorm.DB, typ models.AuthorityType, bookID uuid.UUID) ([]bookLink, error) {
+	return s.booksLinks(tx, typ, []uuid.UUID{bookID})
+}
+
+func (s *AuthorityService) booksLinks(tx *gorm.DB, typ models.AuthorityType, bookIDs []uuid.UUID) ([]bookLink, error) {
+	kind := authorityKinds[typ]
+	
+	columns := "l.book_id, t.id, t.name, l.position"
+	switch typ {
+	case models.AuthorityAuthor:
+		columns += ", l.role"
+	case models.AuthoritySeries:
+		columns += ", l.volume"
+	}
+	
+	var links []bookLink
+	statement := fmt.Sprintf(`SELECT %s FROM %s l JOIN %s t ON t.id = l.%s
+		WHERE l.book_id IN ?
+		ORDER BY l.position ASC, t.sort_name ASC`, columns, kind.linkTable, kind.table, kind.linkColumn)
+	if err := tx.Raw(statement, bookIDs).Scan(&links).Error; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (s *AuthorityService) BookAuthorities(bookID uuid.UUID) (*models.BookRequest, error) {
+	results, err := s.BooksAuthorities([]uuid.UUID{bookID})
+	if err != nil {
+		return nil, err
+	}
+	return results[bookID], nil
+}
+
+// BooksAuthorities loads the authority links of a page of books with one
+// query per authority type, keyed by book ID.
+func (s *AuthorityService) BooksAuthorities(bookIDs []uuid.UUID) (map[uuid.UUID]*models.BookRequest, error) {
+	results := make(map[uuid.UUID]*models.BookRequest, len(bookIDs))
+	for _, id := range bookIDs {
+		results[id] = &models.BookRequest{}
+	}
+	if len(bookIDs) == 0 {
+		return results, nil
+	}
+	
+	for _, typ := range []models.AuthorityType{models.AuthorityAuthor, models.AuthorityPublisher, models.AuthoritySeries, models.AuthoritySubject} {
+		links, err := s.booksLinks(s.db, typ, bookIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range links {
+			result, ok := results[link.BookID]
+			if !ok {
+				continue
+			}
+			ref := models.AuthorityRef{ID: link.ID.String(), Name: link.Name}
+			switch typ {
+			case models.AuthorityAuthor:
+				result.Contributors = append(result.Contributors, models.ContributorRef{
+					AuthorityRef: ref,
+					Role:         models.ContributorRole(link.Role),
+				})
+			case models.AuthorityPublisher:
+				result.Publishers = append(result.Publishers, ref)
+			case models.AuthoritySeries:
+				result.Series = append(result.Series, models.SeriesRef{AuthorityRef: ref, Volume: link.Volume})
+			case models.AuthoritySubject:
+				result.Subjects = append(result.Subjects, ref)
+			}
+		}
+	}
+	
+	return results, nil
+}
+
+func (s *AuthorityService) syncBook(tx *gorm.DB, book *models.Book) error {
+	updates := make(map[string]interface{})
+	
+	fields := []struct {
+		typ    models.AuthorityType
+		column string
+		value  *string
+	}{
+		{models.AuthorityAuthor, "author", &book.Author},
+		{models.AuthorityPublisher, "publisher", &book.Publisher},
+		{models.AuthoritySubject, "category", &book.Category},
+	}
+	
+	for _, field := range fields {
+		links, err := s.bookLinks(tx, field.typ, book.ID)
+		if err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			continue
+		}
+		
+		value := bookString(field.typ, links)
+		if value != *field.value {
+			updates[field.column] = value
+			*field.value = value
+		}
+	}
+	
+	if len(updates) == 0 {
+		return nil
+	}
+	return tx.Model(&models.Book{}).Where("id = ?", book.ID).Updates(updates).Error
+}
+
+func (s *AuthorityService) syncBooks(tx *gorm.DB, typ models.AuthorityType, ids []uuid.UUID) error {
+	kind := authorityKinds[typ]
+	
+	var books []models.Book
+	statement := fmt.Sprintf("SELECT * FROM books WHERE id IN (SELECT book_id FROM %s WHERE %s IN ?)", kind.linkTable, kind.linkColumn)
+	if err := tx.Raw(statement, ids).Scan(&books).Error; err != nil {
+		return err
+	}
+	
+	for i := range books {
+		if err := s.syncBook(tx, &books[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bookString(typ models.AuthorityType, links []bookLink) string {
+	if len(links) == 0 {
+		return ""
+	}
+	
+	if typ != models.AuthorityAuthor {
+		return links[0].Name
+	}
+	
+	names := make([]string, 0, len(links))
+	for _, link := range links {
+		if link.Role == string(models.ContributorAuthor) && !containsString(names, link.Name) {
+			names = append(names, link.Name)
+		}
+	}
+	if len(names) == 0 {
+		for _, link := range links {
+			if !containsString(names, link.Name) {
+				names = append(names, link.Name)
+			}
+		}
+	}
+	return strings.Join(names, "; ")
+}
+
+func (s *AuthorityService) Get(typ models.AuthorityType, id string) (*models.AuthorityResponse, []models.AuthorityBook, error) {
+	kind, ok := authorityKinds[typ]
+	if !ok {
+		return nil, nil, ErrInvalidAuthorityType
+	}
+	
+	authorityID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nil, ErrAuthorityNotFound
+	}
+	
+	var records []models.AuthorityResponse
+	if err := s.BrowseQuery(typ).Where("t.id = ?", authorityID).Scan(&records).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, ErrAuthorityNotFound
+	}
+	record := records[0]
+	record.Type = typ
+	
+	columns := "b.id, b.title, b.isbn, b.publish_date, b.cover_image"
+	order := "b.title ASC"
+	switch typ {
+	case models.AuthorityAuthor:
+		columns += ", l.role"
+	case models.AuthoritySeries:
+		columns += ", l.volume"
+		order = "l.volume ASC, b.publish_date ASC"
+	}
+	
+	books := []models.AuthorityBook{}
+	statement := fmt.Sprintf(`SELECT %s FROM %s l JOIN books b ON b.id = l.book_id AND b.deleted_at IS NULL
+		WHERE l.%s = ?
+		ORDER BY %s`, columns, kind.linkTable, kind.linkColumn, order)
+	if err := s.db.Raw(statement, authorityID).Scan(&books).Error; err != nil {
+		return nil, nil, err
+	}
+	
+	return &record, books, nil
+}
+
+func (s *AuthorityService) BrowseQuery(typ models.AuthorityType) *gorm.DB {
+	kind := authorityKinds[typ]
+	return s.db.Table(kind.table + " AS t").
+		Select(fmt.Sprintf(`t.*, (SELECT COUNT(*) FROM %s l JOIN books b ON b.id = l.book_id AND b.deleted_at IS NULL
+			WHERE l.%s = t.id) AS book_count`, kind.linkTable, kind.linkColumn))
+}
+
+func (s *AuthorityService) Update(req models.AuthorityRequest) (*models.AuthorityResponse, error) {
+	kind, ok := authorityKinds[req.Type]
+	if !ok {
+		return nil, ErrInvalidAuthorityType
+	}
+	
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, ErrAuthorityNotFound
+	}
+	
+	name := strings.Join(strings.Fields(req.Name), " ")
+	key := AuthorityKey(req.Type, name)
+	if key == "" {
+		return nil, ErrEmptyAuthorityName
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		record, err := s.record(tx, req.Type, id)
+		if err != nil {
+			return err
+		}
+		
+		var conflicts int64
+		statement := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE id <> ? AND (name_key = ? OR ? = ANY(alt_keys))", kind.table)
+		if err := tx.Raw(statement, id, key, key).Scan(&conflicts).Error; err != nil {
+			return err
+		}
+		if conflicts > 0 {
+			return ErrAuthorityExists
+		}
+		
+		altNames := pq.StringArray{}
+		altKeys := pq.StringArray{}
+		candidates := req.AltNames
+		if record.Name != name {
+			candidates = append(candidates, record.Name)
+		}
+		for _, alt := range candidates {
+			alt = strings.Join(strings.Fields(alt), " ")
+			altKey := AuthorityKey(req.Type, alt)
+			if altKey == "" || alt == name || containsString(altNames, alt) {
+				continue
+			}
+			altNames = append(altNames, alt)
+			if altKey != key && !containsString(altKeys, altKey) {
+				altKeys = append(altKeys, altKey)
+			}
+		}
+		
+		sortName := strings.TrimSpace(req.SortName)
+		if sortName == "" {
+			sortName = SortName(req.Type, name)
+		}
+		
+		updates := map[string]interface{}{
+			"name":       name,
+			"sort_name":  sortName,
+			"name_key":   key,
+			"alt_names":  altNames,
+			"alt_keys":   altKeys,
+			"identifier": req.Identifier,
+			"notes":      req.Notes,
+			"updated_at": time.Now(),
+		}
+		switch req.Type {
+		case models.AuthorityAuthor:
+			updates["dates"] = req.Dates
+		case models.AuthorityPublisher:
+			updates["place"] = req.Place
+		case models.AuthoritySubject:
+			if req.Scheme != "" {
+				updates["scheme"] = req.Scheme
+			}
+		}
+		
+		if err := tx.Table(kind.table).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		
+		return s.syncBooks(tx, req.Type, []uuid.UUID{id})
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateAutocomplete(context.Background(), s.redis)
+	
+	record, _, err := s.Get(req.Type, req.ID)
+	return record, err
+}
+
+func (s *AuthorityService) Merge(typ models.AuthorityType, targetID string, sourceIDs []string) (*models.AuthorityResponse, error) {
+	kind, ok := authorityKinds[typ]
+	if !ok {
+		return nil, ErrInvalidAuthorityType
+	}
+	
+	target, err := uuid.Parse(targetID)
+	if err != nil {
+		return nil, ErrAuthorityNotFound
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		record, err := s.record(tx, typ, target)
+		if err != nil {
+			return err
+		}
+		
+		for _, sourceID := range sourceIDs {
+			id, err := uuid.Parse(sourceID)
+			if err != nil {
+				return ErrAuthorityNotFound
+			}
+			if id == target {
+				return ErrAuthorityMergeSelf
+			}
+			
+			source, err := s.record(tx, typ, id)
+			if err != nil {
+				return err
+			}
+			
+			for _, name := range append([]string{source.Name}, source.AltNames...) {
+				if name != record.Name && !containsString(record.AltNames, name) {
+					record.AltNames = append(record.AltNames, name)
+				}
+			}
+			for _, key := range append([]string{source.NameKey}, source.AltKeys...) {
+				if key != record.NameKey && !containsString(record.AltKeys, key) {
+					record.AltKeys = append(record.AltKeys, key)
+				}
+			}
+			
+			move := fmt.Sprintf(`UPDATE %[1]s SET %[2]s = ? WHERE %[2]s = ? AND NOT EXISTS (
+				SELECT 1 FROM %[1]s existing WHERE existing.book_id = %[1]s.book_id AND existing.%[2]s = ?`, kind.linkTable, kind.linkColumn)
+			if typ == models.AuthorityAuthor {
+				move += " AND existing.role = book_authors.role"
+			}
+			move += ")"
+			if err := tx.Exec(move, target, id, target).Error; err != nil {
+				return err
+			}
+			
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", kind.linkTable, kind.linkColumn), id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", kind.table), id).Error; err != nil {
+				return err
+			}
+		}
+		
+		if err := s.saveAltNames(tx, typ, record); err != nil {
+			return err
+		}
+		
+		return s.syncBooks(tx, typ, []uuid.UUID{target})
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateAutocomplete(context.Background(), s.redis)
+	
+	record, _, err := s.Get(typ, targetID)
+	return record, err
+}
+
+func (s *AuthorityService) DuplicateSuggestions(typ models.AuthorityType, threshold float64, limit int) ([]models.DuplicateSuggestion, error) {
+	kind, ok := authorityKinds[typ]
+	if !ok {
+		return nil, ErrInvalidAuthorityType
+	}
+	
+	var pairs []struct {
+		LeftID     string
+		LeftName   string
+		RightID    string
+		RightName  string
+		Similarity float64
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)", fmt.Sprintf("%.2f", threshold)).Error; err != nil {
+			return err
+		}
+		
+		statement := fmt.Sprintf(`SELECT a.id AS left_id, a.name AS left_name, b.id AS right_id, b.name AS right_name,
+				similarity(a.name_key, b.name_key) AS similarity
+			FROM %[1]s a JOIN %[1]s b ON a.id < b.id AND a.name_key %% b.name_key
+			ORDER BY similarity DESC, a.sort_name ASC
+			LIMIT ?`, kind.table)
+		return tx.Raw(statement, limit).Scan(&pairs).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	suggestions := []models.DuplicateSuggestion{}
+	seen := make(map[string]bool)
+	for _, pair := range pairs {
+		seen[pair.LeftID+"/"+pair.RightID] = true
+		suggestions = append(suggestions, models.DuplicateSuggestion{
+			Type:       typ,
+			Reason:     "similar_name",
+			Similarity: pair.Similarity,
+			Records: []models.AuthorityRef{
+				{ID: pair.LeftID, Name: pair.LeftName},
+				{ID: pair.RightID, Name: pair.RightName},
+			},
+		})
+	}
+	
+	if typ != models.AuthorityAuthor || len(suggestions) >= limit {
+		return suggestions, nil
+	}
+	
+	var authors []struct {
+		ID   string
+		Name string
+	}
+	if err := s.db.Table("authors").Select("id", "name").Order("id ASC").Scan(&authors).Error; err != nil {
+		return nil, err
+	}
+	
+	bySurname := make(map[string][]int)
+	initials := make([]string, len(authors))
+	for i, author := range authors {
+		surname, given := splitPersonalName(author.Name)
+		if surname == "" || given == "" {
+			continue
+		}
+		initials[i] = given
+		bySurname[surname] = append(bySurname[surname], i)
+	}
+	
+	surnames := make([]string, 0, len(bySurname))
+	for surname := range bySurname {
+		surnames = append(surnames, surname)
+	}
+	sort.Strings(surnames)
+	
+	for _, surname := range surnames {
+		group := bySurname[surname]
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				left, right := authors[group[i]], authors[group[j]]
+				if initials[group[i]] != initials[group[j]] || seen[left.ID+"/"+right.ID] {
+					continue
+				}
+				
+				suggestions = append(suggestions, models.DuplicateSuggestion{
+					Type:   typ,
+					Reason: "matching_initials",
+					Records: []models.AuthorityRef{
+						{ID: left.ID, Name: left.Name},
+						{ID: right.ID, Name: right.Name},
+					},
+				})
+				if len(suggestions) >= limit {
+					return suggestions, nil
+				}
+			}
+		}
+	}
+	
+	return suggestions, nil
+}
+
+func (s *AuthorityService) Migrate(apply bool) (*models.AuthorityMigrationReport, error) {
+	report := &models.AuthorityMigrationReport{
+		DryRun:   !apply,
+		Created:  make(map[models.AuthorityType]int64),
+		Variants: []models.AuthorityVariant{},
+	}
+	
+	variants := make(map[models.AuthorityType]map[string][]string)
+	order := make(map[models.AuthorityType][]string)
+	note := func(typ models.AuthorityType, name string) {
+		name = strings.Join(strings.Fields(name), " ")
+		key := AuthorityKey(typ, name)
+		if key == "" {
+			return
+		}
+		if variants[typ] == nil {
+			variants[typ] = make(map[string][]string)
+		}
+		if _, ok := variants[typ][key]; !ok {
+			order[typ] = append(order[typ], key)
+		}
+		if !containsString(variants[typ][key], name) {
+			variants[typ][key] = append(variants[typ][key], name)
+		}
+	}
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		before := make(map[models.AuthorityType]int64)
+		for typ, kind := range authorityKinds {
+			var count int64
+			tx.Table(kind.table).Count(&count)
+			before[typ] = count
+		}
+		
+		var books []models.Book
+		err := tx.Where(`(author <> '' AND NOT EXISTS (SELECT 1 FROM book_authors WHERE book_authors.book_id = books.id))
+			OR (publisher <> '' AND NOT EXISTS (SELECT 1 FROM book_publishers WHERE book_publishers.book_id = books.id))
+			OR (category <> '' AND NOT EXISTS (SELECT 1 FROM book_subjects WHERE book_subjects.book_id = books.id))`).
+			Order("created_at ASC").
+			Find(&books).Error
+		if err != nil {
+			return err
+		}
+		
+		for i := range books {
+			book := &books[i]
+			for _, name := range SplitAuthorNames(book.Author) {
+				note(models.AuthorityAuthor, name)
+			}
+			note(models.AuthorityPublisher, book.Publisher)
+			note(models.AuthoritySubject, book.Category)
+			
+			if err := s.LinkBook(tx, book, models.BookRequest{}); err != nil {
+				return err
+			}
+			report.BooksLinked++
+		}
+		
+		for typ, kind := range authorityKinds {
+			var count int64
+			tx.Table(kind.table).Count(&count)
+			report.Created[typ] = count - before[typ]
+		}
+		
+		if !apply {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	
+	for _, typ := range []models.AuthorityType{models.AuthorityAuthor, models.AuthorityPublisher, models.AuthoritySubject} {
+		for _, key := range order[typ] {
+			names := variants[typ][key]
+			if len(names) > 1 {
+				report.Variants = append(report.Variants, models.AuthorityVariant{
+					Type:     typ,
+					Name:     names[0],
+					Variants: names[1:],
+				})
+			}
+		}
+	}
+	
+	return report, nil
+}
+
+func SplitAuthorNames(value string) []string {
+	var names []string
+	for _, name := range authorSeparator.Split(value, -1) {
+		name = strings.Join(strings.Fields(name), " ")
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func AuthorityKey(typ models.AuthorityType, name string) string {
+	tokens := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if typ == models.AuthorityAuthor {
+		sort.Strings(tokens)
+	}
+	return strings.Join(tokens, " ")
+}
+
+func SortName(typ models.AuthorityType, name string) string {
+	if typ != models.AuthorityAuthor {
+		return strings.ToLower(leadingArticle.ReplaceAllString(name, ""))
+	}
+	
+	if strings.Contains(name, ",") {
+		return strings.ToLower(name)
+	}
+	
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(fields[len(fields)-1] + ", " + strings.Join(fields[:len(fields)-1], " "))
+}
+
+func splitPersonalName(name string) (string, string) {
+	var surname, given string
+	if index := strings.Index(name, ","); index >= 0 {
+		surname, given = name[:index], name[index+1:]
+	} else {
+		fields := strings.Fields(name)
+		if len(fields) < 2 {
+			return "", ""
+		}
+		surname, given = fields[len(fields)-1], strings.Join(fields[:len(fields)-1], " ")
+	}
+	
+	initials := make([]rune, 0)
+	for _, token := range strings.FieldsFunc(strings.ToLower(given), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		initials = append(initials, []rune(token)[0])
+	}
+	
+	return AuthorityKey(models.AuthorityPublisher, surname), string(initials)
+}
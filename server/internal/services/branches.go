@@ -0,0 +1,482 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrBranchNotFound       = errors.New("branch not found")
+	ErrBranchInactive       = errors.New("branch is inactive")
+	ErrBranchExists         = errors.New("branch code already exists")
+	ErrNoCopyAtBranch       = errors.New("no copy of this book is on the shelf at this branch")
+	ErrTransferNotFound     = errors.New("transfer not found")
+	ErrTransferSameBranch   = errors.New("transfer source and destination are the same branch")
+	ErrInvalidTransferState = errors.New("transfer cannot change from its current status")
+	ErrHoldingInUse         = errors.New("total copies cannot be lower than the copies in use at this branch")
+)
+
+func EnsureBranches(db *gorm.DB, cfg *config.Config) (*models.Branch, int64, error) {
+	var branch models.Branch
+	var holdings int64
+	
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("is_default = ?", true).First(&branch).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = tx.Where("code = ?", cfg.Library.DefaultBranchCode).
+				Attrs(models.Branch{Name: cfg.Library.DefaultBranchName, IsActive: true}).
+				FirstOrCreate(&branch).Error
+			if err != nil {
+				return err
+			}
+			branch.IsDefault = true
+			err = tx.Save(&branch).Error
+		}
+		if err != nil {
+			return err
+		}
+		
+		now := time.Now()
+		result := tx.Exec(`INSERT INTO book_holdings (id, book_id, branch_id, total_copies, created_at, updated_at)
+			SELECT gen_random_uuid(), b.id, ?, b.total_copies, ?, ?
+			FROM books b
+			WHERE b.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM book_holdings h WHERE h.book_id = b.id)`, branch.ID, now, now)
+		if result.Error != nil {
+			return result.Error
+		}
+		holdings = result.RowsAffected
+		
+		if err := tx.Model(&models.Loan{}).Where("owning_branch_id IS NULL").
+			Updates(map[string]interface{}{"branch_id": branch.ID, "owning_branch_id": branch.ID}).Error; err != nil {
+			return err
+		}
+		
+		if err := tx.Model(&models.Reservation{}).
+			Where("status = ? AND item_branch_id IS NULL", models.ReservationStatusReadyForPickup).
+			Updates(map[string]interface{}{"item_branch_id": branch.ID, "pickup_branch_id": branch.ID}).Error; err != nil {
+			return err
+		}
+		
+		return tx.Model(&models.Repair{}).Where("branch_id IS NULL").Update("branch_id", branch.ID).Error
+	})
+	
+	if err != nil {
+		return nil, 0, err
+	}
+	return &branch, holdings, nil
+}
+
+func DefaultBranch(tx *gorm.DB) (*models.Branch, error) {
+	var branch models.Branch
+	if err := tx.Where("is_default = ?", true).Order("created_at ASC").First(&branch).Error; err != nil {
+		return nil, ErrBranchNotFound
+	}
+	return &branch, nil
+}
+
+func ResolveBranch(tx *gorm.DB, id string) (*models.Branch, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return DefaultBranch(tx)
+	}
+	
+	branchID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrBranchNotFound
+	}
+	
+	var branch models.Branch
+	if err := tx.First(&branch, "id = ?", branchID).Error; err != nil {
+		return nil, ErrBranchNotFound
+	}
+	
+	if !branch.IsActive {
+		return nil, ErrBranchInactive
+	}
+	return &branch, nil
+}
+
+func AdjustHolding(tx *gorm.DB, bookID, branchID uuid.UUID, delta int) error {
+	now := time.Now()
+	return tx.Exec(`INSERT INTO book_holdings (id, book_id, branch_id, total_copies, created_at, updated_at)
+		VALUES (?, ?, ?, GREATEST(?, 0), ?, ?)
+		ON CONFLICT (book_id, branch_id) DO UPDATE
+		SET total_copies = GREATEST(book_holdings.total_copies + ?, 0), updated_at = ?`,
+		uuid.New(), bookID, branchID, delta, now, now, delta, now).Error
+}
+
+func SyncHoldings(tx *gorm.DB, book *models.Book, branchID uuid.UUID) error {
+	var holdings []models.BookHolding
+	if err := tx.Where("book_id = ?", book.ID).Find(&holdings).Error; err != nil {
+		return err
+	}
+	
+	if len(holdings) > 1 {
+		return nil
+	}
+	
+	if len(holdings) == 1 {
+		return tx.Model(&holdings[0]).Update("total_copies", book.TotalCopies).Error
+	}
+	
+	if branchID == uuid.Nil {
+		branch, err := DefaultBranch(tx)
+		if err != nil {
+			return err
+		}
+		branchID = branch.ID
+	}
+	return AdjustHolding(tx, book.ID, branchID, book.TotalCopies)
+}
+
+func (s *CirculationService) Holdings(bookID uuid.UUID) ([]models.HoldingResponse, error) {
+	var holdings []models.BookHolding
+	if err := s.db.Preload("Branch").Preload("Location").Where("book_id = ?", bookID).Find(&holdings).Error; err != nil {
+		return nil, err
+	}
+	
+	responses := make([]models.HoldingResponse, 0, len(holdings))
+	for _, h := range holdings {
+		responses = append(responses, holdingUsage(s.db, h))
+	}
+	return responses, nil
+}
+
+func (s *CirculationService) UpdateHolding(bookID, branchID uuid.UUID, totalCopies int) (*models.HoldingResponse, error) {
+	var response models.HoldingResponse
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", bookID).Error; err != nil {
+			return ErrBookNotFound
+		}
+		
+		var branch models.Branch
+		if err := tx.First(&branch, "id = ?", branchID).Error; err != nil {
+			return ErrBranchNotFound
+		}
+		
+		holding := models.BookHolding{BookID: book.ID, BranchID: branch.ID, Branch: branch}
+		tx.Where("book_id = ? AND branch_id = ?", book.ID, branch.ID).First(&holding)
+		
+		usage := holdingUsage(tx, holding)
+		if totalCopies < int(usage.TotalCopies-usage.OnShelf) {
+			return ErrHoldingInUse
+		}
+		
+		delta := totalCopies - holding.TotalCopies
+		if err := AdjustHolding(tx, book.ID, branch.ID, delta); err != nil {
+			return err
+		}
+		
+		if delta < 0 {
+			book.TotalCopies += delta
+			book.AvailableCopies += delta
+			if book.AvailableCopies < 0 {
+				book.AvailableCopies = 0
+			}
+			if book.AvailableCopies == 0 && book.Status == models.BookStatusAvailable {
+				book.Status = models.BookStatusLoaned
+			}
+			if err := tx.Save(&book).Error; err != nil {
+				return err
+			}
+		}
+		
+		for i := 0; i < delta; i++ {
+			book.TotalCopies++
+			if _, _, err := s.trapNextHold(tx, &book, branch.ID, branch.ID); err != nil {
+				return err
+			}
+		}
+		
+		holding.TotalCopies = totalCopies
+		response = holdingUsage(tx, holding)
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (s *CirculationService) RequestTransfer(bookID uuid.UUID, fromID, toID string, requestedByID uuid.UUID, notes string) (*models.Transfer, error) {
+	to, err := ResolveBranch(s.db, toID)
+	if err != nil {
+		return nil, err
+	}
+	
+	var book models.Book
+	if err := s.db.First(&book, "id = ?", bookID).Error; err != nil {
+		return nil, ErrBookNotFound
+	}
+	
+	var fromBranchID uuid.UUID
+	if fromID != "" {
+		from, err := ResolveBranch(s.db, fromID)
+		if err != nil {
+			return nil, err
+		}
+		fromBranchID = from.ID
+	} else {
+		fromBranchID, err = s.shelfBranch(s.db, book.ID, to.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	
+	if fromBranchID == to.ID {
+		return nil, ErrTransferSameBranch
+	}
+	
+	transfer := models.Transfer{
+		BookID:         book.ID,
+		OwningBranchID: fromBranchID,
+		FromBranchID:   fromBranchID,
+		ToBranchID:     to.ID,
+		Reason:         models.TransferReasonRequest,
+		Status:         models.TransferRequested,
+		RequestedByID:  &requestedByID,
+		Notes:          notes,
+	}
+	
+	if err := s.db.Create(&transfer).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (s *CirculationService) ShipTransfer(transferID uuid.UUID) (*models.Transfer, error) {
+	var transfer models.Transfer
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transfer, "id = ?", transferID).Error; err != nil {
+			return ErrTransferNotFound
+		}
+		
+		if transfer.Status != models.TransferRequested {
+			return ErrInvalidTransferState
+		}
+		
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", transfer.BookID).Error; err != nil {
+			return ErrBookNotFound
+		}
+		
+		onShelf, err := s.onShelfAt(tx, book.ID, transfer.FromBranchID)
+		if err != nil {
+			return err
+		}
+		if onShelf < 1 || !book.IsAvailable() {
+			return ErrNoCopyAtBranch
+		}
+		
+		book.DecrementAvailable()
+		if err := tx.Save(&book).Error; err != nil {
+			return err
+		}
+		
+		transfer.Ship()
+		return tx.Save(&transfer).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (s *CirculationService) ReceiveTransfer(transferID, receivedByID uuid.UUID) (*models.Transfer, *models.Reservation, error) {
+	var transfer models.Transfer
+	var trapped *models.Reservation
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transfer, "id = ?", transferID).Error; err != nil {
+			return ErrTransferNotFound
+		}
+		
+		if transfer.Status != models.TransferInTransit {
+			return ErrInvalidTransferState
+		}
+		
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", transfer.BookID).Error; err != nil {
+			return ErrBookNotFound
+		}
+		
+		transfer.Receive(receivedByID)
+		if err := tx.Save(&transfer).Error; err != nil {
+			return err
+		}
+		
+		owner := transfer.OwningBranchID
+		if transfer.Reason == models.TransferReasonRequest {
+			if err := AdjustHolding(tx, book.ID, transfer.FromBranchID, -1); err != nil {
+				return err
+			}
+			if err := AdjustHolding(tx, book.ID, transfer.ToBranchID, 1); err != nil {
+				return err
+			}
+			owner = transfer.ToBranchID
+		}
+		
+		if transfer.Reason == models.TransferReasonHold && transfer.ReservationID != nil {
+			var hold models.Reservation
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&hold, "id = ?", *transfer.ReservationID).Error
+			if err == nil && hold.Status == models.ReservationStatusInTransit {
+				hold.Trap(s.config.Library.DefaultPickupLocation, s.config.Library.HoldPickupDays)
+				if err := tx.Save(&hold).Error; err != nil {
+					return err
+				}
+				trapped = &hold
+				return nil
+			}
+		}
+		
+		hold, _, err := s.trapNextHold(tx, &book, owner, transfer.ToBranchID)
+		trapped = hold
+		return err
+	})
+	
+	if err != nil {
+		return nil, nil, err
+	}
+	return &transfer, trapped, nil
+}
+
+func (s *CirculationService) CancelTransfer(transferID uuid.UUID) (*models.Transfer, error) {
+	var transfer models.Transfer
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transfer, "id = ?", transferID).Error; err != nil {
+			return ErrTransferNotFound
+		}
+		
+		if transfer.Status != models.TransferRequested {
+			return ErrInvalidTransferState
+		}
+		
+		transfer.Status = models.TransferCancelled
+		return tx.Save(&transfer).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (s *CirculationService) Transfers(branchID, direction, status string) ([]models.Transfer, error) {
+	query := s.db.Preload("Book").Preload("FromBranch").Preload("ToBranch")
+	
+	if branchID != "" {
+		switch direction {
+		case "inbound":
+			query = query.Where("to_branch_id = ?", branchID)
+		case "outbound":
+			query = query.Where("from_branch_id = ?", branchID)
+		default:
+			query = query.Where("to_branch_id = ? OR from_branch_id = ?", branchID, branchID)
+		}
+	}
+	
+	if status != "" {
+		query = query.Where("status = ?", status)
+	} else {
+		query = query.Where("status IN ?", []models.TransferStatus{models.TransferRequested, models.TransferInTransit})
+	}
+	
+	var transfers []models.Transfer
+	err := query.Order("requested_at ASC").Find(&transfers).Error
+	return transfers, err
+}
+
+func (s *CirculationService) branchOrDefault(tx *gorm.DB, id *uuid.UUID) (uuid.UUID, error) {
+	if id != nil && *id != uuid.Nil {
+		return *id, nil
+	}
+	
+	branch, err := DefaultBranch(tx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return branch.ID, nil
+}
+
+func (s *CirculationService) shelfBranch(tx *gorm.DB, bookID, exclude uuid.UUID) (uuid.UUID, error) {
+	var holdings []models.BookHolding
+	if err := tx.Where("book_id = ? AND branch_id <> ?", bookID, exclude).
+		Order("total_copies DESC").Find(&holdings).Error; err != nil {
+		return uuid.Nil, err
+	}
+	
+	best, bestCount := uuid.Nil, int64(0)
+	for _, h := range holdings {
+		if usage := holdingUsage(tx, h); usage.OnShelf > bestCount {
+			best, bestCount = h.BranchID, usage.OnShelf
+		}
+	}
+	
+	if best == uuid.Nil {
+		return uuid.Nil, ErrNoCopyAtBranch
+	}
+	return best, nil
+}
+
+func (s *CirculationService) onShelfAt(tx *gorm.DB, bookID, branchID uuid.UUID) (int64, error) {
+	var holding models.BookHolding
+	err := tx.Where("book_id = ? AND branch_id = ?", bookID, branchID).First(&holding).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return holdingUsage(tx, holding).OnShelf, nil
+}
+
+func holdingUsage(tx *gorm.DB, holding models.BookHolding) models.HoldingResponse {
+	usage := models.HoldingResponse{
+		BranchID:    holding.BranchID.String(),
+		BranchCode:  holding.Branch.Code,
+		BranchName:  holding.Branch.Name,
+		TotalCopies: int64(holding.TotalCopies),
+	}
+	if holding.Location != nil {
+		usage.Location = holding.Location.Path
+	}
+	
+	tx.Model(&models.Loan{}).
+		Where("book_id = ? AND owning_branch_id = ? AND status IN ?", holding.BookID, holding.BranchID,
+			[]models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue}).
+		Count(&usage.OnLoan)
+	
+	tx.Model(&models.Transfer{}).
+		Where("book_id = ? AND owning_branch_id = ? AND status = ?", holding.BookID, holding.BranchID, models.TransferInTransit).
+		Count(&usage.InTransit)
+	
+	tx.Model(&models.Reservation{}).
+		Where("book_id = ? AND item_branch_id = ? AND status = ?", holding.BookID, holding.BranchID, models.ReservationStatusReadyForPickup).
+		Count(&usage.OnHoldShelf)
+	
+	tx.Model(&models.Repair{}).
+		Where("book_id = ? AND branch_id = ? AND status IN ?", holding.BookID, holding.BranchID,
+			[]models.RepairStatus{models.RepairStatusQueued, models.RepairStatusInRepair}).
+		Count(&usage.InRepair)
+	
+	usage.OnShelf = usage.TotalCopies - usage.OnLoan - usage.InTransit - usage.OnHoldShelf - usage.InRepair
+	if usage.OnShelf < 0 {
+		usage.OnShelf = 0
+	}
+	return usage
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/pkg/callnumber"
+
+	"gorm.io/gorm"
+)
+
+const shelfOrder = `call_number_sort COLLATE "C"`
+
+func ParseCallNumber(value, classification, preferred string) (callnumber.CallNumber, error) {
+	if strings.TrimSpace(value) == "" {
+		return callnumber.CallNumber{}, nil
+	}
+	
+	if classification != "" {
+		return callnumber.Parse(value, callnumber.Scheme(strings.ToLower(classification)))
+	}
+	return callnumber.Detect(value, callnumber.Scheme(preferred))
+}
+
+func ApplyCallNumber(book *models.Book, parsed callnumber.CallNumber) {
+	book.CallNumber = parsed.Display
+	book.Classification = string(parsed.Scheme)
+	book.CallNumberSort = parsed.SortKey
+}
+
+func EnsureCallNumbers(db *gorm.DB, cfg *config.Config) (int, error) {
+	updated := 0
+	
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var books []models.Book
+		if err := tx.Select("id", "call_number", "classification").
+			Where("call_number <> '' AND (call_number_sort IS NULL OR call_number_sort = '')").
+			Find(&books).Error; err != nil {
+			return err
+		}
+		
+		for _, book := range books {
+			parsed, err := ParseCallNumber(book.CallNumber, book.Classification, cfg.Library.CallNumberScheme)
+			if err != nil {
+				parsed, _ = callnumber.Parse(book.CallNumber, callnumber.Local)
+			}
+			if err := tx.Model(&models.Book{}).Where("id = ?", book.ID).Updates(map[string]interface{}{
+				"call_number":      parsed.Display,
+				"classification":   string(parsed.Scheme),
+				"call_number_sort": parsed.SortKey,
+			}).Error; err != nil {
+				return err
+			}
+			updated++
+		}
+		
+		var locations []models.Location
+		if err := tx.Where("(call_number_start <> '' AND (start_sort IS NULL OR start_sort = '')) OR (call_number_end <> '' AND (end_sort IS NULL OR end_sort = ''))").
+			Find(&locations).Error; err != nil {
+			return err
+		}
+		
+		for _, location := range locations {
+			if err := tx.Model(&models.Location{}).Where("id = ?", location.ID).Updates(map[string]interface{}{
+				"start_sort": rangeSortKey(location.CallNumberStart, cfg.Library.CallNumberScheme),
+				"end_sort":   rangeSortKey(location.CallNumberEnd, cfg.Library.CallNumberScheme),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	
+	return updated, err
+}
+
+func (s *CatalogService) BrowseShelf(parsed callnumber.CallNumber, before, after int) ([]models.Book, []models.Book, error) {
+	var previous []models.Book
+	if err := s.db.Where("classification = ? AND "+shelfOrder+" < ?", parsed.Scheme, parsed.SortKey).
+		Order(shelfOrder + " DESC").Order("id DESC").
+		Limit(before).
+		Find(&previous).Error; err != nil {
+		return nil, nil, err
+	}
+	
+	for i, j := 0, len(previous)-1; i < j; i, j = i+1, j-1 {
+		previous[i], previous[j] = previous[j], previous[i]
+	}
+	
+	var next []models.Book
+	if err := s.db.Where("classification = ? AND "+shelfOrder+" >= ?", parsed.Scheme, parsed.SortKey).
+		Order(shelfOrder + " ASC").Order("id ASC").
+		Limit(after).
+		Find(&next).Error; err != nil {
+		return nil, nil, err
+	}
+	
+	return previous, next, nil
+}
+
+func rangeSortKey(value, preferred string) string {
+	if strings.TrimSpace(value) == "" {
+		return ""
+	}
+	
+	parsed, err := callnumber.Detect(value, callnumber.Scheme(preferred))
+	if err != nil {
+		return callnumber.SortKey(value, callnumber.Local)
+	}
+	return parsed.SortKey
+}
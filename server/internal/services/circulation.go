@@ -0,0 +1,721 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/pkg/isbn"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrBookNotFound     = errors.New("book not found")
+	ErrMemberNotFound   = errors.New("member not found")
+	ErrLoanNotFound     = errors.New("loan not found")
+	ErrMemberInactive   = errors.New("membership is inactive or expired")
+	ErrLoanLimitReached = errors.New("member has reached the maximum number of loans")
+	ErrBookUnavailable  = errors.New("book is not available for loan")
+	ErrBookOnHold       = errors.New("book is on hold for another member")
+	ErrLoanNotActive    = errors.New("loan is not active")
+	ErrLoanAmbiguous    = errors.New("several copies of this title are on loan, give the patron or the loan")
+	ErrCopyOnLoan       = errors.New("this copy is already checked out")
+	ErrCopyNotFound     = errors.New("copy barcode does not belong to this title")
+	
+	ErrPaymentExceedsBalance = errors.New("payment exceeds outstanding fines")
+)
+
+type RenewalDeniedError struct {
+	Reason models.RenewalDenial
+}
+
+func (e *RenewalDeniedError) Error() string {
+	return "loan cannot be renewed: " + string(e.Reason)
+}
+
+type CirculationService struct {
+	db     *gorm.DB
+	config *config.Config
+}
+
+func NewCirculationService(db *gorm.DB, cfg *config.Config) *CirculationService {
+	return &CirculationService{
+		db:     db,
+		config: cfg,
+	}
+}
+
+type CheckoutResult struct {
+	Loan        *models.Loan
+	Reservation *models.Reservation
+}
+
+type ReturnResult struct {
+	Loan        *models.Loan
+	TrappedHold *models.Reservation
+	Transfer    *models.Transfer
+	Credit      float64
+}
+
+func (s *CirculationService) FindItem(code string) (*models.Book, error) {
+	book, _, err := s.FindCopy(code)
+	return book, err
+}
+
+// FindCopy resolves a scanned code to its title. When the code is a copy
+// barcode it is returned alongside the book; title barcodes and ISBNs
+// return an empty copy barcode.
+func (s *CirculationService) FindCopy(code string) (*models.Book, string, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, "", ErrBookNotFound
+	}
+	
+	var book models.Book
+	var item models.BookCopy
+	if err := s.db.Where("barcode = ?", code).First(&item).Error; err == nil {
+		if err := s.db.First(&book, "id = ?", item.BookID).Error; err != nil {
+			return nil, "", ErrBookNotFound
+		}
+		return &book, item.Barcode, nil
+	}
+	
+	if err := s.db.Where("barcode = ?", code).First(&book).Error; err == nil {
+		return &book, "", nil
+	}
+	
+	if normalized, err := isbn.Normalize(code); err == nil {
+		code = normalized
+	}
+	
+	if err := s.db.Where("isbn = ?", code).First(&book).Error; err != nil {
+		return nil, "", ErrBookNotFound
+	}
+	return &book, "", nil
+}
+
+func (s *CirculationService) FindMemberByCard(membershipID string) (*models.Member, error) {
+	var member models.Member
+	if err := s.db.Preload("User").Where("membership_id = ?", strings.TrimSpace(membershipID)).First(&member).Error; err != nil {
+		return nil, ErrMemberNotFound
+	}
+	return &member, nil
+}
+
+// FindOutstandingLoan returns the open loan of a title. Without a member it
+// refuses to guess when more than one copy is out.
+func (s *CirculationService) FindOutstandingLoan(bookID, memberID uuid.UUID) (*models.Loan, error) {
+	query := s.db.Where("book_id = ? AND status IN ?", bookID,
+		[]models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue, models.LoanStatusLost})
+	if memberID != uuid.Nil {
+		query = query.Where("member_id = ?", memberID)
+	}
+	
+	var loans []models.Loan
+	if err := query.Order("due_date ASC").Limit(2).Find(&loans).Error; err != nil {
+		return nil, err
+	}
+	switch {
+	case len(loans) == 0:
+		return nil, ErrLoanNotFound
+	case len(loans) > 1 && memberID == uuid.Nil:
+		return nil, ErrLoanAmbiguous
+	}
+	return &loans[0], nil
+}
+
+// FindCheckinLoan resolves a scanned code to the loan it closes. A copy
+// barcode identifies its loan exactly; a title barcode or ISBN needs the
+// member when several copies of the title are out.
+func (s *CirculationService) FindCheckinLoan(code string, memberID uuid.UUID) (*models.Book, *models.Loan, error) {
+	book, copyBarcode, err := s.FindCopy(code)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	if copyBarcode == "" {
+		loan, err := s.FindOutstandingLoan(book.ID, memberID)
+		return book, loan, err
+	}
+	
+	var loan models.Loan
+	if err := s.db.Where("book_id = ? AND copy_barcode = ? AND status IN ?", book.ID, copyBarcode,
+		[]models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue, models.LoanStatusLost}).
+		First(&loan).Error; err != nil {
+		return book, nil, ErrLoanNotFound
+	}
+	return book, &loan, nil
+}
+
+func (s *CirculationService) Checkout(bookID, memberID, issuedByID, branchID uuid.UUID, copyBarcode string, dueDate time.Time, notes string) (*CheckoutResult, error) {
+	result := &CheckoutResult{}
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", bookID).Error; err != nil {
+			return ErrBookNotFound
+		}
+		
+		var member models.Member
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&member, "id = ?", memberID).Error; err != nil {
+			return ErrMemberNotFound
+		}
+		
+		if !member.IsActive || member.IsExpired() {
+			return ErrMemberInactive
+		}
+		
+		if !member.CanBorrowMore() {
+			return ErrLoanLimitReached
+		}
+		
+		rule := ItemRule(tx, s.config, book.ItemType)
+		if !rule.Lendable {
+			return ErrItemNotLendable
+		}
+		
+		if copyBarcode != "" {
+			var copies int64
+			tx.Model(&models.BookCopy{}).Where("book_id = ? AND barcode = ?", book.ID, copyBarcode).Count(&copies)
+			if copies == 0 {
+				return ErrCopyNotFound
+			}
+			
+			var out int64
+			tx.Model(&models.Loan{}).
+				Where("book_id = ? AND copy_barcode = ? AND status IN ?", book.ID, copyBarcode,
+					[]models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue, models.LoanStatusLost}).
+				Count(&out)
+			if out > 0 {
+				return ErrCopyOnLoan
+			}
+		}
+		if rule.MaxPerMember > 0 {
+			var borrowed int64
+			tx.Model(&models.Loan{}).
+				Joins("JOIN books ON books.id = loans.book_id").
+				Where("loans.member_id = ? AND loans.status IN ? AND books.item_type = ?",
+					member.ID, []models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue}, book.ItemType).
+				Count(&borrowed)
+			if borrowed >= int64(rule.MaxPerMember) {
+				return ErrItemTypeLimit
+			}
+		}
+		
+		var hold models.Reservation
+		err := tx.Where("book_id = ? AND member_id = ? AND status = ?",
+			book.ID, member.ID, models.ReservationStatusReadyForPickup).First(&hold).Error
+		
+		var owner uuid.UUID
+		if err == nil {
+			if owner, err = s.branchOrDefault(tx, hold.ItemBranchID); err != nil {
+				return err
+			}
+			if branchID == uuid.Nil {
+				branchID = owner
+				if hold.PickupBranchID != nil {
+					branchID = *hold.PickupBranchID
+				}
+			}
+			
+			hold.Fulfill()
+			if err := tx.Save(&hold).Error; err != nil {
+				return err
+			}
+			result.Reservation = &hold
+		} else {
+			if !book.IsAvailable() {
+				var holds int64
+				tx.Model(&models.Reservation{}).
+					Where("book_id = ? AND status = ?", book.ID, models.ReservationStatusReadyForPickup).
+					Count(&holds)
+				if holds > 0 {
+					return ErrBookOnHold
+				}
+				return ErrBookUnavailable
+			}
+			
+			if branchID == uuid.Nil {
+				if branchID, err = s.shelfBranch(tx, book.ID, uuid.Nil); errors.Is(err, ErrNoCopyAtBranch) {
+					branchID, err = s.branchOrDefault(tx, nil)
+				}
+				if err != nil {
+					return err
+				}
+			} else {
+				onShelf, err := s.onShelfAt(tx, book.ID, branchID)
+				if err != nil {
+					return err
+				}
+				if onShelf < 1 {
+					return ErrNoCopyAtBranch
+				}
+			}
+			owner = branchID
+			book.DecrementAvailable()
+		}
+		
+		if dueDate.IsZero() {
+			dueDate = time.Now().AddDate(0, 0, rule.LoanDays)
+		}
+		
+		loan := models.Loan{
+			ID:             uuid.New(),
+			BookID:         book.ID,
+			MemberID:       member.ID,
+			IssuedByID:     issuedByID,
+			BranchID:       &branchID,
+			OwningBranchID: &owner,
+			LoanDate:       time.Now(),
+			DueDate:        dueDate,
+			Status:         models.LoanStatusActive,
+			MaxRenewals:    rule.MaxRenewals,
+			CopyBarcode:    copyBarcode,
+			Notes:          notes,
+		}
+		
+		if err := tx.Create(&loan).Error; err != nil {
+			return err
+		}
+		
+		member.CurrentBooksIssued++
+		if err := tx.Save(&member).Error; err != nil {
+			return err
+		}
+		
+		if err := tx.Save(&book).Error; err != nil {
+			return err
+		}
+		
+		loan.Book = book
+		loan.Member = member
+		result.Loan = &loan
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *CirculationService) Return(loanID, branchID uuid.UUID, returnDate time.Time) (*ReturnResult, error) {
+	result := &ReturnResult{}
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var loan models.Loan
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&loan, "id = ?", loanID).Error; err != nil {
+			return ErrLoanNotFound
+		}
+		
+		if !loan.IsOutstanding() && loan.Status != models.LoanStatusLost {
+			return ErrLoanNotActive
+		}
+		
+		if returnDate.IsZero() {
+			returnDate = time.Now()
+		}
+		
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", loan.BookID).Error; err != nil {
+			return ErrBookNotFound
+		}
+		
+		owner, err := s.branchOrDefault(tx, loan.OwningBranchID)
+		if err != nil {
+			return err
+		}
+		if branchID == uuid.Nil {
+			branchID = owner
+		}
+		loan.ReturnBranchID = &branchID
+		loan.AutoRenewed = false
+		
+		var member models.Member
+		memberErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&member, "id = ?", loan.MemberID).Error
+		
+		if loan.Status == models.LoanStatusLost {
+			credit := loan.MarkFound(returnDate)
+			book.RestoreCopy()
+			if err := AdjustHolding(tx, book.ID, owner, 1); err != nil {
+				return err
+			}
+			if memberErr == nil {
+				member.TotalFineAmount -= credit
+			}
+			result.Credit = credit
+		} else {
+			loan.ActualReturnDate = &returnDate
+			loan.FineAmount = loan.CalculateFine(ItemRule(tx, s.config, book.ItemType).FinePerDay)
+			loan.ReturnDate = &returnDate
+			loan.Status = models.LoanStatusReturned
+			if memberErr == nil {
+				if member.CurrentBooksIssued > 0 {
+					member.CurrentBooksIssued--
+				}
+				member.TotalFineAmount += loan.FineAmount
+			}
+		}
+		
+		if err := tx.Save(&loan).Error; err != nil {
+			return err
+		}
+		
+		if memberErr == nil {
+			if err := tx.Save(&member).Error; err != nil {
+				return err
+			}
+		}
+		
+		hold, transfer, err := s.trapNextHold(tx, &book, owner, branchID)
+		if err != nil {
+			return err
+		}
+		
+		loan.Book = book
+		result.Loan = &loan
+		result.TrappedHold = hold
+		result.Transfer = transfer
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *CirculationService) Renew(loanID uuid.UUID, newDueDate time.Time, override bool) (*models.Loan, error) {
+	var loan models.Loan
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&loan, "id = ?", loanID).Error; err != nil {
+			return ErrLoanNotFound
+		}
+		
+		if err := s.renewLoan(tx, &loan, newDueDate, override); err != nil {
+			return err
+		}
+		
+		loan.AutoRenewed = false
+		return tx.Save(&loan).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+func (s *CirculationService) RenewalDenial(loan *models.Loan) models.RenewalDenial {
+	return s.renewalDenial(s.db, loan, false)
+}
+
+// RenewalDenials works out RenewalDenial for a page of loans, loading the
+// holds waiting on their titles in one query rather than one per loan.
+func (s *CirculationService) RenewalDenials(loans []models.Loan) map[uuid.UUID]models.RenewalDenial {
+	denials := make(map[uuid.UUID]models.RenewalDenial, len(loans))
+	
+	bookIDs := make([]uuid.UUID, 0, len(loans))
+	for i := range loans {
+		if reason := loans[i].RenewalDenial(); reason != "" {
+			denials[loans[i].ID] = reason
+			continue
+		}
+		bookIDs = append(bookIDs, loans[i].BookID)
+	}
+	if len(bookIDs) == 0 {
+		return denials
+	}
+	
+	var rows []struct {
+		BookID   uuid.UUID
+		MemberID uuid.UUID
+		Holds    int64
+	}
+	s.db.Model(&models.Reservation{}).
+		Select("book_id, member_id, COUNT(*) AS holds").
+		Where("book_id IN ? AND status = ?", bookIDs, models.ReservationStatusPending).
+		Group("book_id, member_id").
+		Scan(&rows)
+	
+	total := make(map[uuid.UUID]int64)
+	own := make(map[[2]uuid.UUID]int64)
+	for _, row := range rows {
+		total[row.BookID] += row.Holds
+		own[[2]uuid.UUID{row.BookID, row.MemberID}] = row.Holds
+	}
+	
+	for _, loan := range loans {
+		if _, denied := denials[loan.ID]; denied {
+			continue
+		}
+		if total[loan.BookID]-own[[2]uuid.UUID{loan.BookID, loan.MemberID}] > 0 {
+			denials[loan.ID] = models.RenewalDeniedReserved
+		}
+	}
+	return denials
+}
+
+func (s *CirculationService) AutoRenew() (int, error) {
+	if !s.config.Library.AutoRenewEnabled {
+		return 0, nil
+	}
+	
+	cutoff := time.Now().AddDate(0, 0, s.config.Library.AutoRenewDaysBefore)
+	
+	var due []models.Loan
+	if err := s.db.Joins("JOIN members ON members.id = loans.member_id AND members.auto_renew = ?", true).
+		Where("loans.status = ? AND loans.due_date > ? AND loans.due_date <= ?",
+			models.LoanStatusActive, time.Now(), cutoff).Find(&due).Error; err != nil {
+		return 0, err
+	}
+	
+	renewed := 0
+	for _, l := range due {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var loan models.Loan
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&loan, "id = ?", l.ID).Error; err != nil {
+				return err
+			}
+			
+			if err := s.renewLoan(tx, &loan, time.Time{}, false); err != nil {
+				return err
+			}
+			
+			loan.AutoRenewed = true
+			return tx.Save(&loan).Error
+		})
+		
+		var denied *RenewalDeniedError
+		if errors.As(err, &denied) {
+			continue
+		}
+		if err != nil {
+			return renewed, err
+		}
+		renewed++
+	}
+	
+	return renewed, nil
+}
+
+func (s *CirculationService) renewLoan(tx *gorm.DB, loan *models.Loan, newDueDate time.Time, override bool) error {
+	if reason := s.renewalDenial(tx, loan, override); reason != "" {
+		return &RenewalDeniedError{Reason: reason}
+	}
+	
+	var book models.Book
+	tx.Select("id", "item_type").First(&book, "id = ?", loan.BookID)
+	loan.Renew(ItemRule(tx, s.config, book.ItemType).LoanDays)
+	if !newDueDate.IsZero() && newDueDate.After(time.Now()) {
+		loan.DueDate = newDueDate
+	}
+	
+	return nil
+}
+
+func (s *CirculationService) renewalDenial(tx *gorm.DB, loan *models.Loan, override bool) models.RenewalDenial {
+	if reason := loan.RenewalDenial(); reason != "" {
+		return reason
+	}
+	
+	if override {
+		return ""
+	}
+	
+	var waiting int64
+	tx.Model(&models.Reservation{}).
+		Where("book_id = ? AND member_id <> ? AND status = ?", loan.BookID, loan.MemberID, models.ReservationStatusPending).
+		Count(&waiting)
+	
+	if waiting > 0 {
+		return models.RenewalDeniedReserved
+	}
+	
+	return ""
+}
+
+func (s *CirculationService) HoldShelf(pickupLocation string) ([]models.Reservation, error) {
+	query := s.db.Preload("Book").Preload("Member.User").
+		Where("status = ?", models.ReservationStatusReadyForPickup)
+	
+	if pickupLocation != "" {
+		query = query.Where("pickup_location = ?", pickupLocation)
+	}
+	
+	var holds []models.Reservation
+	err := query.Order("pickup_location ASC").Order("pickup_deadline ASC").Find(&holds).Error
+	return holds, err
+}
+
+func (s *CirculationService) ExpireHolds() (int, error) {
+	var expired []models.Reservation
+	if err := s.db.Where("status = ? AND pickup_deadline < ?",
+		models.ReservationStatusReadyForPickup, time.Now()).Find(&expired).Error; err != nil {
+		return 0, err
+	}
+	
+	count := 0
+	for _, r := range expired {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var hold models.Reservation
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&hold, "id = ?", r.ID).Error; err != nil {
+				return err
+			}
+			
+			if !hold.IsPickupExpired() {
+				return nil
+			}
+			
+			hold.ExpirePickup()
+			if err := tx.Save(&hold).Error; err != nil {
+				return err
+			}
+			
+			var book models.Book
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", hold.BookID).Error; err != nil {
+				return err
+			}
+			
+			owner, err := s.branchOrDefault(tx, hold.ItemBranchID)
+			if err != nil {
+				return err
+			}
+			
+			location, err := s.branchOrDefault(tx, hold.PickupBranchID)
+			if err != nil {
+				return err
+			}
+			
+			if _, _, err := s.trapNextHold(tx, &book, owner, location); err != nil {
+				return err
+			}
+			
+			count++
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+	}
+	
+	return count, nil
+}
+
+func (s *CirculationService) trapNextHold(tx *gorm.DB, book *models.Book, ownerID, locationID uuid.UUID) (*models.Reservation, *models.Transfer, error) {
+	var next models.Reservation
+	err := tx.Where("book_id = ? AND status = ?", book.ID, models.ReservationStatusPending).
+		Order("queue_position ASC").Order("reservation_date ASC").
+		First(&next).Error
+	
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if locationID != ownerID {
+			transfer, err := s.shipCopy(tx, book.ID, ownerID, locationID, ownerID, models.TransferReasonReturn, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, transfer, tx.Save(book).Error
+		}
+		book.IncrementAvailable()
+		return nil, nil, tx.Save(book).Error
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	if next.PickupBranchID == nil {
+		next.PickupBranchID = &locationID
+	}
+	next.ItemBranchID = &ownerID
+	
+	var transfer *models.Transfer
+	if *next.PickupBranchID != locationID {
+		next.StartTransit()
+		if transfer, err = s.shipCopy(tx, book.ID, ownerID, locationID, *next.PickupBranchID, models.TransferReasonHold, &next.ID); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		next.Trap(s.config.Library.DefaultPickupLocation, s.config.Library.HoldPickupDays)
+	}
+	
+	if err := tx.Save(&next).Error; err != nil {
+		return nil, nil, err
+	}
+	
+	book.HoldReturnedCopy()
+	if err := tx.Save(book).Error; err != nil {
+		return nil, nil, err
+	}
+	
+	if err := s.reorderQueue(tx, book.ID); err != nil {
+		return nil, nil, err
+	}
+	
+	return &next, transfer, nil
+}
+
+func (s *CirculationService) shipCopy(tx *gorm.DB, bookID, ownerID, fromID, toID uuid.UUID, reason models.TransferReason, reservationID *uuid.UUID) (*models.Transfer, error) {
+	transfer := models.Transfer{
+		BookID:         bookID,
+		OwningBranchID: ownerID,
+		FromBranchID:   fromID,
+		ToBranchID:     toID,
+		Reason:         reason,
+		ReservationID:  reservationID,
+	}
+	transfer.Ship()
+	
+	if err := tx.Create(&transfer).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (s *CirculationService) reorderQueue(tx *gorm.DB, bookID uuid.UUID) error {
+	var pending []models.Reservation
+	if err := tx.Where("book_id = ? AND status = ?", bookID, models.ReservationStatusPending).
+		Order("queue_position ASC").Order("reservation_date ASC").
+		Find(&pending).Error; err != nil {
+		return err
+	}
+	
+	for i, r := range pending {
+		if r.QueuePosition == i+1 {
+			continue
+		}
+		if err := tx.Model(&models.Reservation{}).Where("id = ?", r.ID).
+			Update("queue_position", i+1).Error; err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (s *CirculationService) RecordPayment(memberID uuid.UUID, amount float64) (*models.Member, error) {
+	var member models.Member
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&member, "id = ?", memberID).Error; err != nil {
+			return ErrMemberNotFound
+		}
+		
+		if amount > member.GetOutstandingFines()+0.005 {
+			return ErrPaymentExceedsBalance
+		}
+		
+		member.FinesPaid += amount
+		return tx.Save(&member).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
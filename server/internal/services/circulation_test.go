@@ -0,0 +1,150 @@
+package services
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/database"
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func testDB(t *testing.T) (*gorm.DB, *config.Config) {
+	t.Helper()
+	
+	if os.Getenv("SERVICES_TEST_DATABASE") == "" {
+		t.Skip("set SERVICES_TEST_DATABASE=1 and DB_* to run against Postgres")
+	}
+	
+	cfg := config.Load()
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if _, _, err := EnsureBranches(db, cfg); err != nil {
+		t.Fatalf("branches: %v", err)
+	}
+	return db, cfg
+}
+
+func createMember(t *testing.T, db *gorm.DB, name string, autoRenew bool) models.Member {
+	t.Helper()
+	
+	suffix := uuid.New().String()[:8]
+	user := models.User{Email: name + "-" + suffix + "@example.org", FullName: name, Role: models.RoleMember, IsActive: true}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	
+	member := models.Member{UserID: user.ID, MembershipID: "M" + suffix, JoinDate: time.Now(), MaxBooksAllowed: 5, IsActive: true, AutoRenew: autoRenew}
+	if err := db.Create(&member).Error; err != nil {
+		t.Fatalf("create member: %v", err)
+	}
+	// AutoRenew false is a zero value and would be replaced by the column default.
+	db.Model(&member).Update("auto_renew", autoRenew)
+	return member
+}
+
+func createLoan(t *testing.T, db *gorm.DB, member models.Member, dueDate time.Time) models.Loan {
+	t.Helper()
+	
+	book := models.Book{Title: "Renewal Test", Author: "Tester", Barcode: "B" + uuid.New().String()[:8], TotalCopies: 1, AvailableCopies: 0, Status: models.BookStatusLoaned}
+	if err := db.Create(&book).Error; err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	
+	loan := models.Loan{BookID: book.ID, MemberID: member.ID, IssuedByID: member.UserID, DueDate: dueDate, Status: models.LoanStatusActive, MaxRenewals: 2}
+	if err := db.Create(&loan).Error; err != nil {
+		t.Fatalf("create loan: %v", err)
+	}
+	return loan
+}
+
+func TestAutoRenew(t *testing.T) {
+	db, cfg := testDB(t)
+	cfg.Library.AutoRenewEnabled = true
+	cfg.Library.AutoRenewDaysBefore = 2
+	circulation := NewCirculationService(db, cfg)
+	
+	dueSoon := time.Now().AddDate(0, 0, 1)
+	optedIn := createMember(t, db, "opted-in", true)
+	optedOut := createMember(t, db, "opted-out", false)
+	waiting := createMember(t, db, "waiting", false)
+	
+	renews := createLoan(t, db, optedIn, dueSoon)
+	notDueYet := createLoan(t, db, optedIn, time.Now().AddDate(0, 0, 10))
+	overdue := createLoan(t, db, optedIn, time.Now().AddDate(0, 0, -1))
+	optOut := createLoan(t, db, optedOut, dueSoon)
+	reserved := createLoan(t, db, optedIn, dueSoon)
+	
+	hold := models.Reservation{BookID: reserved.BookID, MemberID: waiting.ID, Status: models.ReservationStatusPending, QueuePosition: 1}
+	if err := db.Create(&hold).Error; err != nil {
+		t.Fatalf("create hold: %v", err)
+	}
+	
+	if _, err := circulation.AutoRenew(); err != nil {
+		t.Fatalf("auto renew: %v", err)
+	}
+	
+	tests := []struct {
+		name    string
+		loan    models.Loan
+		renewed bool
+	}{
+		{name: "due within the window", loan: renews, renewed: true},
+		{name: "not due yet", loan: notDueYet},
+		{name: "already overdue", loan: overdue},
+		{name: "member opted out", loan: optOut},
+		{name: "another member is waiting", loan: reserved},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var loan models.Loan
+			if err := db.First(&loan, "id = ?", tt.loan.ID).Error; err != nil {
+				t.Fatalf("reload loan: %v", err)
+			}
+			if loan.AutoRenewed != tt.renewed {
+				t.Errorf("auto_renewed = %v, want %v", loan.AutoRenewed, tt.renewed)
+			}
+			if renewed := loan.RenewalCount == 1; renewed != tt.renewed {
+				t.Errorf("renewal count = %d, renewed want %v", loan.RenewalCount, tt.renewed)
+			}
+		})
+	}
+	
+	if denial := circulation.RenewalDenial(&reserved); denial != models.RenewalDeniedReserved {
+		t.Errorf("denial for a reserved title = %q, want %q", denial, models.RenewalDeniedReserved)
+	}
+	if _, err := circulation.Renew(reserved.ID, time.Time{}, true); err != nil {
+		t.Errorf("librarian override refused: %v", err)
+	}
+	
+	t.Run("manual renewal clears auto_renewed", func(t *testing.T) {
+		loan, err := circulation.Renew(renews.ID, time.Time{}, false)
+		if err != nil {
+			t.Fatalf("renew: %v", err)
+		}
+		if loan.AutoRenewed {
+			t.Fatalf("auto_renewed still set after a manual renewal")
+		}
+	})
+	
+	t.Run("return clears auto_renewed", func(t *testing.T) {
+		db.Model(&models.Loan{}).Where("id = ?", renews.ID).Update("auto_renewed", true)
+		result, err := circulation.Return(renews.ID, uuid.Nil, time.Now())
+		if err != nil {
+			t.Fatalf("return: %v", err)
+		}
+		if result.Loan.AutoRenewed {
+			t.Fatalf("auto_renewed still set after return")
+		}
+	})
+}
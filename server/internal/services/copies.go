@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBarcodeInUse    = errors.New("barcode is already assigned to another item")
+	ErrBarcodeRepeated = errors.New("barcode is listed more than once")
+	ErrTooManyBarcodes = errors.New("more copy barcodes than copies")
+)
+
+// CleanCopyBarcodes trims the copy barcodes of a title and checks them
+// against each other, the title barcode and the number of copies.
+func CleanCopyBarcodes(titleBarcode string, barcodes []string, totalCopies int) ([]string, error) {
+	titleBarcode = strings.TrimSpace(titleBarcode)
+	seen := make(map[string]bool, len(barcodes))
+	cleaned := make([]string, 0, len(barcodes))
+	for _, barcode := range barcodes {
+		barcode = strings.TrimSpace(barcode)
+		if barcode == "" {
+			continue
+		}
+		if seen[barcode] || barcode == titleBarcode {
+			return nil, ErrBarcodeRepeated
+		}
+		seen[barcode] = true
+		cleaned = append(cleaned, barcode)
+	}
+	
+	if len(cleaned) > totalCopies {
+		return nil, ErrTooManyBarcodes
+	}
+	return cleaned, nil
+}
+
+// CheckBarcodes makes sure none of the given barcodes already identifies
+// another title or a copy of another title.
+func CheckBarcodes(db *gorm.DB, bookID uuid.UUID, barcodes ...string) error {
+	codes := make([]string, 0, len(barcodes))
+	for _, barcode := range barcodes {
+		if barcode = strings.TrimSpace(barcode); barcode != "" {
+			codes = append(codes, barcode)
+		}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	
+	var titles int64
+	db.Unscoped().Model(&models.Book{}).Where("barcode IN ? AND id <> ?", codes, bookID).Count(&titles)
+	
+	var copies int64
+	db.Model(&models.BookCopy{}).Where("barcode IN ? AND book_id <> ?", codes, bookID).Count(&copies)
+	
+	if titles > 0 || copies > 0 {
+		return ErrBarcodeInUse
+	}
+	return nil
+}
+
+// SyncCopies replaces the copy barcodes of a title. Copies that are out on
+// loan cannot be removed.
+func SyncCopies(tx *gorm.DB, bookID uuid.UUID, barcodes []string) error {
+	var current []models.BookCopy
+	if err := tx.Where("book_id = ?", bookID).Find(&current).Error; err != nil {
+		return err
+	}
+	
+	keep := make(map[string]bool, len(barcodes))
+	for _, barcode := range barcodes {
+		keep[barcode] = true
+	}
+	
+	existing := make(map[string]bool, len(current))
+	for _, item := range current {
+		existing[item.Barcode] = true
+		if keep[item.Barcode] {
+			continue
+		}
+		
+		var out int64
+		tx.Model(&models.Loan{}).
+			Where("book_id = ? AND copy_barcode = ? AND status IN ?", bookID, item.Barcode,
+				[]models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue, models.LoanStatusLost}).
+			Count(&out)
+		if out > 0 {
+			return ErrCopyOnLoan
+		}
+		if err := tx.Delete(&item).Error; err != nil {
+			return err
+		}
+	}
+	
+	for _, barcode := range barcodes {
+		if existing[barcode] {
+			continue
+		}
+		if err := tx.Create(&models.BookCopy{BookID: bookID, Barcode: barcode}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func CopyBarcodes(db *gorm.DB, bookID uuid.UUID) []string {
+	var barcodes []string
+	db.Model(&models.BookCopy{}).Where("book_id = ?", bookID).Order("barcode").Pluck("barcode", &barcodes)
+	return barcodes
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCleanCopyBarcodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		barcodes []string
+		total    int
+		want     []string
+		err      error
+	}{
+		{name: "none", total: 1, want: []string{}},
+		{name: "trimmed and blanks dropped", barcodes: []string{" C1 ", "", "C2"}, total: 2, want: []string{"C1", "C2"}},
+		{name: "repeated copy", barcodes: []string{"C1", "C1"}, total: 2, err: ErrBarcodeRepeated},
+		{name: "copy reuses title barcode", title: "T1", barcodes: []string{"T1"}, total: 1, err: ErrBarcodeRepeated},
+		{name: "more barcodes than copies", barcodes: []string{"C1", "C2"}, total: 1, err: ErrTooManyBarcodes},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CleanCopyBarcodes(tt.title, tt.barcodes, tt.total)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if tt.err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("barcodes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/storage"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCoverTooLarge       = errors.New("cover image exceeds the maximum upload size")
+	ErrCoverUnsupported    = errors.New("cover image must be a JPEG, PNG or WebP")
+	ErrCoverUnreadable     = errors.New("cover image could not be decoded")
+	ErrCoverNotFound       = errors.New("cover image not found")
+	ErrInvalidCoverSize    = errors.New("invalid cover size, expected large, medium or small")
+	ErrCoverDimensionLimit = errors.New("cover image dimensions are too large")
+)
+
+const (
+	CoverLarge  = "large"
+	CoverMedium = "medium"
+	CoverSmall  = "small"
+)
+
+const maxCoverPixels = 40_000_000
+
+var coverWidths = map[string]int{
+	CoverLarge:  1200,
+	CoverMedium: 400,
+	CoverSmall:  160,
+}
+
+var allowedCoverTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+type CoverService struct {
+	db     *gorm.DB
+	store  storage.BlobStore
+	config *config.Config
+}
+
+func NewCoverService(db *gorm.DB, store storage.BlobStore, cfg *config.Config) *CoverService {
+	return &CoverService{
+		db:     db,
+		store:  store,
+		config: cfg,
+	}
+}
+
+func (s *CoverService) Upload(ctx context.Context, bookID string, r io.Reader) (*models.Book, error) {
+	var book models.Book
+	if err := s.db.First(&book, "id = ?", bookID).Error; err != nil {
+		return nil, ErrBookNotFound
+	}
+	
+	data, err := io.ReadAll(io.LimitReader(r, s.config.Covers.MaxUploadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	
+	if int64(len(data)) > s.config.Covers.MaxUploadSize {
+		return nil, ErrCoverTooLarge
+	}
+	
+	if !allowedCoverTypes[http.DetectContentType(data)] {
+		return nil, ErrCoverUnsupported
+	}
+	
+	imgConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCoverUnreadable
+	}
+	
+	if imgConfig.Width*imgConfig.Height > maxCoverPixels {
+		return nil, ErrCoverDimensionLimit
+	}
+	
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCoverUnreadable
+	}
+	
+	sum := sha256.Sum256(data)
+	version := hex.EncodeToString(sum[:8])
+	orientation := exifOrientation(data)
+	
+	for size, width := range coverWidths {
+		encoded, err := encodeCover(img, width, orientation)
+		if err != nil {
+			return nil, err
+		}
+		
+		if err := s.store.Put(ctx, coverKey(book.ID.String(), version, size), encoded, "image/jpeg"); err != nil {
+			return nil, err
+		}
+	}
+	
+	previous := book.CoverVersion
+	book.CoverVersion = version
+	book.CoverImage = s.CoverURL(book.ID.String(), version, CoverLarge)
+	
+	if err := s.db.Model(&book).Updates(map[string]interface{}{
+		"cover_version": book.CoverVersion,
+		"cover_image":   book.CoverImage,
+	}).Error; err != nil {
+		return nil, err
+	}
+	
+	if previous != "" && previous != version {
+		for size := range coverWidths {
+			s.store.Delete(ctx, coverKey(book.ID.String(), previous, size))
+		}
+	}
+	
+	return &book, nil
+}
+
+func (s *CoverService) Open(ctx context.Context, bookID, version, size string) (io.ReadCloser, *storage.BlobInfo, error) {
+	if size == "" {
+		size = CoverLarge
+	}
+	
+	if _, ok := coverWidths[size]; !ok {
+		return nil, nil, ErrInvalidCoverSize
+	}
+	
+	if version == "" {
+		var book models.Book
+		if err := s.db.Select("id", "cover_version").First(&book, "id = ?", bookID).Error; err != nil || book.CoverVersion == "" {
+			return nil, nil, ErrCoverNotFound
+		}
+		version = book.CoverVersion
+	}
+	
+	body, info, err := s.store.Get(ctx, coverKey(bookID, version, size))
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return nil, nil, ErrCoverNotFound
+	}
+	return body, info, err
+}
+
+func (s *CoverService) CoverURL(bookID, version, size string) string {
+	params := url.Values{}
+	params.Set("book_id", bookID)
+	params.Set("size", size)
+	params.Set("v", version)
+	return s.config.APIPrefix + "/method/library_management.api.books/get_cover?" + params.Encode()
+}
+
+func coverKey(bookID, version, size string) string {
+	return fmt.Sprintf("covers/%s/%s/%s.jpg", bookID, version, size)
+}
+
+// encodeCover scales src to width as it is meant to be displayed, turning
+// it upright according to its EXIF orientation, since the re-encoded JPEG
+// carries no EXIF data of its own.
+func encodeCover(src image.Image, width, orientation int) ([]byte, error) {
+	bounds := src.Bounds()
+	displayWidth, displayHeight := bounds.Dx(), bounds.Dy()
+	transposed := orientation >= 5 && orientation <= 8
+	if transposed {
+		displayWidth, displayHeight = displayHeight, displayWidth
+	}
+	
+	if displayWidth < width {
+		width = displayWidth
+	}
+	height := displayHeight * width / displayWidth
+	if height < 1 {
+		height = 1
+	}
+	
+	scaledWidth, scaledHeight := width, height
+	if transposed {
+		scaledWidth, scaledHeight = height, width
+	}
+	
+	scaled := image.NewRGBA(image.Rect(0, 0, scaledWidth, scaledHeight))
+	draw.Draw(scaled, scaled.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), src, bounds, draw.Over, nil)
+	
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, orient(scaled, orientation), &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// orient applies an EXIF orientation (1-8) to img.
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	
+	// source returns the pixel of img that lands at (x, y) once upright.
+	source := func(x, y int) (int, int) {
+		switch orientation {
+		case 2:
+			return w - 1 - x, y
+		case 3:
+			return w - 1 - x, h - 1 - y
+		case 4:
+			return x, h - 1 - y
+		case 5:
+			return y, x
+		case 6:
+			return y, h - 1 - x
+		case 7:
+			return w - 1 - y, h - 1 - x
+		default:
+			return w - 1 - y, x
+		}
+	}
+	
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			sx, sy := source(x, y)
+			dst.SetRGBA(x, y, img.RGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
+
+// exifOrientation reads the EXIF orientation tag from a JPEG, PNG or WebP
+// file, returning 1 (upright) when there is none.
+func exifOrientation(data []byte) int {
+	var exif []byte
+	switch {
+	case len(data) > 4 && data[0] == 0xFF && data[1] == 0xD8:
+		for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+			marker := data[i+1]
+			length := int(binary.BigEndian.Uint16(data[i+2:]))
+			if marker == 0xDA || marker == 0xD9 || length < 2 || i+2+length > len(data) {
+				break
+			}
+			segment := data[i+4 : i+2+length]
+			if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+				exif = segment[6:]
+				break
+			}
+			i += 2 + length
+		}
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		for i := 8; i+12 <= len(data); {
+			length := int(binary.BigEndian.Uint32(data[i:]))
+			kind := string(data[i+4 : i+8])
+			if kind == "IDAT" || length < 0 || i+12+length > len(data) {
+				break
+			}
+			if kind == "eXIf" {
+				exif = data[i+8 : i+8+length]
+				break
+			}
+			i += 12 + length
+		}
+	case len(data) > 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		for i := 12; i+8 <= len(data); {
+			length := int(binary.LittleEndian.Uint32(data[i+4:]))
+			if length < 0 || i+8+length > len(data) {
+				break
+			}
+			if string(data[i:i+4]) == "EXIF" {
+				exif = bytes.TrimPrefix(data[i+8:i+8+length], []byte("Exif\x00\x00"))
+				break
+			}
+			i += 8 + length + length%2
+		}
+	}
+	return tiffOrientation(exif)
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	
+	entries := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < entries; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
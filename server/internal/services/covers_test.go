@@ -0,0 +1,176 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// sideways draws a 40x20 image that is red on the left and blue on the
+// right, as a camera held on its side would store a red-over-blue scene.
+func sideways() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			if x < 20 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	return img
+}
+
+// exifBlock builds a little-endian TIFF header with a single orientation tag.
+func exifBlock(orientation uint16) []byte {
+	tiff := []byte("II*\x00\x08\x00\x00\x00")
+	tiff = binary.LittleEndian.AppendUint16(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	return tiff
+}
+
+func jpegWithOrientation(t *testing.T, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, sideways(), nil); err != nil {
+		t.Fatal(err)
+	}
+	
+	segment := append([]byte("Exif\x00\x00"), exifBlock(orientation)...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+	
+	data := buf.Bytes()
+	return append(append(append([]byte{}, data[:2]...), app1...), data[2:]...)
+}
+
+func pngWithOrientation(t *testing.T, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sideways()); err != nil {
+		t.Fatal(err)
+	}
+	
+	exif := exifBlock(orientation)
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(exif)))
+	chunk = append(chunk, "eXIf"...)
+	chunk = append(chunk, exif...)
+	chunk = append(chunk, 0, 0, 0, 0)
+	
+	// Insert after the 8 byte signature and the 25 byte IHDR chunk.
+	data := buf.Bytes()
+	return append(append(append([]byte{}, data[:33]...), chunk...), data[33:]...)
+}
+
+func webpWithOrientation(orientation uint16) []byte {
+	exif := append([]byte("Exif\x00\x00"), exifBlock(orientation)...)
+	chunks := append([]byte("VP8X"), binary.LittleEndian.AppendUint32(nil, 10)...)
+	chunks = append(chunks, make([]byte, 10)...)
+	chunks = append(chunks, "EXIF"...)
+	chunks = binary.LittleEndian.AppendUint32(chunks, uint32(len(exif)))
+	chunks = append(chunks, exif...)
+	
+	data := append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(chunks)+4))...)
+	data = append(data, "WEBP"...)
+	return append(data, chunks...)
+}
+
+func TestExifOrientation(t *testing.T) {
+	var plain bytes.Buffer
+	jpeg.Encode(&plain, sideways(), nil)
+	
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{name: "jpeg", data: jpegWithOrientation(t, 6), want: 6},
+		{name: "jpeg without exif", data: plain.Bytes(), want: 1},
+		{name: "png", data: pngWithOrientation(t, 3), want: 3},
+		{name: "webp", data: webpWithOrientation(8), want: 8},
+		{name: "out of range", data: jpegWithOrientation(t, 9), want: 1},
+		{name: "truncated", data: jpegWithOrientation(t, 6)[:30], want: 1},
+		{name: "not an image", data: []byte("hello"), want: 1},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exifOrientation(tt.data); got != tt.want {
+				t.Errorf("orientation = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeCoverOrientation(t *testing.T) {
+	red := func(c color.Color) bool {
+		r, g, b, _ := c.RGBA()
+		return r > 0xC000 && g < 0x4000 && b < 0x4000
+	}
+	blue := func(c color.Color) bool {
+		r, g, b, _ := c.RGBA()
+		return b > 0xC000 && r < 0x4000 && g < 0x4000
+	}
+	
+	tests := []struct {
+		orientation   int
+		width, height int
+		first, second func(color.Color) bool // top/bottom or left/right
+		vertical      bool
+	}{
+		{orientation: 1, width: 40, height: 20, first: red, second: blue},
+		{orientation: 2, width: 40, height: 20, first: blue, second: red},
+		{orientation: 3, width: 40, height: 20, first: blue, second: red},
+		{orientation: 6, width: 20, height: 40, first: red, second: blue, vertical: true},
+		{orientation: 8, width: 20, height: 40, first: blue, second: red, vertical: true},
+	}
+	
+	for _, tt := range tests {
+		encoded, err := encodeCover(sideways(), 400, tt.orientation)
+		if err != nil {
+			t.Fatalf("orientation %d: %v", tt.orientation, err)
+		}
+		img, err := jpeg.Decode(bytes.NewReader(encoded))
+		if err != nil {
+			t.Fatalf("orientation %d: %v", tt.orientation, err)
+		}
+		
+		bounds := img.Bounds()
+		if bounds.Dx() != tt.width || bounds.Dy() != tt.height {
+			t.Errorf("orientation %d: size = %dx%d, want %dx%d", tt.orientation, bounds.Dx(), bounds.Dy(), tt.width, tt.height)
+			continue
+		}
+		
+		first, second := img.At(5, 5), img.At(tt.width-5, 5)
+		if tt.vertical {
+			second = img.At(5, tt.height-5)
+		}
+		if !tt.first(first) || !tt.second(second) {
+			t.Errorf("orientation %d: colours %v and %v are in the wrong place", tt.orientation, first, second)
+		}
+	}
+}
+
+func TestEncodeCoverScalesToDisplayedWidth(t *testing.T) {
+	encoded, err := encodeCover(sideways(), 10, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 10 || bounds.Dy() != 20 {
+		t.Errorf("size = %dx%d, want 10x20", bounds.Dx(), bounds.Dy())
+	}
+}
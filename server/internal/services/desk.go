@@ -0,0 +1,354 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionNotFound = errors.New("desk session not found")
+	ErrSessionClosed   = errors.New("desk session is closed")
+	ErrSessionNoPatron = errors.New("desk session has no patron")
+)
+
+func (s *CirculationService) OpenDeskSession(membershipID, deskLocation, branchID string, openedByID uuid.UUID) (*models.DeskSession, []models.CirculationAlert, error) {
+	branch, err := ResolveBranch(s.db, branchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	session := models.DeskSession{
+		ID:           uuid.New(),
+		OpenedByID:   openedByID,
+		DeskLocation: deskLocation,
+		BranchID:     &branch.ID,
+		Status:       models.DeskSessionOpen,
+	}
+	
+	var alerts []models.CirculationAlert
+	if membershipID != "" {
+		member, err := s.FindMemberByCard(membershipID)
+		if err != nil {
+			return nil, nil, err
+		}
+		session.MemberID = &member.ID
+		session.Member = member
+		alerts = s.PatronAlerts(member)
+	}
+	
+	if err := s.db.Create(&session).Error; err != nil {
+		return nil, nil, err
+	}
+	
+	return &session, alerts, nil
+}
+
+func (s *CirculationService) PatronAlerts(member *models.Member) []models.CirculationAlert {
+	var alerts []models.CirculationAlert
+	
+	if !member.IsActive {
+		alerts = append(alerts, models.CirculationAlert{Type: models.AlertBlocked, Message: "Membership is inactive"})
+	} else if member.IsExpired() {
+		alerts = append(alerts, models.CirculationAlert{Type: models.AlertBlocked, Message: "Membership has expired"})
+	}
+	
+	if !member.CanBorrowMore() && member.IsActive {
+		alerts = append(alerts, models.CirculationAlert{
+			Type:    models.AlertBlocked,
+			Message: fmt.Sprintf("Loan limit of %d reached", member.MaxBooksAllowed),
+		})
+	}
+	
+	if member.HasOutstandingFines() {
+		alerts = append(alerts, models.CirculationAlert{
+			Type:    models.AlertFines,
+			Message: fmt.Sprintf("Outstanding fines: %.2f", member.GetOutstandingFines()),
+		})
+	}
+	
+	var overdue int64
+	s.db.Model(&models.Loan{}).
+		Where("member_id = ? AND status IN ? AND due_date < ?", member.ID,
+			[]models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue}, time.Now()).
+		Count(&overdue)
+	if overdue > 0 {
+		alerts = append(alerts, models.CirculationAlert{
+			Type:    models.AlertOverdue,
+			Message: fmt.Sprintf("%d overdue item(s)", overdue),
+		})
+	}
+	
+	var holds []models.Reservation
+	s.db.Preload("Book").
+		Where("member_id = ? AND status = ?", member.ID, models.ReservationStatusReadyForPickup).
+		Find(&holds)
+	for _, hold := range holds {
+		alerts = append(alerts, models.CirculationAlert{
+			Type:    models.AlertHoldWaiting,
+			Message: fmt.Sprintf("Hold waiting: %s (%s)", hold.Book.Title, hold.PickupLocation),
+		})
+	}
+	
+	return alerts
+}
+
+func (s *CirculationService) DeskCheckout(sessionID uuid.UUID, items []string, issuedByID uuid.UUID) ([]models.DeskScanResult, error) {
+	session, err := s.openSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	
+	if session.MemberID == nil {
+		return nil, ErrSessionNoPatron
+	}
+	
+	results := make([]models.DeskScanResult, 0, len(items))
+	for _, code := range items {
+		result := models.DeskScanResult{ItemCode: code}
+		txn := models.DeskTransaction{SessionID: session.ID, Action: models.DeskActionCheckout, ItemCode: code}
+		
+		book, copyBarcode, err := s.FindCopy(code)
+		if err == nil {
+			result.BookID = book.ID.String()
+			result.Title = book.Title
+			txn.BookID = &book.ID
+			txn.Title = book.Title
+			
+			var checkout *CheckoutResult
+			checkout, err = s.Checkout(book.ID, *session.MemberID, issuedByID, sessionBranch(session), copyBarcode, time.Time{}, "")
+			if err == nil {
+				result.Success = true
+				result.LoanID = checkout.Loan.ID.String()
+				result.DueDate = &checkout.Loan.DueDate
+				txn.LoanID = &checkout.Loan.ID
+				txn.DueDate = &checkout.Loan.DueDate
+				if checkout.Reservation != nil {
+					result.Alerts = append(result.Alerts, models.CirculationAlert{
+						Type:    models.AlertHoldWaiting,
+						Message: "Hold collected from " + checkout.Reservation.PickupLocation,
+					})
+				}
+			}
+		}
+		
+		if err != nil {
+			result.Message = err.Error()
+			if errors.Is(err, ErrMemberInactive) || errors.Is(err, ErrLoanLimitReached) || errors.Is(err, ErrItemTypeLimit) {
+				result.Alerts = append(result.Alerts, models.CirculationAlert{Type: models.AlertBlocked, Message: err.Error()})
+			}
+		}
+		
+		txn.Success = result.Success
+		txn.Message = result.Message
+		s.db.Create(&txn)
+		results = append(results, result)
+	}
+	
+	return results, nil
+}
+
+func (s *CirculationService) DeskCheckin(sessionID uuid.UUID, items []string) ([]models.DeskScanResult, error) {
+	session, err := s.openSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	
+	memberID := uuid.Nil
+	if session.MemberID != nil {
+		memberID = *session.MemberID
+	}
+	
+	results := make([]models.DeskScanResult, 0, len(items))
+	for _, code := range items {
+		result := models.DeskScanResult{ItemCode: code}
+		txn := models.DeskTransaction{SessionID: session.ID, Action: models.DeskActionCheckin, ItemCode: code}
+		
+		book, loan, err := s.FindCheckinLoan(code, memberID)
+		if book != nil {
+			result.BookID = book.ID.String()
+			result.Title = book.Title
+			txn.BookID = &book.ID
+			txn.Title = book.Title
+			
+			if err == nil {
+				var returned *ReturnResult
+				returned, err = s.Return(loan.ID, sessionBranch(session), time.Time{})
+				if err == nil {
+					result.Success = true
+					result.LoanID = loan.ID.String()
+					txn.LoanID = &loan.ID
+					result.Alerts = s.checkinAlerts(session, returned)
+				}
+			}
+		}
+		
+		if err != nil {
+			result.Message = err.Error()
+		}
+		
+		txn.Success = result.Success
+		txn.Message = result.Message
+		s.db.Create(&txn)
+		results = append(results, result)
+	}
+	
+	return results, nil
+}
+
+func (s *CirculationService) checkinAlerts(session *models.DeskSession, returned *ReturnResult) []models.CirculationAlert {
+	var alerts []models.CirculationAlert
+	
+	if returned.Credit > 0 {
+		alerts = append(alerts, models.CirculationAlert{
+			Type:    models.AlertLostFound,
+			Message: fmt.Sprintf("Lost item found, %.2f credited to patron", returned.Credit),
+		})
+	}
+	
+	if returned.Loan.FineAmount > 0 {
+		alerts = append(alerts, models.CirculationAlert{
+			Type:    models.AlertOverdue,
+			Message: fmt.Sprintf("Returned late, fine %.2f", returned.Loan.FineAmount),
+		})
+	}
+	
+	if transfer := returned.Transfer; transfer != nil {
+		var branch models.Branch
+		s.db.First(&branch, "id = ?", transfer.ToBranchID)
+		alerts = append(alerts, models.CirculationAlert{
+			Type:    models.AlertRouteItem,
+			Message: "Send item in transit to " + branch.Name,
+		})
+	} else if hold := returned.TrappedHold; hold != nil {
+		alerts = append(alerts, models.CirculationAlert{
+			Type:    models.AlertHoldTrapped,
+			Message: "Place on hold shelf for pickup at " + hold.PickupLocation,
+		})
+		
+		if session.DeskLocation != "" && !strings.EqualFold(session.DeskLocation, hold.PickupLocation) {
+			alerts = append(alerts, models.CirculationAlert{
+				Type:    models.AlertRouteItem,
+				Message: "Route item to " + hold.PickupLocation,
+			})
+		}
+	}
+	
+	return alerts
+}
+
+func (s *CirculationService) CloseDeskSession(sessionID uuid.UUID) (*models.DeskSlip, error) {
+	session, err := s.openSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	
+	session.Close()
+	if err := s.db.Save(session).Error; err != nil {
+		return nil, err
+	}
+	
+	return s.DeskSlip(sessionID)
+}
+
+func (s *CirculationService) DeskSlip(sessionID uuid.UUID) (*models.DeskSlip, error) {
+	var session models.DeskSession
+	if err := s.db.Preload("Member.User").
+		Preload("Transactions", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		First(&session, "id = ?", sessionID).Error; err != nil {
+		return nil, ErrSessionNotFound
+	}
+	
+	slip := &models.DeskSlip{
+		SessionID:    session.ID.String(),
+		DeskLocation: session.DeskLocation,
+		OpenedAt:     session.OpenedAt,
+		ClosedAt:     session.ClosedAt,
+		CheckedOut:   []models.DeskTransaction{},
+		CheckedIn:    []models.DeskTransaction{},
+		Failed:       []models.DeskTransaction{},
+	}
+	
+	if session.Member != nil {
+		var member models.Member
+		if err := s.db.First(&member, "id = ?", session.Member.ID).Error; err == nil {
+			slip.Outstanding = member.GetOutstandingFines()
+		}
+		slip.MembershipID = session.Member.MembershipID
+		slip.PatronName = session.Member.User.FullName
+	}
+	
+	for _, txn := range session.Transactions {
+		switch {
+		case !txn.Success:
+			slip.Failed = append(slip.Failed, txn)
+		case txn.Action == models.DeskActionCheckout:
+			slip.CheckedOut = append(slip.CheckedOut, txn)
+		default:
+			slip.CheckedIn = append(slip.CheckedIn, txn)
+		}
+	}
+	
+	slip.Text = formatSlip(slip)
+	return slip, nil
+}
+
+func (s *CirculationService) openSession(sessionID uuid.UUID) (*models.DeskSession, error) {
+	var session models.DeskSession
+	if err := s.db.First(&session, "id = ?", sessionID).Error; err != nil {
+		return nil, ErrSessionNotFound
+	}
+	
+	if !session.IsOpen() {
+		return nil, ErrSessionClosed
+	}
+	
+	return &session, nil
+}
+
+func sessionBranch(session *models.DeskSession) uuid.UUID {
+	if session.BranchID == nil {
+		return uuid.Nil
+	}
+	return *session.BranchID
+}
+
+func formatSlip(slip *models.DeskSlip) string {
+	var b strings.Builder
+	
+	b.WriteString(slip.DeskLocation + "\n")
+	b.WriteString(slip.OpenedAt.Format("2006-01-02 15:04") + "\n")
+	if slip.PatronName != "" {
+		fmt.Fprintf(&b, "%s (%s)\n", slip.PatronName, slip.MembershipID)
+	}
+	
+	if len(slip.CheckedOut) > 0 {
+		b.WriteString("\nChecked out:\n")
+		for _, txn := range slip.CheckedOut {
+			due := ""
+			if txn.DueDate != nil {
+				due = txn.DueDate.Format("2006-01-02")
+			}
+			fmt.Fprintf(&b, "  %s  due %s\n", txn.Title, due)
+		}
+	}
+	
+	if len(slip.CheckedIn) > 0 {
+		b.WriteString("\nReturned:\n")
+		for _, txn := range slip.CheckedIn {
+			fmt.Fprintf(&b, "  %s\n", txn.Title)
+		}
+	}
+	
+	if slip.Outstanding > 0 {
+		fmt.Fprintf(&b, "\nOutstanding fines: %.2f\n", slip.Outstanding)
+	}
+	
+	return b.String()
+}
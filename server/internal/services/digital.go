@@ -0,0 +1,739 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrDigitalItemNotFound     = errors.New("digital item not found")
+	ErrDigitalItemInactive     = errors.New("digital item is inactive")
+	ErrDigitalFormat           = errors.New("format must be ebook or audiobook")
+	ErrDigitalNoFile           = errors.New("no file has been uploaded for this digital item")
+	ErrDigitalFileTooLarge     = errors.New("digital file exceeds the maximum upload size")
+	ErrDigitalFileUnsupported  = errors.New("unsupported digital file type")
+	ErrDigitalCheckedOut       = errors.New("member already has this title checked out")
+	ErrDigitalLimitReached     = errors.New("member has reached the digital checkout limit")
+	ErrDigitalNoLicense        = errors.New("all licenses for this title are in use, place a hold instead")
+	ErrDigitalLicenseAvailable = errors.New("a license is available, check the title out instead")
+	ErrDigitalHoldExists       = errors.New("member already has a hold on this title")
+	ErrDigitalCheckoutNotFound = errors.New("digital checkout not found")
+	ErrDigitalCheckoutEnded    = errors.New("digital checkout has been returned or has expired")
+	ErrDigitalHoldNotFound     = errors.New("digital hold not found")
+	ErrDigitalHoldClosed       = errors.New("digital hold is no longer open")
+	ErrDigitalNotOwner         = errors.New("digital checkout or hold belongs to another member")
+)
+
+type digitalFileType struct {
+	ContentType string
+	Format      models.DigitalFormat
+}
+
+var digitalFileTypes = map[string]digitalFileType{
+	".epub": {"application/epub+zip", models.DigitalFormatEbook},
+	".pdf":  {"application/pdf", models.DigitalFormatEbook},
+	".mp3":  {"audio/mpeg", models.DigitalFormatAudiobook},
+	".m4a":  {"audio/mp4", models.DigitalFormatAudiobook},
+	".m4b":  {"audio/mp4", models.DigitalFormatAudiobook},
+}
+
+type DigitalService struct {
+	db     *gorm.DB
+	store  storage.BlobStore
+	signer *storage.URLSigner
+	config *config.Config
+}
+
+func NewDigitalService(db *gorm.DB, store storage.BlobStore, cfg *config.Config) *DigitalService {
+	return &DigitalService{
+		db:     db,
+		store:  store,
+		signer: storage.NewURLSigner(cfg.Digital.SigningKey),
+		config: cfg,
+	}
+}
+
+func (s *DigitalService) Items(search, format string, includeInactive bool) ([]models.DigitalAvailability, error) {
+	query := s.db.Model(&models.DigitalItem{})
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+	if search != "" {
+		pattern := "%" + strings.ToLower(search) + "%"
+		query = query.Where("(LOWER(title) LIKE ? OR LOWER(author) LIKE ?)", pattern, pattern)
+	}
+	if format != "" {
+		query = query.Where("format = ?", format)
+	}
+	
+	var items []models.DigitalItem
+	if err := query.Order("title ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	
+	type itemCount struct {
+		DigitalItemID uuid.UUID
+		Status        string
+		Total         int64
+	}
+	
+	inUse := make(map[uuid.UUID]int64)
+	held := make(map[uuid.UUID]int64)
+	waiting := make(map[uuid.UUID]int64)
+	if len(ids) > 0 {
+		now := time.Now()
+		
+		var checkouts []itemCount
+		if err := s.db.Model(&models.DigitalCheckout{}).
+			Select("digital_item_id, COUNT(*) AS total").
+			Where("digital_item_id IN ? AND status = ? AND expires_at > ?", ids, models.DigitalCheckoutActive, now).
+			Group("digital_item_id").Scan(&checkouts).Error; err != nil {
+			return nil, err
+		}
+		for _, count := range checkouts {
+			inUse[count.DigitalItemID] = count.Total
+		}
+		
+		var holds []itemCount
+		if err := s.db.Model(&models.DigitalHold{}).
+			Select("digital_item_id, status, COUNT(*) AS total").
+			Where("digital_item_id IN ? AND (status = ? OR (status = ? AND expires_at > ?))",
+				ids, models.DigitalHoldWaiting, models.DigitalHoldAvailable, now).
+			Group("digital_item_id, status").Scan(&holds).Error; err != nil {
+			return nil, err
+		}
+		for _, count := range holds {
+			if count.Status == string(models.DigitalHoldWaiting) {
+				waiting[count.DigitalItemID] = count.Total
+			} else {
+				held[count.DigitalItemID] = count.Total
+			}
+		}
+	}
+	
+	result := make([]models.DigitalAvailability, 0, len(items))
+	for _, item := range items {
+		available := int64(item.Licenses) - inUse[item.ID] - held[item.ID]
+		if available < 0 {
+			available = 0
+		}
+		result = append(result, models.DigitalAvailability{
+			Item:          item,
+			LicensesInUse: inUse[item.ID],
+			Available:     available,
+			HoldsWaiting:  waiting[item.ID],
+		})
+	}
+	return result, nil
+}
+
+func (s *DigitalService) CreateItem(req models.DigitalItemRequest) (*models.DigitalItem, error) {
+	item := models.DigitalItem{IsActive: true}
+	if err := s.applyItemRequest(s.db, &item, req); err != nil {
+		return nil, err
+	}
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		return tx.Model(&item).Update("is_active", item.IsActive).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *DigitalService) UpdateItem(id string, req models.DigitalItemRequest) (*models.DigitalItem, error) {
+	var item models.DigitalItem
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, "id = ?", id).Error; err != nil {
+			return ErrDigitalItemNotFound
+		}
+		if err := s.applyItemRequest(tx, &item, req); err != nil {
+			return err
+		}
+		if err := tx.Save(&item).Error; err != nil {
+			return err
+		}
+		_, err := s.promote(tx, &item)
+		return err
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *DigitalService) Upload(ctx context.Context, itemID, fileName string, r io.Reader) (*models.DigitalItem, error) {
+	var item models.DigitalItem
+	if err := s.db.First(&item, "id = ?", itemID).Error; err != nil {
+		return nil, ErrDigitalItemNotFound
+	}
+	
+	ext := strings.ToLower(filepath.Ext(fileName))
+	fileType, ok := digitalFileTypes[ext]
+	if !ok {
+		return nil, fmt.Errorf("%w: use .epub, .pdf, .mp3, .m4a or .m4b", ErrDigitalFileUnsupported)
+	}
+	if fileType.Format != item.Format {
+		return nil, fmt.Errorf("%w: %s files cannot be attached to an %s", ErrDigitalFileUnsupported, ext, item.Format)
+	}
+	
+	data, err := io.ReadAll(io.LimitReader(r, s.config.Digital.MaxUploadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.config.Digital.MaxUploadSize {
+		return nil, ErrDigitalFileTooLarge
+	}
+	
+	sum := sha256.Sum256(data)
+	key := fmt.Sprintf("digital/%s/%s%s", item.ID, hex.EncodeToString(sum[:8]), ext)
+	if err := s.store.Put(ctx, key, data, fileType.ContentType); err != nil {
+		return nil, err
+	}
+	
+	previous := item.FileKey
+	item.FileKey = key
+	item.FileName = filepath.Base(fileName)
+	item.ContentType = fileType.ContentType
+	item.FileSize = int64(len(data))
+	
+	if err := s.db.Model(&item).Updates(map[string]interface{}{
+		"file_key":     item.FileKey,
+		"file_name":    item.FileName,
+		"content_type": item.ContentType,
+		"file_size":    item.FileSize,
+	}).Error; err != nil {
+		return nil, err
+	}
+	
+	if previous != "" && previous != key {
+		s.store.Delete(ctx, previous)
+	}
+	
+	return &item, nil
+}
+
+func (s *DigitalService) Checkout(itemID string, memberID uuid.UUID) (*models.DigitalCheckout, error) {
+	var checkout models.DigitalCheckout
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		item, err := s.lockItem(tx, itemID)
+		if err != nil {
+			return err
+		}
+		if !item.HasFile() {
+			return ErrDigitalNoFile
+		}
+		
+		member, err := s.lockMember(tx, memberID)
+		if err != nil {
+			return err
+		}
+		
+		if _, err := s.promote(tx, item); err != nil {
+			return err
+		}
+		
+		now := time.Now()
+		var existing int64
+		if err := tx.Model(&models.DigitalCheckout{}).
+			Where("digital_item_id = ? AND member_id = ? AND status = ? AND expires_at > ?", item.ID, member.ID, models.DigitalCheckoutActive, now).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrDigitalCheckedOut
+		}
+		
+		if limit := s.config.Digital.MaxCheckouts; limit > 0 {
+			var active int64
+			if err := tx.Model(&models.DigitalCheckout{}).
+				Where("member_id = ? AND status = ? AND expires_at > ?", member.ID, models.DigitalCheckoutActive, now).
+				Count(&active).Error; err != nil {
+				return err
+			}
+			if active >= int64(limit) {
+				return ErrDigitalLimitReached
+			}
+		}
+		
+		var hold models.DigitalHold
+		heldForMember := tx.Where("digital_item_id = ? AND member_id = ? AND status = ?", item.ID, member.ID, models.DigitalHoldAvailable).
+			First(&hold).Error == nil
+		if !heldForMember {
+			free, err := s.freeLicenses(tx, item, now)
+			if err != nil {
+				return err
+			}
+			if free < 1 {
+				return ErrDigitalNoLicense
+			}
+		}
+		
+		loanDays := item.LoanDays
+		if loanDays <= 0 {
+			loanDays = s.config.Digital.LoanDays
+		}
+		
+		checkout = models.DigitalCheckout{
+			DigitalItemID: item.ID,
+			MemberID:      member.ID,
+			CheckedOutAt:  now,
+			ExpiresAt:     now.AddDate(0, 0, loanDays),
+			Status:        models.DigitalCheckoutActive,
+		}
+		if err := tx.Omit("DigitalItem").Create(&checkout).Error; err != nil {
+			return err
+		}
+		
+		if err := tx.Model(&models.DigitalHold{}).
+			Where("digital_item_id = ? AND member_id = ? AND status IN ?", item.ID, member.ID,
+				[]models.DigitalHoldStatus{models.DigitalHoldWaiting, models.DigitalHoldAvailable}).
+			Updates(map[string]interface{}{"status": models.DigitalHoldFulfilled, "queue_position": 0}).Error; err != nil {
+			return err
+		}
+		if _, err := s.promote(tx, item); err != nil {
+			return err
+		}
+		
+		checkout.DigitalItem = *item
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &checkout, nil
+}
+
+func (s *DigitalService) Return(checkoutID string, memberID *uuid.UUID) (*models.DigitalCheckout, error) {
+	var checkout models.DigitalCheckout
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&checkout, "id = ?", checkoutID).Error; err != nil {
+			return ErrDigitalCheckoutNotFound
+		}
+		if memberID != nil && checkout.MemberID != *memberID {
+			return ErrDigitalNotOwner
+		}
+		if !checkout.IsActive() {
+			return ErrDigitalCheckoutEnded
+		}
+		
+		var item models.DigitalItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, "id = ?", checkout.DigitalItemID).Error; err != nil {
+			return ErrDigitalItemNotFound
+		}
+		
+		now := time.Now()
+		checkout.Status = models.DigitalCheckoutReturned
+		checkout.ReturnedAt = &now
+		if err := tx.Omit("DigitalItem").Save(&checkout).Error; err != nil {
+			return err
+		}
+		
+		_, err := s.promote(tx, &item)
+		return err
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &checkout, nil
+}
+
+func (s *DigitalService) Checkouts(itemID, memberID, status string) ([]models.DigitalCheckout, error) {
+	query := s.db.Preload("DigitalItem")
+	if itemID != "" {
+		query = query.Where("digital_item_id = ?", itemID)
+	}
+	if memberID != "" {
+		query = query.Where("member_id = ?", memberID)
+	}
+	switch models.DigitalCheckoutStatus(status) {
+	case "":
+	case models.DigitalCheckoutActive:
+		query = query.Where("status = ? AND expires_at > ?", status, time.Now())
+	case models.DigitalCheckoutExpired:
+		query = query.Where("(status = ? OR (status = ? AND expires_at <= ?))", status, models.DigitalCheckoutActive, time.Now())
+	default:
+		query = query.Where("status = ?", status)
+	}
+	
+	var checkouts []models.DigitalCheckout
+	err := query.Order("checked_out_at DESC").Find(&checkouts).Error
+	return checkouts, err
+}
+
+func (s *DigitalService) PlaceHold(itemID string, memberID uuid.UUID) (*models.DigitalHold, error) {
+	var hold models.DigitalHold
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		item, err := s.lockItem(tx, itemID)
+		if err != nil {
+			return err
+		}
+		
+		member, err := s.lockMember(tx, memberID)
+		if err != nil {
+			return err
+		}
+		
+		if _, err := s.promote(tx, item); err != nil {
+			return err
+		}
+		
+		now := time.Now()
+		var existing int64
+		if err := tx.Model(&models.DigitalCheckout{}).
+			Where("digital_item_id = ? AND member_id = ? AND status = ? AND expires_at > ?", item.ID, member.ID, models.DigitalCheckoutActive, now).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrDigitalCheckedOut
+		}
+		
+		if err := tx.Model(&models.DigitalHold{}).
+			Where("digital_item_id = ? AND member_id = ? AND status IN ?", item.ID, member.ID,
+				[]models.DigitalHoldStatus{models.DigitalHoldWaiting, models.DigitalHoldAvailable}).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrDigitalHoldExists
+		}
+		
+		free, err := s.freeLicenses(tx, item, now)
+		if err != nil {
+			return err
+		}
+		if free > 0 {
+			return ErrDigitalLicenseAvailable
+		}
+		
+		var waiting int64
+		if err := tx.Model(&models.DigitalHold{}).
+			Where("digital_item_id = ? AND status = ?", item.ID, models.DigitalHoldWaiting).
+			Count(&waiting).Error; err != nil {
+			return err
+		}
+		
+		hold = models.DigitalHold{
+			DigitalItemID: item.ID,
+			MemberID:      member.ID,
+			Status:        models.DigitalHoldWaiting,
+			QueuePosition: int(waiting) + 1,
+		}
+		if err := tx.Omit("DigitalItem").Create(&hold).Error; err != nil {
+			return err
+		}
+		
+		hold.DigitalItem = *item
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (s *DigitalService) CancelHold(holdID string, memberID *uuid.UUID) (*models.DigitalHold, error) {
+	var hold models.DigitalHold
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&hold, "id = ?", holdID).Error; err != nil {
+			return ErrDigitalHoldNotFound
+		}
+		if memberID != nil && hold.MemberID != *memberID {
+			return ErrDigitalNotOwner
+		}
+		if !hold.IsOpen() {
+			return ErrDigitalHoldClosed
+		}
+		
+		var item models.DigitalItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, "id = ?", hold.DigitalItemID).Error; err != nil {
+			return ErrDigitalItemNotFound
+		}
+		
+		hold.Status = models.DigitalHoldCancelled
+		hold.QueuePosition = 0
+		if err := tx.Omit("DigitalItem").Save(&hold).Error; err != nil {
+			return err
+		}
+		
+		_, err := s.promote(tx, &item)
+		return err
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (s *DigitalService) Holds(itemID, memberID, status string) ([]models.DigitalHold, error) {
+	query := s.db.Preload("DigitalItem")
+	if itemID != "" {
+		query = query.Where("digital_item_id = ?", itemID)
+	}
+	if memberID != "" {
+		query = query.Where("member_id = ?", memberID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	
+	var holds []models.DigitalHold
+	err := query.Order("created_at ASC").Find(&holds).Error
+	return holds, err
+}
+
+func (s *DigitalService) ExpireDue() (int, error) {
+	now := time.Now()
+	
+	var itemIDs []uuid.UUID
+	if err := s.db.Raw(`SELECT digital_item_id FROM digital_checkouts WHERE status = ? AND expires_at <= ?
+		UNION SELECT digital_item_id FROM digital_holds WHERE status = ? AND expires_at <= ?`,
+		models.DigitalCheckoutActive, now, models.DigitalHoldAvailable, now).Scan(&itemIDs).Error; err != nil {
+		return 0, err
+	}
+	
+	expired := 0
+	for _, itemID := range itemIDs {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var item models.DigitalItem
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, "id = ?", itemID).Error; err != nil {
+				return err
+			}
+			count, err := s.promote(tx, &item)
+			expired += count
+			return err
+		})
+		if err != nil {
+			return expired, err
+		}
+	}
+	return expired, nil
+}
+
+func (s *DigitalService) DownloadLink(checkoutID string, memberID uuid.UUID) (*models.DownloadLink, error) {
+	var checkout models.DigitalCheckout
+	if err := s.db.Preload("DigitalItem").First(&checkout, "id = ?", checkoutID).Error; err != nil {
+		return nil, ErrDigitalCheckoutNotFound
+	}
+	if checkout.MemberID != memberID {
+		return nil, ErrDigitalNotOwner
+	}
+	if !checkout.IsActive() {
+		return nil, ErrDigitalCheckoutEnded
+	}
+	if !checkout.DigitalItem.HasFile() {
+		return nil, ErrDigitalNoFile
+	}
+	
+	expires := time.Now().Add(s.config.Digital.LinkTTL)
+	if expires.After(checkout.ExpiresAt) {
+		expires = checkout.ExpiresAt
+	}
+	
+	params := url.Values{}
+	params.Set("checkout_id", checkout.ID.String())
+	params.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	params.Set("signature", s.signer.Sign(checkout.ID.String(), expires))
+	
+	return &models.DownloadLink{
+		CheckoutID: checkout.ID.String(),
+		URL:        s.config.APIPrefix + "/method/library_management.api.digital/download?" + params.Encode(),
+		ExpiresAt:  time.Unix(expires.Unix(), 0),
+		FileName:   checkout.DigitalItem.FileName,
+	}, nil
+}
+
+func (s *DigitalService) Open(ctx context.Context, checkoutID, expires, signature string) (io.ReadCloser, *storage.BlobInfo, *models.DigitalItem, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, nil, nil, storage.ErrLinkInvalid
+	}
+	if err := s.signer.Verify(checkoutID, expiresAt, signature); err != nil {
+		return nil, nil, nil, err
+	}
+	
+	var checkout models.DigitalCheckout
+	if err := s.db.Preload("DigitalItem").First(&checkout, "id = ?", checkoutID).Error; err != nil {
+		return nil, nil, nil, ErrDigitalCheckoutNotFound
+	}
+	if !checkout.IsActive() {
+		return nil, nil, nil, ErrDigitalCheckoutEnded
+	}
+	if !checkout.DigitalItem.HasFile() {
+		return nil, nil, nil, ErrDigitalNoFile
+	}
+	
+	body, info, err := s.store.Get(ctx, checkout.DigitalItem.FileKey)
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return nil, nil, nil, ErrDigitalNoFile
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	
+	s.db.Model(&checkout).UpdateColumns(map[string]interface{}{
+		"downloads":        gorm.Expr("downloads + 1"),
+		"last_download_at": time.Now(),
+	})
+	
+	return body, info, &checkout.DigitalItem, nil
+}
+
+func (s *DigitalService) applyItemRequest(tx *gorm.DB, item *models.DigitalItem, req models.DigitalItemRequest) error {
+	if !req.Format.Valid() {
+		return ErrDigitalFormat
+	}
+	if item.HasFile() && req.Format != item.Format {
+		return fmt.Errorf("%w: format cannot change once a file is uploaded", ErrDigitalFileUnsupported)
+	}
+	
+	item.BookID = nil
+	if req.BookID != "" {
+		var book models.Book
+		if err := tx.Select("id").First(&book, "id = ?", req.BookID).Error; err != nil {
+			return ErrBookNotFound
+		}
+		item.BookID = &book.ID
+	}
+	
+	item.Title = strings.TrimSpace(req.Title)
+	item.Author = strings.TrimSpace(req.Author)
+	item.Format = req.Format
+	item.Publisher = req.Publisher
+	item.Language = req.Language
+	item.Description = req.Description
+	item.Licenses = req.Licenses
+	item.LoanDays = req.LoanDays
+	if item.Language == "" {
+		item.Language = "en"
+	}
+	if item.Licenses == 0 {
+		item.Licenses = 1
+	}
+	if req.IsActive != nil {
+		item.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+func (s *DigitalService) lockItem(tx *gorm.DB, itemID string) (*models.DigitalItem, error) {
+	var item models.DigitalItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, "id = ?", itemID).Error; err != nil {
+		return nil, ErrDigitalItemNotFound
+	}
+	if !item.IsActive {
+		return nil, ErrDigitalItemInactive
+	}
+	return &item, nil
+}
+
+func (s *DigitalService) lockMember(tx *gorm.DB, memberID uuid.UUID) (*models.Member, error) {
+	var member models.Member
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&member, "id = ?", memberID).Error; err != nil {
+		return nil, ErrMemberNotFound
+	}
+	if !member.IsActive || member.IsExpired() {
+		return nil, ErrMemberInactive
+	}
+	return &member, nil
+}
+
+func (s *DigitalService) freeLicenses(tx *gorm.DB, item *models.DigitalItem, now time.Time) (int, error) {
+	var inUse, held int64
+	if err := tx.Model(&models.DigitalCheckout{}).
+		Where("digital_item_id = ? AND status = ? AND expires_at > ?", item.ID, models.DigitalCheckoutActive, now).
+		Count(&inUse).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&models.DigitalHold{}).
+		Where("digital_item_id = ? AND status = ? AND expires_at > ?", item.ID, models.DigitalHoldAvailable, now).
+		Count(&held).Error; err != nil {
+		return 0, err
+	}
+	return item.Licenses - int(inUse) - int(held), nil
+}
+
+func (s *DigitalService) promote(tx *gorm.DB, item *models.DigitalItem) (int, error) {
+	now := time.Now()
+	
+	result := tx.Model(&models.DigitalCheckout{}).
+		Where("digital_item_id = ? AND status = ? AND expires_at <= ?", item.ID, models.DigitalCheckoutActive, now).
+		Update("status", models.DigitalCheckoutExpired)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	expired := int(result.RowsAffected)
+	
+	if err := tx.Model(&models.DigitalHold{}).
+		Where("digital_item_id = ? AND status = ? AND expires_at <= ?", item.ID, models.DigitalHoldAvailable, now).
+		Updates(map[string]interface{}{"status": models.DigitalHoldExpired, "queue_position": 0}).Error; err != nil {
+		return expired, err
+	}
+	
+	free, err := s.freeLicenses(tx, item, now)
+	if err != nil {
+		return expired, err
+	}
+	
+	var waiting []models.DigitalHold
+	if err := tx.Where("digital_item_id = ? AND status = ?", item.ID, models.DigitalHoldWaiting).
+		Order("created_at ASC").Find(&waiting).Error; err != nil {
+		return expired, err
+	}
+	
+	holdUntil := now.AddDate(0, 0, s.config.Digital.HoldDays)
+	position := 0
+	for i := range waiting {
+		hold := &waiting[i]
+		if item.IsActive && free > 0 {
+			free--
+			hold.Status = models.DigitalHoldAvailable
+			hold.QueuePosition = 0
+			hold.AvailableAt = &now
+			hold.ExpiresAt = &holdUntil
+		} else {
+			position++
+			if hold.QueuePosition == position {
+				continue
+			}
+			hold.QueuePosition = position
+		}
+		if err := tx.Omit("DigitalItem").Save(hold).Error; err != nil {
+			return expired, err
+		}
+	}
+	return expired, nil
+}
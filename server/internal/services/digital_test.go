@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestDigitalLicensesAndHolds(t *testing.T) {
+	db, cfg := testDB(t)
+	cfg.Digital.MaxCheckouts = 3
+	cfg.Digital.HoldDays = 3
+	digital := NewDigitalService(db, nil, cfg)
+	
+	item := models.DigitalItem{Title: "Digital Test " + uuid.New().String()[:8], Format: models.DigitalFormatEbook, Licenses: 1, FileKey: "digital/test.epub", IsActive: true}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	itemID := item.ID.String()
+	
+	first := createMember(t, db, "first", false)
+	second := createMember(t, db, "second", false)
+	third := createMember(t, db, "third", false)
+	late := createMember(t, db, "late", false)
+	
+	holdStatus := func(t *testing.T, hold *models.DigitalHold) models.DigitalHold {
+		t.Helper()
+		var current models.DigitalHold
+		if err := db.First(&current, "id = ?", hold.ID).Error; err != nil {
+			t.Fatalf("reload hold: %v", err)
+		}
+		return current
+	}
+	
+	checkout, err := digital.Checkout(itemID, first.ID)
+	if err != nil {
+		t.Fatalf("checkout of the only license: %v", err)
+	}
+	if _, err := digital.Checkout(itemID, second.ID); !errors.Is(err, ErrDigitalNoLicense) {
+		t.Fatalf("second checkout error = %v, want %v", err, ErrDigitalNoLicense)
+	}
+	
+	secondHold, err := digital.PlaceHold(itemID, second.ID)
+	if err != nil {
+		t.Fatalf("place hold: %v", err)
+	}
+	thirdHold, err := digital.PlaceHold(itemID, third.ID)
+	if err != nil {
+		t.Fatalf("place hold: %v", err)
+	}
+	if secondHold.QueuePosition != 1 || thirdHold.QueuePosition != 2 {
+		t.Fatalf("queue positions = %d, %d, want 1, 2", secondHold.QueuePosition, thirdHold.QueuePosition)
+	}
+	if _, err := digital.PlaceHold(itemID, second.ID); !errors.Is(err, ErrDigitalHoldExists) {
+		t.Fatalf("duplicate hold error = %v, want %v", err, ErrDigitalHoldExists)
+	}
+	
+	if _, err := digital.Return(checkout.ID.String(), &first.ID); err != nil {
+		t.Fatalf("return: %v", err)
+	}
+	if hold := holdStatus(t, secondHold); hold.Status != models.DigitalHoldAvailable || hold.ExpiresAt == nil {
+		t.Fatalf("first hold after return = %q, want %q with a deadline", hold.Status, models.DigitalHoldAvailable)
+	}
+	if hold := holdStatus(t, thirdHold); hold.Status != models.DigitalHoldWaiting || hold.QueuePosition != 1 {
+		t.Fatalf("second hold after return = %q at %d, want waiting at 1", hold.Status, hold.QueuePosition)
+	}
+	
+	// The freed license is set aside for the member at the head of the queue.
+	if _, err := digital.Checkout(itemID, late.ID); !errors.Is(err, ErrDigitalNoLicense) {
+		t.Fatalf("checkout past the queue error = %v, want %v", err, ErrDigitalNoLicense)
+	}
+	secondCheckout, err := digital.Checkout(itemID, second.ID)
+	if err != nil {
+		t.Fatalf("checkout of a held license: %v", err)
+	}
+	if hold := holdStatus(t, secondHold); hold.Status != models.DigitalHoldFulfilled {
+		t.Fatalf("hold after checkout = %q, want %q", hold.Status, models.DigitalHoldFulfilled)
+	}
+	
+	// An expired checkout frees its license for the next hold.
+	db.Model(&models.DigitalCheckout{}).Where("id = ?", secondCheckout.ID).Update("expires_at", time.Now().Add(-time.Minute))
+	if _, err := digital.ExpireDue(); err != nil {
+		t.Fatalf("expire due: %v", err)
+	}
+	var expired models.DigitalCheckout
+	db.First(&expired, "id = ?", secondCheckout.ID)
+	if expired.Status != models.DigitalCheckoutExpired {
+		t.Fatalf("checkout past its loan period = %q, want %q", expired.Status, models.DigitalCheckoutExpired)
+	}
+	if hold := holdStatus(t, thirdHold); hold.Status != models.DigitalHoldAvailable {
+		t.Fatalf("next hold after expiry = %q, want %q", hold.Status, models.DigitalHoldAvailable)
+	}
+	
+	// A hold that is not picked up lapses and the license returns to the pool.
+	db.Model(&models.DigitalHold{}).Where("id = ?", thirdHold.ID).Update("expires_at", time.Now().Add(-time.Minute))
+	if _, err := digital.ExpireDue(); err != nil {
+		t.Fatalf("expire due: %v", err)
+	}
+	if hold := holdStatus(t, thirdHold); hold.Status != models.DigitalHoldExpired {
+		t.Fatalf("lapsed hold = %q, want %q", hold.Status, models.DigitalHoldExpired)
+	}
+	
+	items, err := digital.Items(item.Title, "", false)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("items = %d, %v", len(items), err)
+	}
+	if items[0].Available != 1 || items[0].LicensesInUse != 0 || items[0].HoldsWaiting != 0 {
+		t.Fatalf("availability %+v, want the one license free", items[0])
+	}
+	
+	if _, err := digital.Checkout(itemID, late.ID); err != nil {
+		t.Fatalf("checkout after the queue cleared: %v", err)
+	}
+}
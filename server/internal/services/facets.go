@@ -0,0 +1,137 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+const (
+	FacetCategory     = "category"
+	FacetAuthor       = "author"
+	FacetPublisher    = "publisher"
+	FacetTag          = "tag"
+	FacetDecade       = "decade"
+	FacetLanguage     = "language"
+	FacetAvailability = "availability"
+)
+
+const (
+	AvailabilityAvailable   = "available"
+	AvailabilityUnavailable = "unavailable"
+)
+
+const availabilityExpr = "CASE WHEN books.available_copies > 0 AND books.status = 'available' THEN 'available' ELSE 'unavailable' END"
+
+const decadeExpr = "(EXTRACT(YEAR FROM books.publish_date)::int / 10 * 10)"
+
+type FacetFilters struct {
+	Search       string
+	Language     string
+	Categories   []string
+	Authors      []string
+	Publishers   []string
+	Tags         []string
+	TagsMatchAll bool
+	Decades      []int
+	Languages    []string
+	Availability []string
+}
+
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+func (s *CatalogService) FilterBooks(query *gorm.DB, f FacetFilters) *gorm.DB {
+	return s.applyFacetFilters(query, f, "")
+}
+
+func (s *CatalogService) applyFacetFilters(query *gorm.DB, f FacetFilters, except string) *gorm.DB {
+	if f.Search != "" {
+		query = MatchBooks(query, f.Search, f.Language)
+	}
+	
+	if len(f.Categories) > 0 && except != FacetCategory {
+		query = query.Where("books.category IN ?", f.Categories)
+	}
+	
+	if len(f.Authors) > 0 && except != FacetAuthor {
+		query = query.Where("books.author IN ?", f.Authors)
+	}
+	
+	if len(f.Publishers) > 0 && except != FacetPublisher {
+		query = query.Where("books.publisher IN ?", f.Publishers)
+	}
+	
+	if len(f.Tags) > 0 && except != FacetTag {
+		if f.TagsMatchAll {
+			query = query.Where("books.tags @> ?", pq.StringArray(f.Tags))
+		} else {
+			query = query.Where("books.tags && ?", pq.StringArray(f.Tags))
+		}
+	}
+	
+	if len(f.Decades) > 0 && except != FacetDecade {
+		query = query.Where(decadeExpr+" IN ?", f.Decades)
+	}
+	
+	if len(f.Languages) > 0 && except != FacetLanguage {
+		query = query.Where("books.language IN ?", f.Languages)
+	}
+	
+	if len(f.Availability) > 0 && except != FacetAvailability {
+		query = query.Where(availabilityExpr+" IN ?", f.Availability)
+	}
+	
+	return query
+}
+
+func (s *CatalogService) Facets(f FacetFilters, size int) (map[string][]FacetValue, error) {
+	columns := map[string]struct {
+		expr  string
+		where string
+	}{
+		FacetCategory:     {"books.category", "books.category <> ''"},
+		FacetAuthor:       {"books.author", "books.author <> ''"},
+		FacetPublisher:    {"books.publisher", "books.publisher <> ''"},
+		FacetTag:          {"unnest(books.tags)", "books.tags IS NOT NULL"},
+		FacetDecade:       {decadeExpr + "::text", "EXTRACT(YEAR FROM books.publish_date) > 1"},
+		FacetLanguage:     {"books.language", "books.language <> ''"},
+		FacetAvailability: {availabilityExpr, "TRUE"},
+	}
+	
+	facets := make(map[string][]FacetValue, len(columns))
+	for name, column := range columns {
+		values := []FacetValue{}
+		query := s.applyFacetFilters(s.db.Model(&models.Book{}), f, name).
+			Select(column.expr + " AS value, COUNT(*) AS count").
+			Where(column.where).
+			Group("value").
+			Order("count DESC, value ASC")
+		
+		if name != FacetDecade && name != FacetAvailability {
+			query = query.Limit(size)
+		}
+		
+		if err := query.Scan(&values).Error; err != nil {
+			return nil, err
+		}
+		facets[name] = values
+	}
+	
+	return facets, nil
+}
+
+func ParseDecades(values []string) []int {
+	decades := make([]int, 0, len(values))
+	for _, v := range values {
+		if d, err := strconv.Atoi(v); err == nil {
+			decades = append(decades, d/10*10)
+		}
+	}
+	return decades
+}
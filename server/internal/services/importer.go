@@ -0,0 +1,829 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/marc"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/pkg/isbn"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnsupportedImportFormat = errors.New("unsupported import format, expected csv, json, marc or marcxml")
+	ErrInvalidDuplicatePolicy  = errors.New("invalid duplicate policy, expected skip, update or merge")
+	ErrImportEmpty             = errors.New("import file contains no rows")
+	ErrImportTooLarge          = errors.New("import file exceeds the maximum number of rows")
+	ErrImportJobNotFound       = errors.New("import job not found")
+	ErrImportInterrupted       = errors.New("import was interrupted by a server restart")
+	ErrImportTitleConflict     = errors.New("title is already catalogued under another barcode, give the row that item's barcode or ISBN to match it")
+)
+
+const importProgressBatch = 25
+
+var importFields = []string{
+	"title", "author", "isbn", "barcode", "publisher", "publish_date", "category", "language",
+	"description", "cover_image", "total_copies", "available_copies", "replacement_cost", "location", "tags",
+	"series", "series_volume", "call_number", "classification", "item_type", "attributes",
+}
+
+type ImportOptions struct {
+	Format          string
+	FileName        string
+	DuplicatePolicy models.DuplicatePolicy
+	DryRun          bool
+	StartedByID     uuid.UUID
+}
+
+type ImportRecord struct {
+	Number int
+	Fields map[string]interface{}
+}
+
+type ImportService struct {
+	db          *gorm.DB
+	redis       *redis.Client
+	config      *config.Config
+	authorities *AuthorityService
+	locations   *LocationService
+	suggestions *PurchaseSuggestionService
+}
+
+func NewImportService(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *ImportService {
+	return &ImportService{
+		db:          db,
+		redis:       redisClient,
+		config:      cfg,
+		authorities: NewAuthorityService(db, redisClient),
+		locations:   NewLocationService(db, cfg),
+		suggestions: NewPurchaseSuggestionService(db, cfg),
+	}
+}
+
+func (s *ImportService) Parse(format string, r io.Reader, mapping map[string]string) ([]ImportRecord, error) {
+	var records []ImportRecord
+	var err error
+	
+	switch format {
+	case "csv":
+		records, err = parseCSVImport(r, mapping)
+	case "json":
+		records, err = parseJSONImport(r, mapping)
+	case "marc":
+		records, err = parseMARCImport(marc.NewReader(r).ReadAll())
+	case "marcxml":
+		records, err = parseMARCImport(marc.ReadXML(r))
+	default:
+		return nil, ErrUnsupportedImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	
+	if len(records) == 0 {
+		return nil, ErrImportEmpty
+	}
+	
+	if len(records) > s.config.Import.MaxRows {
+		return nil, ErrImportTooLarge
+	}
+	
+	return records, nil
+}
+
+func (s *ImportService) Start(records []ImportRecord, opts ImportOptions) (*models.ImportJob, bool, error) {
+	switch opts.DuplicatePolicy {
+	case models.DuplicateSkip, models.DuplicateUpdate, models.DuplicateMerge:
+	default:
+		return nil, false, ErrInvalidDuplicatePolicy
+	}
+	
+	job := &models.ImportJob{
+		Format:          opts.Format,
+		FileName:        opts.FileName,
+		DuplicatePolicy: opts.DuplicatePolicy,
+		DryRun:          opts.DryRun,
+		Status:          models.ImportJobQueued,
+		TotalRows:       len(records),
+		StartedByID:     opts.StartedByID,
+	}
+	
+	if err := s.db.Create(job).Error; err != nil {
+		return nil, false, err
+	}
+	
+	if len(records) > s.config.Import.BackgroundThreshold {
+		queued := *job
+		go s.run(job, records)
+		return &queued, true, nil
+	}
+	
+	s.run(job, records)
+	return job, false, nil
+}
+
+// FailInterrupted marks jobs left queued or running by a previous process
+// as failed. Background imports run in a goroutine of the server that
+// started them, so nothing will pick them up again after a restart. Call it
+// before the server takes requests.
+func (s *ImportService) FailInterrupted() (int64, error) {
+	result := s.db.Model(&models.ImportJob{}).
+		Where("status IN ?", []models.ImportJobStatus{models.ImportJobQueued, models.ImportJobRunning}).
+		Updates(map[string]interface{}{
+			"status":       models.ImportJobFailed,
+			"error":        ErrImportInterrupted.Error(),
+			"completed_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (s *ImportService) Job(id uuid.UUID) (*models.ImportJob, error) {
+	var job models.ImportJob
+	if err := s.db.First(&job, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImportJobNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *ImportService) JobRows(id uuid.UUID, action string, limit, offset int) ([]models.ImportRow, int64, error) {
+	query := s.db.Model(&models.ImportRow{}).Where("job_id = ?", id)
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	
+	var total int64
+	query.Count(&total)
+	
+	var rows []models.ImportRow
+	err := query.Order("row_number ASC").Limit(limit).Offset(offset).Find(&rows).Error
+	return rows, total, err
+}
+
+func (s *ImportService) run(job *models.ImportJob, records []ImportRecord) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.finish(job, fmt.Errorf("import aborted: %v", r))
+		}
+	}()
+	
+	now := time.Now()
+	job.Status = models.ImportJobRunning
+	job.StartedAt = &now
+	s.db.Model(job).Updates(map[string]interface{}{
+		"status":     job.Status,
+		"started_at": job.StartedAt,
+	})
+	
+	seen := make(map[string]bool)
+	batch := make([]models.ImportRow, 0, importProgressBatch)
+	for _, record := range records {
+		row := s.importRecord(job, record, seen)
+		job.Record(row.Action)
+		batch = append(batch, row)
+		
+		if len(batch) == importProgressBatch {
+			if err := s.flush(job, batch); err != nil {
+				s.finish(job, err)
+				return
+			}
+			batch = batch[:0]
+		}
+	}
+	
+	if err := s.flush(job, batch); err != nil {
+		s.finish(job, err)
+		return
+	}
+	
+	s.finish(job, nil)
+}
+
+func (s *ImportService) flush(job *models.ImportJob, rows []models.ImportRow) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if len(rows) > 0 {
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+		
+		return tx.Model(job).Updates(map[string]interface{}{
+			"processed_rows": job.ProcessedRows,
+			"created_count":  job.CreatedCount,
+			"updated_count":  job.UpdatedCount,
+			"merged_count":   job.MergedCount,
+			"skipped_count":  job.SkippedCount,
+			"invalid_count":  job.InvalidCount,
+			"failed_count":   job.FailedCount,
+		}).Error
+	})
+}
+
+func (s *ImportService) finish(job *models.ImportJob, err error) {
+	now := time.Now()
+	job.CompletedAt = &now
+	job.Status = models.ImportJobCompleted
+	if err != nil {
+		job.Status = models.ImportJobFailed
+		job.Error = err.Error()
+	}
+	
+	s.db.Model(job).Updates(map[string]interface{}{
+		"status":       job.Status,
+		"error":        job.Error,
+		"completed_at": job.CompletedAt,
+	})
+	
+	if !job.DryRun && job.CreatedCount+job.UpdatedCount+job.MergedCount > 0 {
+		invalidateAutocomplete(context.Background(), s.redis)
+	}
+}
+
+func (s *ImportService) importRecord(job *models.ImportJob, record ImportRecord, seen map[string]bool) models.ImportRow {
+	row := models.ImportRow{
+		JobID:     job.ID,
+		RowNumber: record.Number,
+	}
+	
+	req, problems := bookRequestFromFields(record.Fields)
+	row.ISBN = req.ISBN
+	row.Title = req.Title
+	
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		problems = append(problems, strings.Split(err.Error(), "\n")...)
+	}
+	
+	itemType, attributes, err := CleanItemAttributes(req.ItemType, req.Attributes)
+	switch {
+	case errors.Is(err, ErrItemType):
+		problems = append(problems, "item_type: "+err.Error())
+	case err != nil:
+		problems = append(problems, "attributes: "+err.Error())
+	}
+	req.ItemType, req.Attributes = itemType, attributes
+	
+	if req.ISBN != "" {
+		normalized, err := isbn.Normalize(req.ISBN)
+		if err != nil {
+			problems = append(problems, "isbn: "+err.Error())
+		} else {
+			req.ISBN = normalized
+			row.ISBN = normalized
+		}
+	}
+	
+	if _, err := ParseCallNumber(req.CallNumber, req.Classification, s.config.Library.CallNumberScheme); err != nil {
+		problems = append(problems, "call_number: "+err.Error())
+	}
+	
+	if len(problems) > 0 {
+		row.Action = models.ImportRowInvalid
+		row.Errors = problems
+		return row
+	}
+	
+	incoming := s.bookFromRequest(req)
+	
+	// Records without an ISBN are matched by barcode only. A title is not
+	// enough to tell two copies or editions apart, so a row whose title is
+	// already catalogued under another barcode, or under none, is reported
+	// instead of being written over that item.
+	var key string
+	query := s.db.Model(&models.Book{})
+	switch {
+	case req.ISBN != "":
+		key = "isbn:" + req.ISBN
+		query = query.Where("isbn = ?", req.ISBN)
+	case req.Barcode != "":
+		key = "barcode:" + req.Barcode
+		query = query.Where("barcode = ?", req.Barcode)
+	}
+	
+	var existing models.Book
+	found := false
+	if key != "" {
+		err = query.First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			row.Action = models.ImportRowFailed
+			row.Errors = []string{err.Error()}
+			return row
+		}
+		found = err == nil
+	}
+	
+	titleKey := "title:" + string(req.ItemType) + ":" + strings.ToLower(req.Title)
+	if req.ISBN == "" && !found && !(job.DryRun && seen[key]) {
+		var titled int64
+		if err := s.db.Model(&models.Book{}).
+			Where("LOWER(title) = LOWER(?) AND item_type = ?", req.Title, req.ItemType).
+			Count(&titled).Error; err != nil {
+			row.Action = models.ImportRowFailed
+			row.Errors = []string{err.Error()}
+			return row
+		}
+		if titled > 0 || (job.DryRun && seen[titleKey]) {
+			row.Action = models.ImportRowInvalid
+			row.Errors = []string{"barcode: " + ErrImportTitleConflict.Error()}
+			return row
+		}
+	}
+	
+	duplicate := found || (job.DryRun && key != "" && seen[key])
+	if key != "" {
+		seen[key] = true
+	}
+	seen[titleKey] = true
+	
+	bookID := uuid.Nil
+	if found {
+		bookID = existing.ID
+	}
+	if err := CheckBarcodes(s.db, bookID, req.Barcode); err != nil {
+		row.Action = models.ImportRowInvalid
+		row.Errors = []string{"barcode: " + err.Error()}
+		return row
+	}
+	
+	if !duplicate {
+		row.Action = models.ImportRowCreated
+		if !job.DryRun {
+			err := s.db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Create(&incoming).Error; err != nil {
+					return err
+				}
+				if err := SyncHoldings(tx, &incoming, uuid.Nil); err != nil {
+					return err
+				}
+				if err := s.placeImported(tx, &incoming); err != nil {
+					return err
+				}
+				if err := s.authorities.LinkBook(tx, &incoming, req); err != nil {
+					return err
+				}
+				branchID, err := s.locations.PrimaryBranch(tx, incoming.ID)
+				if err != nil {
+					return err
+				}
+				_, err = s.suggestions.Fulfill(tx, &incoming, branchID)
+				return err
+			})
+			if err != nil {
+				row.Action = models.ImportRowFailed
+				row.Errors = []string{err.Error()}
+				return row
+			}
+			row.BookID = &incoming.ID
+		}
+		return row
+	}
+	
+	if found {
+		row.BookID = &existing.ID
+	}
+	
+	links := models.BookRequest{}
+	previousLocation := existing.Location
+	previousCallNumber := existing.CallNumberSort
+	switch job.DuplicatePolicy {
+	case models.DuplicateUpdate:
+		if found && existing.ItemType != incoming.ItemType && ItemInUse(s.db, existing.ID) {
+			row.Action = models.ImportRowInvalid
+			row.Errors = []string{"item_type: " + ErrItemTypeInUse.Error()}
+			return row
+		}
+		row.Action = models.ImportRowUpdated
+		updateImportedBook(&existing, incoming)
+		links = req
+	case models.DuplicateMerge:
+		row.Action = models.ImportRowMerged
+		mergeImportedBook(&existing, incoming)
+	default:
+		row.Action = models.ImportRowSkipped
+		return row
+	}
+	
+	if !job.DryRun {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&existing).Error; err != nil {
+				return err
+			}
+			if err := SyncHoldings(tx, &existing, uuid.Nil); err != nil {
+				return err
+			}
+			if existing.Location != previousLocation || existing.CallNumberSort != previousCallNumber {
+				if err := s.placeImported(tx, &existing); err != nil {
+					return err
+				}
+			}
+			return s.authorities.LinkBook(tx, &existing, links)
+		})
+		if err != nil {
+			row.Action = models.ImportRowFailed
+			row.Errors = []string{err.Error()}
+		}
+	}
+	
+	return row
+}
+
+func (s *ImportService) placeImported(tx *gorm.DB, book *models.Book) error {
+	branchID, err := s.locations.PrimaryBranch(tx, book.ID)
+	if err != nil {
+		return err
+	}
+	
+	location, err := s.locations.Resolve(tx, branchID, "", book.Location)
+	if err == nil && location == nil {
+		location, err = s.locations.ShelfFor(tx, branchID, book.CallNumberSort)
+	}
+	if err != nil || location == nil {
+		return err
+	}
+	return s.locations.Place(tx, book.ID, branchID, location)
+}
+
+func (s *ImportService) bookFromRequest(req models.BookRequest) models.Book {
+	book := models.Book{
+		ID:              uuid.New(),
+		Title:           req.Title,
+		Author:          req.Author,
+		ISBN:            req.ISBN,
+		Barcode:         req.Barcode,
+		Publisher:       req.Publisher,
+		PublishDate:     req.PublishDate,
+		Category:        req.Category,
+		Language:        req.Language,
+		Description:     req.Description,
+		CoverImage:      req.CoverImage,
+		TotalCopies:     req.TotalCopies,
+		AvailableCopies: req.AvailableCopies,
+		ReplacementCost: req.ReplacementCost,
+		Location:        req.Location,
+		Status:          models.BookStatusAvailable,
+		ItemType:        req.ItemType,
+		Attributes:      req.Attributes,
+		Tags:            req.Tags,
+	}
+	
+	if book.AvailableCopies == 0 || book.AvailableCopies > book.TotalCopies {
+		book.AvailableCopies = book.TotalCopies
+	}
+	
+	if book.Language == "" {
+		book.Language = s.config.Search.DefaultLanguage
+	}
+	
+	if callNumber, err := ParseCallNumber(req.CallNumber, req.Classification, s.config.Library.CallNumberScheme); err == nil {
+		ApplyCallNumber(&book, callNumber)
+	}
+	
+	return book
+}
+
+func updateImportedBook(existing *models.Book, incoming models.Book) {
+	onLoan := existing.TotalCopies - existing.AvailableCopies
+	
+	existing.Title = incoming.Title
+	existing.Author = incoming.Author
+	existing.Barcode = incoming.Barcode
+	existing.Publisher = incoming.Publisher
+	existing.PublishDate = incoming.PublishDate
+	existing.Category = incoming.Category
+	existing.Language = incoming.Language
+	existing.Description = incoming.Description
+	existing.CoverImage = incoming.CoverImage
+	existing.ReplacementCost = incoming.ReplacementCost
+	existing.Location = incoming.Location
+	existing.CallNumber = incoming.CallNumber
+	existing.Classification = incoming.Classification
+	existing.CallNumberSort = incoming.CallNumberSort
+	existing.Tags = incoming.Tags
+	existing.ItemType = incoming.ItemType
+	existing.Attributes = incoming.Attributes
+	existing.TotalCopies = incoming.TotalCopies
+	existing.AvailableCopies = incoming.TotalCopies - onLoan
+	if existing.AvailableCopies < 0 {
+		existing.AvailableCopies = 0
+	}
+}
+
+func mergeImportedBook(existing *models.Book, incoming models.Book) {
+	fill := func(current *string, value string) {
+		if *current == "" {
+			*current = value
+		}
+	}
+	
+	fill(&existing.Barcode, incoming.Barcode)
+	fill(&existing.Publisher, incoming.Publisher)
+	fill(&existing.Category, incoming.Category)
+	fill(&existing.Description, incoming.Description)
+	fill(&existing.CoverImage, incoming.CoverImage)
+	fill(&existing.Location, incoming.Location)
+	
+	if existing.CallNumber == "" {
+		existing.CallNumber = incoming.CallNumber
+		existing.Classification = incoming.Classification
+		existing.CallNumberSort = incoming.CallNumberSort
+	}
+	
+	if existing.PublishDate.IsZero() {
+		existing.PublishDate = incoming.PublishDate
+	}
+	
+	if existing.ReplacementCost == 0 {
+		existing.ReplacementCost = incoming.ReplacementCost
+	}
+	
+	if existing.ItemType == incoming.ItemType {
+		if existing.Attributes == nil {
+			existing.Attributes = models.ItemAttributes{}
+		}
+		for key, value := range incoming.Attributes {
+			if existing.Attributes[key] == "" {
+				existing.Attributes[key] = value
+			}
+		}
+	}
+	
+	for _, tag := range incoming.Tags {
+		if !containsString(existing.Tags, tag) {
+			existing.Tags = append(existing.Tags, tag)
+		}
+	}
+	
+	existing.TotalCopies += incoming.TotalCopies
+	existing.AvailableCopies += incoming.TotalCopies
+	if existing.Status == models.BookStatusReserved && existing.AvailableCopies > 0 {
+		existing.Status = models.BookStatusAvailable
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCSVImport(r io.Reader, mapping map[string]string) ([]ImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrImportEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+	
+	columns := make([]string, len(header))
+	for i, name := range header {
+		columns[i] = importField(strings.TrimPrefix(name, "\ufeff"), mapping)
+	}
+	
+	var records []ImportRecord
+	for number := 1; ; number++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv on row %d: %w", number, err)
+		}
+		
+		fields := make(map[string]interface{})
+		for i, value := range values {
+			if i < len(columns) && columns[i] != "" {
+				fields[columns[i]] = strings.TrimSpace(value)
+			}
+		}
+		records = append(records, ImportRecord{Number: number, Fields: fields})
+	}
+	
+	return records, nil
+}
+
+func parseJSONImport(r io.Reader, mapping map[string]string) ([]ImportRecord, error) {
+	var items []map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("invalid json, expected an array of objects: %w", err)
+	}
+	
+	records := make([]ImportRecord, len(items))
+	for i, item := range items {
+		fields := make(map[string]interface{})
+		for key, value := range item {
+			if field := importField(key, mapping); field != "" {
+				fields[field] = value
+			}
+		}
+		records[i] = ImportRecord{Number: i + 1, Fields: fields}
+	}
+	
+	return records, nil
+}
+
+func parseMARCImport(marcRecords []*marc.Record, err error) ([]ImportRecord, error) {
+	if err != nil {
+		return nil, fmt.Errorf("invalid marc data: %w", err)
+	}
+	
+	records := make([]ImportRecord, len(marcRecords))
+	for i, record := range marcRecords {
+		book := marc.ToBook(record)
+		fields := map[string]interface{}{
+			"title":            book.Title,
+			"author":           book.Author,
+			"isbn":             book.ISBN,
+			"barcode":          book.Barcode,
+			"publisher":        book.Publisher,
+			"category":         book.Category,
+			"language":         book.Language,
+			"description":      book.Description,
+			"cover_image":      book.CoverImage,
+			"replacement_cost": book.ReplacementCost,
+			"location":         book.Location,
+			"call_number":      book.CallNumber,
+			"classification":   book.Classification,
+		}
+		
+		if !book.PublishDate.IsZero() {
+			fields["publish_date"] = book.PublishDate.Format("2006-01-02")
+		}
+		
+		if series, volume := marc.Series(record); series != "" {
+			fields["series"] = series
+			fields["series_volume"] = volume
+		}
+		
+		tags := make([]interface{}, len(book.Tags))
+		for j, tag := range book.Tags {
+			tags[j] = tag
+		}
+		fields["tags"] = tags
+		
+		records[i] = ImportRecord{Number: i + 1, Fields: fields}
+	}
+	
+	return records, nil
+}
+
+func importField(column string, mapping map[string]string) string {
+	if field, ok := mapping[column]; ok {
+		column = field
+	}
+	
+	column = strings.ToLower(strings.TrimSpace(column))
+	column = strings.ReplaceAll(column, " ", "_")
+	for _, field := range importFields {
+		if field == column {
+			return field
+		}
+	}
+	for _, itemType := range models.ItemTypes {
+		if itemType.AllowsAttribute(column) {
+			return column
+		}
+	}
+	return ""
+}
+
+func bookRequestFromFields(fields map[string]interface{}) (models.BookRequest, []string) {
+	var problems []string
+	text := func(name string) string {
+		switch v := fields[name].(type) {
+		case nil:
+			return ""
+		case string:
+			return strings.TrimSpace(v)
+		default:
+			return strings.TrimSpace(fmt.Sprint(v))
+		}
+	}
+	number := func(name string) float64 {
+		switch v := fields[name].(type) {
+		case float64:
+			return v
+		case string:
+			if strings.TrimSpace(v) == "" {
+				return 0
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not a number", name, v))
+			}
+			return f
+		case nil:
+			return 0
+		default:
+			problems = append(problems, fmt.Sprintf("%s: expected a number", name))
+			return 0
+		}
+	}
+	
+	req := models.BookRequest{
+		Title:           text("title"),
+		Author:          text("author"),
+		ISBN:            text("isbn"),
+		Barcode:         text("barcode"),
+		Publisher:       text("publisher"),
+		Category:        text("category"),
+		Language:        text("language"),
+		Description:     text("description"),
+		CoverImage:      text("cover_image"),
+		TotalCopies:     int(number("total_copies")),
+		AvailableCopies: int(number("available_copies")),
+		ReplacementCost: number("replacement_cost"),
+		Location:        text("location"),
+		CallNumber:      text("call_number"),
+		Classification:  text("classification"),
+		ItemType:        models.ItemType(strings.ToLower(text("item_type"))),
+		Attributes:      models.ItemAttributes{},
+	}
+	
+	switch v := fields["attributes"].(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			req.Attributes[key] = strings.TrimSpace(fmt.Sprint(value))
+		}
+	case string:
+		for _, pair := range strings.FieldsFunc(v, func(r rune) bool { return r == ';' || r == '|' }) {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				problems = append(problems, fmt.Sprintf("attributes: %q is not key=value", strings.TrimSpace(pair)))
+				continue
+			}
+			req.Attributes[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	for _, itemType := range models.ItemTypes {
+		for _, key := range itemType.AttributeKeys() {
+			if value := text(key); value != "" {
+				req.Attributes[key] = value
+			}
+		}
+	}
+	
+	if text("total_copies") == "" {
+		req.TotalCopies = 1
+	}
+	
+	if date := text("publish_date"); date != "" {
+		parsed, err := parseImportDate(date)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("publish_date: %q is not a valid date", date))
+		}
+		req.PublishDate = parsed
+	}
+	
+	if series := text("series"); series != "" {
+		req.Series = []models.SeriesRef{{
+			AuthorityRef: models.AuthorityRef{Name: series},
+			Volume:       number("series_volume"),
+		}}
+	}
+	
+	switch v := fields["tags"].(type) {
+	case string:
+		for _, tag := range strings.FieldsFunc(v, func(r rune) bool { return r == ';' || r == '|' || r == ',' }) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				req.Tags = append(req.Tags, tag)
+			}
+		}
+	case []interface{}:
+		for _, tag := range v {
+			req.Tags = append(req.Tags, strings.TrimSpace(fmt.Sprint(tag)))
+		}
+	}
+	
+	return req, problems
+}
+
+func parseImportDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339, "2006-01", "2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
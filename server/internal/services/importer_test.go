@@ -0,0 +1,142 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestBookRequestFromFieldsItemType(t *testing.T) {
+	tests := []struct {
+		name       string
+		fields     map[string]interface{}
+		itemType   models.ItemType
+		attributes models.ItemAttributes
+		problems   int
+	}{
+		{
+			name:       "plain book",
+			fields:     map[string]interface{}{"title": "Dune"},
+			attributes: models.ItemAttributes{},
+		},
+		{
+			name:       "attribute columns",
+			fields:     map[string]interface{}{"title": "Alien", "item_type": "DVD", "runtime": "117", "region": " 2 "},
+			itemType:   models.ItemTypeDVD,
+			attributes: models.ItemAttributes{"runtime": "117", "region": "2"},
+		},
+		{
+			name:       "attributes as key=value list",
+			fields:     map[string]interface{}{"title": "Laptop", "item_type": "equipment", "attributes": "model=X1; accessories=charger"},
+			itemType:   models.ItemTypeEquipment,
+			attributes: models.ItemAttributes{"model": "X1", "accessories": "charger"},
+		},
+		{
+			name:       "attributes as object",
+			fields:     map[string]interface{}{"title": "Room 1", "item_type": "room", "attributes": map[string]interface{}{"capacity": float64(8)}},
+			itemType:   models.ItemTypeRoom,
+			attributes: models.ItemAttributes{"capacity": "8"},
+		},
+		{
+			name:       "malformed attributes",
+			fields:     map[string]interface{}{"title": "Laptop", "attributes": "model"},
+			attributes: models.ItemAttributes{},
+			problems:   1,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, problems := bookRequestFromFields(tt.fields)
+			if len(problems) != tt.problems {
+				t.Fatalf("problems = %v, want %d", problems, tt.problems)
+			}
+			if req.ItemType != tt.itemType {
+				t.Errorf("item type = %q, want %q", req.ItemType, tt.itemType)
+			}
+			if !reflect.DeepEqual(req.Attributes, tt.attributes) {
+				t.Errorf("attributes = %v, want %v", req.Attributes, tt.attributes)
+			}
+		})
+	}
+}
+
+func TestImportMatchesRowsWithoutISBNByBarcode(t *testing.T) {
+	db, cfg := testDB(t)
+	importer := NewImportService(db, nil, cfg)
+	suffix := uuid.New().String()[:8]
+	title := "Import Laptop " + suffix
+	
+	existing := models.Book{Title: title, Author: "Maker", Barcode: "L1-" + suffix, ItemType: models.ItemTypeEquipment, TotalCopies: 1, AvailableCopies: 1, Status: models.BookStatusAvailable}
+	if err := db.Create(&existing).Error; err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	
+	row := func(number int, title, barcode string) ImportRecord {
+		fields := map[string]interface{}{"title": title, "author": "Maker", "item_type": "equipment", "publisher": "Updated"}
+		if barcode != "" {
+			fields["barcode"] = barcode
+		}
+		return ImportRecord{Number: number, Fields: fields}
+	}
+	
+	tests := []struct {
+		name    string
+		dryRun  bool
+		records []ImportRecord
+		want    []models.ImportRowAction
+	}{
+		{
+			name: "import",
+			records: []ImportRecord{
+				row(1, title, "L1-"+suffix),
+				row(2, title, "L2-"+suffix),
+				row(3, title, ""),
+				row(4, "Import Tablet "+suffix, "T1-"+suffix),
+			},
+			want: []models.ImportRowAction{models.ImportRowUpdated, models.ImportRowInvalid, models.ImportRowInvalid, models.ImportRowCreated},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			records: []ImportRecord{
+				row(1, "Import Reader "+suffix, "R1-"+suffix),
+				row(2, "Import Reader "+suffix, "R1-"+suffix),
+				row(3, "Import Reader "+suffix, "R2-"+suffix),
+			},
+			want: []models.ImportRowAction{models.ImportRowCreated, models.ImportRowUpdated, models.ImportRowInvalid},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, _, err := importer.Start(tt.records, ImportOptions{Format: "json", DuplicatePolicy: models.DuplicateUpdate, DryRun: tt.dryRun})
+			if err != nil {
+				t.Fatalf("start: %v", err)
+			}
+			rows, _, err := importer.JobRows(job.ID, "", len(tt.records), 0)
+			if err != nil {
+				t.Fatalf("rows: %v", err)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.want))
+			}
+			for i, row := range rows {
+				if row.Action != tt.want[i] {
+					t.Errorf("row %d action = %q (%v), want %q", row.RowNumber, row.Action, row.Errors, tt.want[i])
+				}
+			}
+		})
+	}
+	
+	var current models.Book
+	if err := db.First(&current, "id = ?", existing.ID).Error; err != nil {
+		t.Fatalf("reload book: %v", err)
+	}
+	if current.Barcode != existing.Barcode || current.Publisher != "Updated" {
+		t.Fatalf("barcode-matched item = barcode %q, publisher %q", current.Barcode, current.Publisher)
+	}
+}
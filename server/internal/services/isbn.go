@@ -0,0 +1,74 @@
+package services
+
+import (
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/pkg/isbn"
+
+	"gorm.io/gorm"
+)
+
+func NormalizeISBNs(db *gorm.DB, apply bool) (*models.ISBNReport, error) {
+	var books []models.Book
+	if err := db.Unscoped().Select("id", "isbn", "title", "item_type", "deleted_at").Order("created_at ASC").Find(&books).Error; err != nil {
+		return nil, err
+	}
+	
+	report := &models.ISBNReport{
+		Invalid:   []models.ISBNIssue{},
+		Conflicts: []models.ISBNConflict{},
+	}
+	
+	groups := make(map[string][]models.Book)
+	order := make([]string, 0)
+	for _, book := range books {
+		if book.ISBN == "" && book.ItemType != models.ItemTypeBook {
+			continue
+		}
+		
+		normalized, err := isbn.Normalize(book.ISBN)
+		if err != nil {
+			report.Invalid = append(report.Invalid, models.ISBNIssue{
+				BookID: book.ID.String(),
+				Title:  book.Title,
+				ISBN:   book.ISBN,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		
+		if _, ok := groups[normalized]; !ok {
+			order = append(order, normalized)
+		}
+		groups[normalized] = append(groups[normalized], book)
+	}
+	
+	for _, normalized := range order {
+		group := groups[normalized]
+		if len(group) > 1 {
+			conflict := models.ISBNConflict{ISBN: normalized}
+			for _, book := range group {
+				conflict.Books = append(conflict.Books, models.ISBNIssue{
+					BookID:  book.ID.String(),
+					Title:   book.Title,
+					ISBN:    book.ISBN,
+					Deleted: book.DeletedAt.Valid,
+				})
+			}
+			report.Conflicts = append(report.Conflicts, conflict)
+			continue
+		}
+		
+		if group[0].ISBN == normalized {
+			continue
+		}
+		
+		if apply {
+			if err := db.Unscoped().Model(&models.Book{}).Where("id = ?", group[0].ID).Update("isbn", normalized).Error; err != nil {
+				return report, err
+			}
+		}
+		report.Normalized++
+	}
+	
+	return report, nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrItemType        = errors.New("item type must be book, dvd, equipment or room")
+	ErrItemAttribute   = errors.New("invalid item attribute")
+	ErrItemNotLendable = errors.New("items of this type cannot be borrowed")
+	ErrItemTypeLimit   = errors.New("member has reached the loan limit for this item type")
+	ErrItemTypeInUse   = errors.New("item type cannot change while copies are on loan or on hold")
+)
+
+func EnsureItemTypeRules(db *gorm.DB, cfg *config.Config) (int, error) {
+	defaults := []models.ItemTypeRule{
+		{ItemType: models.ItemTypeDVD, Lendable: true, LoanDays: 7, MaxRenewals: 1, FinePerDay: cfg.Library.OverdueFinePerDay},
+		{ItemType: models.ItemTypeEquipment, Lendable: true, LoanDays: 3, MaxRenewals: 0, FinePerDay: cfg.Library.OverdueFinePerDay, MaxPerMember: 1},
+		{ItemType: models.ItemTypeRoom, Lendable: false, MaxBookingHours: 2},
+	}
+	
+	created := 0
+	for _, rule := range defaults {
+		var count int64
+		if err := db.Model(&models.ItemTypeRule{}).Where("item_type = ?", rule.ItemType).Count(&count).Error; err != nil {
+			return created, err
+		}
+		if count > 0 {
+			continue
+		}
+		
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&rule).Error; err != nil {
+				return err
+			}
+			return tx.Model(&rule).Updates(map[string]interface{}{
+				"lendable":     rule.Lendable,
+				"loan_days":    rule.LoanDays,
+				"max_renewals": rule.MaxRenewals,
+			}).Error
+		})
+		if err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+func ItemRule(tx *gorm.DB, cfg *config.Config, itemType models.ItemType) models.ItemTypeRule {
+	if itemType == "" {
+		itemType = models.ItemTypeBook
+	}
+	
+	var rule models.ItemTypeRule
+	if err := tx.First(&rule, "item_type = ?", itemType).Error; err == nil {
+		return rule
+	}
+	
+	return models.ItemTypeRule{
+		ItemType:    itemType,
+		Lendable:    itemType != models.ItemTypeRoom,
+		LoanDays:    cfg.Library.MaxLoanDays,
+		MaxRenewals: cfg.Library.MaxRenewals,
+		FinePerDay:  cfg.Library.OverdueFinePerDay,
+	}
+}
+
+func ItemRules(db *gorm.DB, cfg *config.Config) ([]models.ItemTypeInfo, error) {
+	type typeCount struct {
+		ItemType models.ItemType
+		Items    int64
+	}
+	
+	var counts []typeCount
+	if err := db.Model(&models.Book{}).Select("item_type, COUNT(*) AS items").Group("item_type").Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	
+	items := make(map[models.ItemType]int64)
+	for _, count := range counts {
+		items[count.ItemType] = count.Items
+	}
+	
+	infos := make([]models.ItemTypeInfo, 0, len(models.ItemTypes))
+	for _, itemType := range models.ItemTypes {
+		infos = append(infos, models.ItemTypeInfo{
+			ItemType:   itemType,
+			Attributes: itemType.AttributeKeys(),
+			Rule:       ItemRule(db, cfg, itemType),
+			Items:      items[itemType],
+		})
+	}
+	return infos, nil
+}
+
+func SaveItemRule(db *gorm.DB, cfg *config.Config, req models.ItemTypeRuleRequest) (*models.ItemTypeRule, error) {
+	if !req.ItemType.Valid() {
+		return nil, ErrItemType
+	}
+	
+	rule := ItemRule(db, cfg, req.ItemType)
+	if req.Lendable != nil {
+		rule.Lendable = *req.Lendable
+	}
+	rule.LoanDays = req.LoanDays
+	rule.MaxRenewals = req.MaxRenewals
+	rule.FinePerDay = req.FinePerDay
+	rule.MaxPerMember = req.MaxPerMember
+	rule.MaxBookingHours = req.MaxBookingHours
+	
+	if rule.Lendable && rule.LoanDays < 1 {
+		return nil, fmt.Errorf("%w: loan_days must be at least 1 for lendable items", ErrItemAttribute)
+	}
+	
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&rule).Error; err != nil {
+			return err
+		}
+		return tx.Model(&rule).Updates(map[string]interface{}{
+			"lendable":     rule.Lendable,
+			"loan_days":    rule.LoanDays,
+			"max_renewals": rule.MaxRenewals,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func CleanItemAttributes(itemType models.ItemType, attributes models.ItemAttributes) (models.ItemType, models.ItemAttributes, error) {
+	if itemType == "" {
+		itemType = models.ItemTypeBook
+	}
+	if !itemType.Valid() {
+		return "", nil, ErrItemType
+	}
+	
+	cleaned := models.ItemAttributes{}
+	for key, value := range attributes {
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !itemType.AllowsAttribute(key) {
+			return "", nil, fmt.Errorf("%w: %s items take %s", ErrItemAttribute, itemType, strings.Join(itemType.AttributeKeys(), ", "))
+		}
+		if models.IsNumericAttribute(key) {
+			if n, err := strconv.Atoi(value); err != nil || n < 0 {
+				return "", nil, fmt.Errorf("%w: %s must be a whole number", ErrItemAttribute, key)
+			}
+		}
+		cleaned[key] = value
+	}
+	return itemType, cleaned, nil
+}
+
+// ItemInUse reports whether an item has loans, holds or upcoming bookings
+// that its item type must not change under.
+func ItemInUse(db *gorm.DB, bookID uuid.UUID) bool {
+	var loans, holds, bookings int64
+	db.Model(&models.Loan{}).
+		Where("book_id = ? AND status IN ?", bookID, []models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue}).
+		Count(&loans)
+	db.Model(&models.Reservation{}).
+		Where("book_id = ? AND status IN ?", bookID, []models.ReservationStatus{models.ReservationStatusPending, models.ReservationStatusInTransit, models.ReservationStatusReadyForPickup}).
+		Count(&holds)
+	db.Model(&models.RoomBooking{}).
+		Where("room_id = ? AND status = ? AND end_time > ?", bookID, models.BookingConfirmed, time.Now()).
+		Count(&bookings)
+	return loans+holds+bookings > 0
+}
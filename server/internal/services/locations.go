@@ -0,0 +1,547 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLocationNotFound     = errors.New("location not found")
+	ErrLocationExists       = errors.New("a location with this code already exists under the same parent")
+	ErrInvalidLocationLevel = errors.New("location level must be floor, room, range or shelf")
+	ErrLocationParentLevel  = errors.New("location level must sit below its parent and above its children")
+	ErrLocationCycle        = errors.New("location cannot be moved inside itself")
+	ErrLocationBranch       = errors.New("locations belong to different branches")
+	ErrLocationInactive     = errors.New("location is inactive")
+	ErrUnknownLocation      = errors.New("location does not match the shelf hierarchy of this branch")
+	ErrAmbiguousLocation    = errors.New("location code matches more than one place, use the full path")
+	ErrNothingToRelocate    = errors.New("no items selected to relocate")
+)
+
+type LocationService struct {
+	db     *gorm.DB
+	config *config.Config
+}
+
+func NewLocationService(db *gorm.DB, cfg *config.Config) *LocationService {
+	return &LocationService{db: db, config: cfg}
+}
+
+func (s *LocationService) Create(req models.LocationRequest) (*models.Location, error) {
+	if !req.Level.IsValid() {
+		return nil, ErrInvalidLocationLevel
+	}
+	
+	location := models.Location{
+		Level:    req.Level,
+		IsActive: true,
+	}
+	s.apply(&location, req)
+	
+	if req.ParentID != "" {
+		parent, err := s.find(s.db, req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Level.Depth() >= req.Level.Depth() {
+			return nil, ErrLocationParentLevel
+		}
+		location.BranchID = parent.BranchID
+		location.ParentID = &parent.ID
+		location.Path = parent.Path + "/" + location.Code
+	} else {
+		branch, err := ResolveBranch(s.db, req.BranchID)
+		if err != nil {
+			return nil, err
+		}
+		location.BranchID = branch.ID
+		location.Path = location.Code
+	}
+	
+	var existing int64
+	s.db.Model(&models.Location{}).Where("branch_id = ? AND path = ?", location.BranchID, location.Path).Count(&existing)
+	if existing > 0 {
+		return nil, ErrLocationExists
+	}
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&location).Error; err != nil {
+			return err
+		}
+		if !location.IsActive {
+			return tx.Model(&location).Update("is_active", false).Error
+		}
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (s *LocationService) Update(id string, req models.LocationRequest) (*models.Location, error) {
+	if !req.Level.IsValid() {
+		return nil, ErrInvalidLocationLevel
+	}
+	
+	location, err := s.find(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	
+	if req.Level != location.Level {
+		if err := s.checkLevel(s.db, location, location.ParentID, req.Level); err != nil {
+			return nil, err
+		}
+		location.Level = req.Level
+	}
+	
+	oldPath := location.Path
+	s.apply(location, req)
+	location.Path = replaceLastSegment(oldPath, location.Code)
+	
+	if location.Path != oldPath {
+		var existing int64
+		s.db.Model(&models.Location{}).Where("branch_id = ? AND path = ?", location.BranchID, location.Path).Count(&existing)
+		if existing > 0 {
+			return nil, ErrLocationExists
+		}
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(location).Error; err != nil {
+			return err
+		}
+		if location.Path == oldPath {
+			return nil
+		}
+		return s.rewritePaths(tx, location.BranchID, oldPath, location.Path)
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+func (s *LocationService) Move(id, parentID string) (*models.Location, error) {
+	location, err := s.find(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	
+	var parent *models.Location
+	newPath := location.Code
+	if parentID != "" {
+		if parent, err = s.find(s.db, parentID); err != nil {
+			return nil, err
+		}
+		if parent.BranchID != location.BranchID {
+			return nil, ErrLocationBranch
+		}
+		if parent.Path == location.Path || strings.HasPrefix(parent.Path, location.Path+"/") {
+			return nil, ErrLocationCycle
+		}
+		newPath = parent.Path + "/" + location.Code
+	}
+	
+	var newParentID *uuid.UUID
+	if parent != nil {
+		newParentID = &parent.ID
+	}
+	if err := s.checkLevel(s.db, location, newParentID, location.Level); err != nil {
+		return nil, err
+	}
+	
+	if newPath == location.Path {
+		return location, nil
+	}
+	
+	var existing int64
+	s.db.Model(&models.Location{}).Where("branch_id = ? AND path = ?", location.BranchID, newPath).Count(&existing)
+	if existing > 0 {
+		return nil, ErrLocationExists
+	}
+	
+	oldPath := location.Path
+	location.ParentID = newParentID
+	location.Path = newPath
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(location).Select("parent_id", "path").Updates(location).Error; err != nil {
+			return err
+		}
+		return s.rewritePaths(tx, location.BranchID, oldPath, newPath)
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+func (s *LocationService) Tree(branchID string) ([]*models.LocationTree, error) {
+	branch, err := ResolveBranch(s.db, branchID)
+	if err != nil {
+		return nil, err
+	}
+	
+	var locations []models.Location
+	if err := s.db.Where("branch_id = ?", branch.ID).Order("path ASC").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	
+	var counts []struct {
+		LocationID uuid.UUID
+		Items      int64
+	}
+	s.db.Model(&models.BookHolding{}).
+		Select("location_id, COUNT(*) AS items").
+		Where("branch_id = ? AND location_id IS NOT NULL", branch.ID).
+		Group("location_id").
+		Scan(&counts)
+	
+	items := make(map[uuid.UUID]int64, len(counts))
+	for _, c := range counts {
+		items[c.LocationID] = c.Items
+	}
+	
+	nodes := make(map[uuid.UUID]*models.LocationTree, len(locations))
+	roots := []*models.LocationTree{}
+	for _, l := range locations {
+		node := &models.LocationTree{Location: l, Children: []*models.LocationTree{}, Items: items[l.ID]}
+		nodes[l.ID] = node
+		if l.ParentID == nil {
+			roots = append(roots, node)
+		} else if parent, ok := nodes[*l.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	
+	return roots, nil
+}
+
+func (s *LocationService) Resolve(tx *gorm.DB, branchID uuid.UUID, locationID, text string) (*models.Location, error) {
+	if locationID != "" {
+		location, err := s.find(tx, locationID)
+		if err != nil {
+			return nil, err
+		}
+		if location.BranchID != branchID {
+			return nil, ErrLocationBranch
+		}
+		if !location.IsActive {
+			return nil, ErrLocationInactive
+		}
+		return location, nil
+	}
+	
+	text = strings.Trim(strings.TrimSpace(text), "/")
+	if text == "" {
+		return nil, nil
+	}
+	
+	var matches []models.Location
+	if err := tx.Where("branch_id = ? AND is_active = ? AND LOWER(path) = LOWER(?)", branchID, true, text).
+		Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	
+	if len(matches) == 0 {
+		if err := tx.Where("branch_id = ? AND is_active = ? AND LOWER(code) = LOWER(?)", branchID, true, text).
+			Find(&matches).Error; err != nil {
+			return nil, err
+		}
+	}
+	
+	switch len(matches) {
+	case 1:
+		return &matches[0], nil
+	case 0:
+		var defined int64
+		tx.Model(&models.Location{}).Where("branch_id = ?", branchID).Count(&defined)
+		if defined > 0 {
+			return nil, ErrUnknownLocation
+		}
+		return nil, nil
+	default:
+		return nil, ErrAmbiguousLocation
+	}
+}
+
+func (s *LocationService) Place(tx *gorm.DB, bookID, branchID uuid.UUID, location *models.Location) error {
+	if location == nil {
+		return nil
+	}
+	
+	if err := tx.Model(&models.BookHolding{}).
+		Where("book_id = ? AND branch_id = ?", bookID, branchID).
+		Update("location_id", location.ID).Error; err != nil {
+		return err
+	}
+	
+	return syncBookLocations(tx, tx.Model(&models.BookHolding{}).Select("book_id").Where("book_id = ?", bookID))
+}
+
+func (s *LocationService) ShelfFor(tx *gorm.DB, branchID uuid.UUID, sortKey string) (*models.Location, error) {
+	if sortKey == "" {
+		return nil, nil
+	}
+	
+	var locations []models.Location
+	err := tx.Where("branch_id = ? AND is_active = ? AND start_sort <> ''", branchID, true).
+		Where(`start_sort COLLATE "C" <= ?`, sortKey).
+		Where(`(end_sort = '' OR end_sort COLLATE "C" >= ? OR ? LIKE end_sort || '%')`, sortKey, sortKey).
+		Order("LENGTH(path) DESC").
+		Limit(1).
+		Find(&locations).Error
+	if err != nil || len(locations) == 0 {
+		return nil, err
+	}
+	return &locations[0], nil
+}
+
+func (s *LocationService) PrimaryBranch(tx *gorm.DB, bookID uuid.UUID) (uuid.UUID, error) {
+	var holding models.BookHolding
+	err := tx.Where("book_id = ?", bookID).Order("total_copies DESC").Order("created_at ASC").First(&holding).Error
+	if err == nil {
+		return holding.BranchID, nil
+	}
+	
+	branch, err := DefaultBranch(tx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return branch.ID, nil
+}
+
+func (s *LocationService) WhereIs(bookID uuid.UUID) ([]models.BookPlacement, error) {
+	var book models.Book
+	if err := s.db.First(&book, "id = ?", bookID).Error; err != nil {
+		return nil, ErrBookNotFound
+	}
+	
+	var holdings []models.BookHolding
+	if err := s.db.Preload("Branch").Preload("Location").
+		Where("book_id = ?", bookID).Order("total_copies DESC").Find(&holdings).Error; err != nil {
+		return nil, err
+	}
+	
+	placements := make([]models.BookPlacement, 0, len(holdings))
+	for _, h := range holdings {
+		placement := models.BookPlacement{
+			BranchID:   h.BranchID.String(),
+			BranchCode: h.Branch.Code,
+			BranchName: h.Branch.Name,
+			Location:   book.Location,
+			Path:       []models.LocationNode{},
+			OnShelf:    holdingUsage(s.db, h).OnShelf,
+		}
+		
+		if h.Location != nil {
+			placement.Location = h.Location.Path
+			for _, node := range s.ancestors(h.Location) {
+				placement.Path = append(placement.Path, models.LocationNode{
+					ID:              node.ID.String(),
+					Level:           node.Level,
+					Code:            node.Code,
+					Name:            node.Name,
+					CallNumberStart: node.CallNumberStart,
+					CallNumberEnd:   node.CallNumberEnd,
+				})
+				if node.MapURL != "" {
+					placement.MapURL = node.MapURL
+				}
+				if node.HasCoordinates() {
+					placement.MapX, placement.MapY = node.MapX, node.MapY
+				}
+			}
+		}
+		
+		placements = append(placements, placement)
+	}
+	
+	return placements, nil
+}
+
+func (s *LocationService) Relocate(req models.RelocateRequest) (*models.RelocationReport, error) {
+	target, err := s.find(s.db, req.ToLocationID)
+	if err != nil {
+		return nil, err
+	}
+	if !target.IsActive {
+		return nil, ErrLocationInactive
+	}
+	
+	report := &models.RelocationReport{Location: target.Path, Skipped: []string{}}
+	
+	var bookIDs []uuid.UUID
+	for _, id := range req.BookIDs {
+		if bookID, err := uuid.Parse(id); err == nil {
+			bookIDs = append(bookIDs, bookID)
+		} else {
+			report.Skipped = append(report.Skipped, id)
+		}
+	}
+	
+	query := s.db.Model(&models.BookHolding{}).Where("book_holdings.branch_id = ?", target.BranchID)
+	switch {
+	case req.FromLocationID != "":
+		from, err := s.find(s.db, req.FromLocationID)
+		if err != nil {
+			return nil, err
+		}
+		if from.BranchID != target.BranchID {
+			return nil, ErrLocationBranch
+		}
+		query = query.Where("book_holdings.location_id IN (?)",
+			s.db.Model(&models.Location{}).Select("id").
+				Where("branch_id = ? AND (path = ? OR path LIKE ?)", from.BranchID, from.Path, from.Path+"/%"))
+	case req.FromText != "":
+		query = query.Joins("JOIN books ON books.id = book_holdings.book_id").
+			Where("LOWER(books.location) = LOWER(?)", strings.TrimSpace(req.FromText))
+	case len(bookIDs) > 0:
+		query = query.Where("book_holdings.book_id IN ?", bookIDs)
+	default:
+		return nil, ErrNothingToRelocate
+	}
+	
+	var holdings []models.BookHolding
+	if err := query.Select("book_holdings.*").Find(&holdings).Error; err != nil {
+		return nil, err
+	}
+	
+	if len(bookIDs) > 0 && req.FromLocationID == "" && req.FromText == "" {
+		found := make(map[uuid.UUID]bool, len(holdings))
+		for _, h := range holdings {
+			found[h.BookID] = true
+		}
+		for _, id := range bookIDs {
+			if !found[id] {
+				report.Skipped = append(report.Skipped, id.String())
+			}
+		}
+	}
+	
+	if len(holdings) == 0 {
+		return report, nil
+	}
+	
+	ids := make([]uuid.UUID, len(holdings))
+	for i, h := range holdings {
+		ids[i] = h.ID
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.BookHolding{}).Where("id IN ?", ids).Update("location_id", target.ID).Error; err != nil {
+			return err
+		}
+		return syncBookLocations(tx, tx.Model(&models.BookHolding{}).Select("book_id").Where("id IN ?", ids))
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	report.Moved = len(holdings)
+	return report, nil
+}
+
+func (s *LocationService) find(tx *gorm.DB, id string) (*models.Location, error) {
+	locationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrLocationNotFound
+	}
+	
+	var location models.Location
+	if err := tx.First(&location, "id = ?", locationID).Error; err != nil {
+		return nil, ErrLocationNotFound
+	}
+	return &location, nil
+}
+
+func (s *LocationService) ancestors(location *models.Location) []models.Location {
+	chain := []models.Location{*location}
+	for current := location; current.ParentID != nil && len(chain) < 8; {
+		var parent models.Location
+		if err := s.db.First(&parent, "id = ?", *current.ParentID).Error; err != nil {
+			break
+		}
+		chain = append([]models.Location{parent}, chain...)
+		current = &parent
+	}
+	return chain
+}
+
+func (s *LocationService) checkLevel(tx *gorm.DB, location *models.Location, parentID *uuid.UUID, level models.LocationLevel) error {
+	if parentID != nil {
+		var parent models.Location
+		if err := tx.First(&parent, "id = ?", *parentID).Error; err != nil {
+			return ErrLocationNotFound
+		}
+		if parent.Level.Depth() >= level.Depth() {
+			return ErrLocationParentLevel
+		}
+	}
+	
+	var children []models.Location
+	tx.Where("parent_id = ?", location.ID).Find(&children)
+	for _, child := range children {
+		if child.Level.Depth() <= level.Depth() {
+			return ErrLocationParentLevel
+		}
+	}
+	return nil
+}
+
+func (s *LocationService) rewritePaths(tx *gorm.DB, branchID uuid.UUID, oldPath, newPath string) error {
+	if err := tx.Exec(`UPDATE locations SET path = ? || substr(path, ?)
+		WHERE branch_id = ? AND path LIKE ?`,
+		newPath, len(oldPath)+1, branchID, oldPath+"/%").Error; err != nil {
+		return err
+	}
+	
+	return syncBookLocations(tx, tx.Model(&models.BookHolding{}).Select("book_holdings.book_id").
+		Joins("JOIN locations ON locations.id = book_holdings.location_id").
+		Where("locations.branch_id = ? AND (locations.path = ? OR locations.path LIKE ?)", branchID, newPath, newPath+"/%"))
+}
+
+func syncBookLocations(tx *gorm.DB, bookIDs *gorm.DB) error {
+	return tx.Exec(`UPDATE books SET location = placed.path
+		FROM (
+			SELECT DISTINCT ON (h.book_id) h.book_id, l.path
+			FROM book_holdings h
+			JOIN locations l ON l.id = h.location_id
+			WHERE h.book_id IN (?)
+			ORDER BY h.book_id, h.total_copies DESC, h.created_at ASC
+		) placed
+		WHERE books.id = placed.book_id`, bookIDs).Error
+}
+
+func (s *LocationService) apply(location *models.Location, req models.LocationRequest) {
+	location.Code = strings.ReplaceAll(strings.Trim(strings.TrimSpace(req.Code), "/"), "/", "-")
+	location.Name = strings.TrimSpace(req.Name)
+	location.CallNumberStart = strings.TrimSpace(req.CallNumberStart)
+	location.CallNumberEnd = strings.TrimSpace(req.CallNumberEnd)
+	location.StartSort = rangeSortKey(location.CallNumberStart, s.config.Library.CallNumberScheme)
+	location.EndSort = rangeSortKey(location.CallNumberEnd, s.config.Library.CallNumberScheme)
+	location.MapURL = req.MapURL
+	location.MapX = req.MapX
+	location.MapY = req.MapY
+	if req.IsActive != nil {
+		location.IsActive = *req.IsActive
+	}
+}
+
+func replaceLastSegment(path, code string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[:i+1] + code
+	}
+	return code
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrRepairNotFound      = errors.New("repair record not found")
+	ErrRepairClosed        = errors.New("repair record is already closed")
+	ErrInvalidRepairStatus = errors.New("invalid repair status")
+	ErrNoCopyOnShelf       = errors.New("no copy of this book is on the shelf")
+	ErrLoanLost            = errors.New("loan is marked lost, the copy is not in the library")
+)
+
+func (s *CirculationService) DeclareLost(loanID uuid.UUID, notes string) (*models.Loan, error) {
+	var loan models.Loan
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&loan, "id = ?", loanID).Error; err != nil {
+			return ErrLoanNotFound
+		}
+		
+		if err := s.declareLost(tx, &loan); err != nil {
+			return err
+		}
+		
+		if notes != "" {
+			loan.Notes = notes
+		}
+		return tx.Save(&loan).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+func (s *CirculationService) AutoDeclareLost() (int, error) {
+	if s.config.Library.AutoLostAfterDays <= 0 {
+		return 0, nil
+	}
+	
+	cutoff := time.Now().AddDate(0, 0, -s.config.Library.AutoLostAfterDays)
+	
+	var overdue []models.Loan
+	if err := s.db.Where("status IN ? AND due_date < ?",
+		[]models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue}, cutoff).
+		Find(&overdue).Error; err != nil {
+		return 0, err
+	}
+	
+	count := 0
+	for _, l := range overdue {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var loan models.Loan
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&loan, "id = ?", l.ID).Error; err != nil {
+				return err
+			}
+			
+			if err := s.declareLost(tx, &loan); err != nil {
+				return err
+			}
+			return tx.Save(&loan).Error
+		})
+		if errors.Is(err, ErrLoanNotActive) {
+			continue
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	
+	return count, nil
+}
+
+func (s *CirculationService) declareLost(tx *gorm.DB, loan *models.Loan) error {
+	if !loan.IsOutstanding() {
+		return ErrLoanNotActive
+	}
+	
+	var book models.Book
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", loan.BookID).Error; err != nil {
+		return ErrBookNotFound
+	}
+	
+	replacement := book.ReplacementCost
+	if replacement <= 0 {
+		replacement = s.config.Library.DefaultReplacementFee
+	}
+	
+	loan.FineAmount = loan.CalculateFine(ItemRule(tx, s.config, book.ItemType).FinePerDay)
+	loan.MarkLost(replacement, s.config.Library.LostProcessingFee)
+	
+	book.WithdrawCopy()
+	if err := tx.Save(&book).Error; err != nil {
+		return err
+	}
+	
+	owner, err := s.branchOrDefault(tx, loan.OwningBranchID)
+	if err != nil {
+		return err
+	}
+	if err := AdjustHolding(tx, book.ID, owner, -1); err != nil {
+		return err
+	}
+	
+	var member models.Member
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&member, "id = ?", loan.MemberID).Error; err != nil {
+		return ErrMemberNotFound
+	}
+	
+	if member.CurrentBooksIssued > 0 {
+		member.CurrentBooksIssued--
+	}
+	member.TotalFineAmount += loan.FineAmount + loan.ReplacementFee + loan.ProcessingFee
+	return tx.Save(&member).Error
+}
+
+func (s *CirculationService) ReportDamage(bookID uuid.UUID, loanID, branchID *uuid.UUID, reportedByID uuid.UUID, charge float64, notes string, photos []string) (*models.Repair, error) {
+	repair := models.Repair{
+		ID:           uuid.New(),
+		BookID:       bookID,
+		LoanID:       loanID,
+		ReportedByID: reportedByID,
+		Status:       models.RepairStatusQueued,
+		DamageCharge: charge,
+		Notes:        notes,
+		Photos:       photos,
+	}
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", bookID).Error; err != nil {
+			return ErrBookNotFound
+		}
+		
+		if loanID != nil {
+			var loan models.Loan
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				First(&loan, "id = ? AND book_id = ?", *loanID, bookID).Error; err != nil {
+				return ErrLoanNotFound
+			}
+			if loan.Status == models.LoanStatusLost {
+				return ErrLoanLost
+			}
+			
+			if loan.OwningBranchID != nil {
+				branchID = loan.OwningBranchID
+			}
+			
+			returned := loan.IsOutstanding()
+			if returned {
+				now := time.Now()
+				loan.FineAmount = loan.CalculateFine(ItemRule(tx, s.config, book.ItemType).FinePerDay)
+				loan.ReturnDate = &now
+				loan.ActualReturnDate = &now
+				loan.Status = models.LoanStatusReturned
+				if err := tx.Save(&loan).Error; err != nil {
+					return err
+				}
+			}
+			
+			var member models.Member
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&member, "id = ?", loan.MemberID).Error; err == nil {
+				if returned {
+					if member.CurrentBooksIssued > 0 {
+						member.CurrentBooksIssued--
+					}
+					member.TotalFineAmount += loan.FineAmount
+				}
+				member.TotalFineAmount += charge
+				if err := tx.Save(&member).Error; err != nil {
+					return err
+				}
+			}
+			
+			if !returned {
+				if err := pullShelfCopy(&book); err != nil {
+					return err
+				}
+			}
+		} else if err := pullShelfCopy(&book); err != nil {
+			return err
+		}
+		
+		if err := tx.Save(&book).Error; err != nil {
+			return err
+		}
+		
+		owner, err := s.branchOrDefault(tx, branchID)
+		if err != nil {
+			return err
+		}
+		repair.BranchID = &owner
+		
+		return tx.Create(&repair).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &repair, nil
+}
+
+func (s *CirculationService) RepairQueue(status string) ([]models.Repair, error) {
+	query := s.db.Preload("Book")
+	
+	if status != "" {
+		query = query.Where("status = ?", status)
+	} else {
+		query = query.Where("status IN ?", []models.RepairStatus{models.RepairStatusQueued, models.RepairStatusInRepair})
+	}
+	
+	var repairs []models.Repair
+	err := query.Order("created_at ASC").Find(&repairs).Error
+	return repairs, err
+}
+
+func (s *CirculationService) UpdateRepair(repairID uuid.UUID, status models.RepairStatus, notes string) (*models.Repair, error) {
+	var repair models.Repair
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&repair, "id = ?", repairID).Error; err != nil {
+			return ErrRepairNotFound
+		}
+		
+		if !repair.IsOpen() {
+			return ErrRepairClosed
+		}
+		
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", repair.BookID).Error; err != nil {
+			return ErrBookNotFound
+		}
+		
+		owner, err := s.branchOrDefault(tx, repair.BranchID)
+		if err != nil {
+			return err
+		}
+		
+		switch status {
+		case models.RepairStatusInRepair:
+			repair.Status = status
+			if notes != "" {
+				repair.ResolvedNotes = notes
+			}
+		case models.RepairStatusRepaired:
+			repair.Resolve(status, notes)
+			if _, _, err := s.trapNextHold(tx, &book, owner, owner); err != nil {
+				return err
+			}
+		case models.RepairStatusWithdrawn:
+			repair.Resolve(status, notes)
+			book.WithdrawCopy()
+			if book.TotalCopies == 0 {
+				book.Status = models.BookStatusDamaged
+			}
+			if err := tx.Save(&book).Error; err != nil {
+				return err
+			}
+			if err := AdjustHolding(tx, book.ID, owner, -1); err != nil {
+				return err
+			}
+		default:
+			return ErrInvalidRepairStatus
+		}
+		
+		return tx.Save(&repair).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &repair, nil
+}
+
+func pullShelfCopy(book *models.Book) error {
+	if book.AvailableCopies == 0 {
+		return ErrNoCopyOnShelf
+	}
+	book.PullShelfCopy()
+	return nil
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrRoomNotFound     = errors.New("room not found")
+	ErrBookingNotFound  = errors.New("room booking not found")
+	ErrBookingTime      = errors.New("booking must end after it starts and cannot start in the past")
+	ErrBookingTooLong   = errors.New("booking is longer than this room allows")
+	ErrBookingTooFar    = errors.New("booking starts too far ahead")
+	ErrBookingHours     = errors.New("booking falls outside room opening hours")
+	ErrBookingCapacity  = errors.New("attendees exceed the room capacity")
+	ErrBookingCancelled = errors.New("room booking is already cancelled")
+	ErrBookingEnded     = errors.New("room booking has already ended")
+	ErrBookingNotOwner  = errors.New("room booking belongs to another member")
+)
+
+const maxCalendarDays = 31
+
+type BookingConflictError struct {
+	Bookings []models.RoomBooking
+}
+
+func (e *BookingConflictError) Error() string {
+	return "room or member is already booked for this time"
+}
+
+type RoomService struct {
+	db     *gorm.DB
+	config *config.Config
+}
+
+func NewRoomService(db *gorm.DB, cfg *config.Config) *RoomService {
+	return &RoomService{db: db, config: cfg}
+}
+
+func (s *RoomService) Rooms(branchID string, minCapacity int) ([]models.Book, error) {
+	query := s.db.Where("item_type = ?", models.ItemTypeRoom)
+	if branchID != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM book_holdings h WHERE h.book_id = books.id AND h.branch_id = ?)", branchID)
+	}
+	if minCapacity > 0 {
+		query = query.Where("COALESCE(NULLIF(attributes->>'capacity', '')::int, 0) >= ?", minCapacity)
+	}
+	
+	var rooms []models.Book
+	err := query.Order("title ASC").Find(&rooms).Error
+	return rooms, err
+}
+
+func (s *RoomService) Bookings(roomID, memberID, status string, from, to time.Time) ([]models.RoomBooking, error) {
+	query := s.db.Preload("Room").Preload("Member.User")
+	if roomID != "" {
+		query = query.Where("room_id = ?", roomID)
+	}
+	if memberID != "" {
+		query = query.Where("member_id = ?", memberID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if !from.IsZero() {
+		query = query.Where("end_time > ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("start_time < ?", to)
+	}
+	
+	var bookings []models.RoomBooking
+	err := query.Order("start_time ASC").Find(&bookings).Error
+	return bookings, err
+}
+
+func (s *RoomService) Book(req models.RoomBookingRequest, bookedByID uuid.UUID) (*models.RoomBooking, error) {
+	var booking models.RoomBooking
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var room models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&room, "id = ? AND item_type = ?", req.RoomID, models.ItemTypeRoom).Error; err != nil {
+			return ErrRoomNotFound
+		}
+		
+		var member models.Member
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"})
+		if req.MemberID != "" {
+			query = query.Where("id = ?", req.MemberID)
+		} else {
+			query = query.Where("membership_id = ?", strings.TrimSpace(req.MembershipID))
+		}
+		if err := query.First(&member).Error; err != nil {
+			return ErrMemberNotFound
+		}
+		if !member.IsActive || member.IsExpired() {
+			return ErrMemberInactive
+		}
+		
+		start, end := req.StartTime.Truncate(time.Minute), req.EndTime.Truncate(time.Minute)
+		if err := s.checkSlot(tx, &room, start, end); err != nil {
+			return err
+		}
+		
+		attendees := req.Attendees
+		if attendees == 0 {
+			attendees = 1
+		}
+		if capacity, err := strconv.Atoi(room.Attributes["capacity"]); err == nil && capacity > 0 && attendees > capacity {
+			return ErrBookingCapacity
+		}
+		
+		var conflicts []models.RoomBooking
+		if err := tx.Where("status = ? AND (room_id = ? OR member_id = ?) AND start_time < ? AND end_time > ?",
+			models.BookingConfirmed, room.ID, member.ID, end, start).
+			Order("start_time ASC").Find(&conflicts).Error; err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return &BookingConflictError{Bookings: conflicts}
+		}
+		
+		booking = models.RoomBooking{
+			RoomID:     room.ID,
+			MemberID:   member.ID,
+			BookedByID: bookedByID,
+			StartTime:  start,
+			EndTime:    end,
+			Status:     models.BookingConfirmed,
+			Purpose:    req.Purpose,
+			Attendees:  attendees,
+		}
+		if err := tx.Omit("Room", "Member").Create(&booking).Error; err != nil {
+			return err
+		}
+		
+		booking.Room = room
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+func (s *RoomService) Cancel(bookingID string, memberID *uuid.UUID) (*models.RoomBooking, error) {
+	var booking models.RoomBooking
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, "id = ?", bookingID).Error; err != nil {
+			return ErrBookingNotFound
+		}
+		if memberID != nil && booking.MemberID != *memberID {
+			return ErrBookingNotOwner
+		}
+		if booking.Status == models.BookingCancelled {
+			return ErrBookingCancelled
+		}
+		
+		now := time.Now()
+		if !booking.EndTime.After(now) {
+			return ErrBookingEnded
+		}
+		
+		booking.Status = models.BookingCancelled
+		booking.CancelledAt = &now
+		return tx.Omit("Room", "Member").Save(&booking).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+func (s *RoomService) Calendar(roomID, branchID string, from time.Time, days int) ([]models.RoomCalendar, error) {
+	if days < 1 || days > maxCalendarDays {
+		days = 7
+	}
+	if from.IsZero() {
+		from = time.Now()
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, days)
+	
+	var rooms []models.Book
+	if roomID != "" {
+		var room models.Book
+		if err := s.db.First(&room, "id = ? AND item_type = ?", roomID, models.ItemTypeRoom).Error; err != nil {
+			return nil, ErrRoomNotFound
+		}
+		rooms = append(rooms, room)
+	} else {
+		var err error
+		if rooms, err = s.Rooms(branchID, 0); err != nil {
+			return nil, err
+		}
+	}
+	
+	ids := make([]uuid.UUID, 0, len(rooms))
+	for _, room := range rooms {
+		ids = append(ids, room.ID)
+	}
+	
+	var bookings []models.RoomBooking
+	if len(ids) > 0 {
+		if err := s.db.Where("room_id IN ? AND status = ? AND start_time < ? AND end_time > ?",
+			ids, models.BookingConfirmed, to, from).
+			Order("start_time ASC").Find(&bookings).Error; err != nil {
+			return nil, err
+		}
+	}
+	
+	byRoom := make(map[uuid.UUID][]models.RoomBooking)
+	for _, booking := range bookings {
+		byRoom[booking.RoomID] = append(byRoom[booking.RoomID], booking)
+	}
+	
+	calendars := make([]models.RoomCalendar, 0, len(rooms))
+	for _, room := range rooms {
+		calendar := models.RoomCalendar{
+			RoomID:     room.ID.String(),
+			Title:      room.Title,
+			Location:   room.Location,
+			Attributes: room.Attributes,
+			Days:       make([]models.RoomDay, 0, days),
+		}
+		
+		for i := 0; i < days; i++ {
+			day := from.AddDate(0, 0, i)
+			opens, closes := s.openingHours(day)
+			calendar.Days = append(calendar.Days, s.roomDay(day, opens, closes, byRoom[room.ID]))
+		}
+		calendars = append(calendars, calendar)
+	}
+	return calendars, nil
+}
+
+func (s *RoomService) checkSlot(tx *gorm.DB, room *models.Book, start, end time.Time) error {
+	now := time.Now()
+	if !end.After(start) || end.Before(now) || start.Before(now.Add(-time.Minute)) {
+		return ErrBookingTime
+	}
+	
+	if ahead := s.config.Library.RoomBookingDaysAhead; ahead > 0 && start.After(now.AddDate(0, 0, ahead)) {
+		return ErrBookingTooFar
+	}
+	
+	rule := ItemRule(tx, s.config, room.ItemType)
+	if rule.MaxBookingHours > 0 && end.Sub(start) > time.Duration(rule.MaxBookingHours)*time.Hour {
+		return ErrBookingTooLong
+	}
+	
+	opens, closes := s.openingHours(start.In(time.Local))
+	if start.Before(opens) || end.After(closes) {
+		return ErrBookingHours
+	}
+	return nil
+}
+
+func (s *RoomService) openingHours(day time.Time) (time.Time, time.Time) {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	return midnight.Add(time.Duration(s.config.Library.RoomOpenHour) * time.Hour),
+		midnight.Add(time.Duration(s.config.Library.RoomCloseHour) * time.Hour)
+}
+
+func (s *RoomService) roomDay(day, opens, closes time.Time, bookings []models.RoomBooking) models.RoomDay {
+	result := models.RoomDay{
+		Date:     day.Format("2006-01-02"),
+		Bookings: []models.RoomBooking{},
+		Free:     []models.TimeSlot{},
+	}
+	
+	for _, booking := range bookings {
+		if booking.Overlaps(opens, closes) {
+			result.Bookings = append(result.Bookings, booking)
+		}
+	}
+	sort.Slice(result.Bookings, func(i, j int) bool {
+		return result.Bookings[i].StartTime.Before(result.Bookings[j].StartTime)
+	})
+	
+	cursor := opens
+	if now := time.Now().Truncate(time.Minute); cursor.Before(now) {
+		cursor = now
+	}
+	for _, booking := range result.Bookings {
+		if booking.StartTime.After(cursor) {
+			result.Free = append(result.Free, models.TimeSlot{Start: cursor, End: booking.StartTime})
+		}
+		if booking.EndTime.After(cursor) {
+			cursor = booking.EndTime
+		}
+	}
+	if closes.After(cursor) {
+		result.Free = append(result.Free, models.TimeSlot{Start: cursor, End: closes})
+	}
+	return result
+}
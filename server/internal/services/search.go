@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/pkg/isbn"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const autocompleteGenerationKey = "autocomplete:generation"
+
+const bookTSQuery = "(websearch_to_tsquery(books_search_config(?), ?) || websearch_to_tsquery('simple', ?))"
+
+type RankedBook struct {
+	models.Book
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+}
+
+type Suggestion struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type CatalogService struct {
+	db     *gorm.DB
+	redis  *redis.Client
+	config *config.Config
+}
+
+func NewCatalogService(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *CatalogService {
+	return &CatalogService{
+		db:     db,
+		redis:  redisClient,
+		config: cfg,
+	}
+}
+
+func MatchBooks(query *gorm.DB, search, language string) *gorm.DB {
+	search = strings.TrimSpace(search)
+	
+	code := search
+	if normalized, err := isbn.Normalize(search); err == nil {
+		code = normalized
+	}
+	
+	return query.Where("(books.search_vector @@ "+bookTSQuery+" OR books.isbn = ? OR ? <% books.title OR ? <% books.author)",
+		language, search, search, code, search, search)
+}
+
+func RankBooks(query *gorm.DB, search, language string) *gorm.DB {
+	search = strings.TrimSpace(search)
+	return query.Select(
+		"books.*, "+
+			"ts_rank_cd(books.search_vector, "+bookTSQuery+", 32) + "+
+			"0.5 * greatest(word_similarity(?, books.title), word_similarity(?, books.author)) AS rank, "+
+			"ts_headline(books_search_config(books.language), books.title, "+bookTSQuery+
+			", 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight, "+
+			"ts_headline(books_search_config(books.language), coalesce(books.description, ''), "+bookTSQuery+
+			", 'MaxFragments=2, MinWords=8, MaxWords=25, StartSel=<mark>, StopSel=</mark>') AS snippet",
+		language, search, search, search, search,
+		language, search, search,
+		language, search, search,
+	)
+}
+
+func (s *CatalogService) DidYouMean(search string, limit int) []string {
+	search = strings.TrimSpace(search)
+	if search == "" {
+		return nil
+	}
+	
+	// %> compares against pg_trgm.word_similarity_threshold, which is set
+	// for this transaction only, and unlike a word_similarity filter it can
+	// use the trigram indexes on title and author.
+	var values []string
+	s.db.Transaction(func(tx *gorm.DB) error {
+		threshold := strconv.FormatFloat(s.config.Search.SuggestionThreshold, 'f', -1, 64)
+		if err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)", threshold).Error; err != nil {
+			return err
+		}
+		return tx.Raw(`SELECT value FROM (
+				SELECT title AS value, word_similarity(?, title) AS score FROM books
+				WHERE deleted_at IS NULL AND title %> ?
+				UNION
+				SELECT author AS value, word_similarity(?, author) AS score FROM books
+				WHERE deleted_at IS NULL AND author %> ?
+			) candidates
+			ORDER BY score DESC, value ASC
+			LIMIT ?`,
+			search, search, search, search, limit*2).
+			Scan(&values).Error
+	})
+	
+	seen := make(map[string]bool)
+	suggestions := make([]string, 0, limit)
+	for _, v := range values {
+		if seen[strings.ToLower(v)] || strings.EqualFold(v, search) {
+			continue
+		}
+		seen[strings.ToLower(v)] = true
+		suggestions = append(suggestions, v)
+		if len(suggestions) == limit {
+			break
+		}
+	}
+	
+	return suggestions
+}
+
+func (s *CatalogService) Autocomplete(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return []Suggestion{}, nil
+	}
+	
+	var key string
+	if s.redis != nil {
+		generation, _ := s.redis.Get(ctx, autocompleteGenerationKey).Result()
+		key = "autocomplete:" + generation + ":" + strings.ToLower(prefix) + ":" + strconv.Itoa(limit)
+		if cached, err := s.redis.Get(ctx, key).Result(); err == nil {
+			var suggestions []Suggestion
+			if json.Unmarshal([]byte(cached), &suggestions) == nil {
+				return suggestions, nil
+			}
+		}
+	}
+	
+	pattern := escapeLike(prefix) + "%"
+	wordPattern := "% " + pattern
+	
+	var suggestions []Suggestion
+	err := s.db.Raw(`SELECT type, value FROM (
+			SELECT DISTINCT 'title' AS type, title AS value, similarity(title, ?) AS score FROM books
+			WHERE deleted_at IS NULL AND (title ILIKE ? OR title ILIKE ?)
+			UNION ALL
+			SELECT 'author' AS type, name AS value, similarity(name, ?) AS score FROM authors
+			WHERE (name ILIKE ? OR name ILIKE ?)
+				AND EXISTS (SELECT 1 FROM book_authors JOIN books ON books.id = book_authors.book_id AND books.deleted_at IS NULL
+					WHERE book_authors.author_id = authors.id)
+			UNION ALL
+			SELECT 'series' AS type, name AS value, similarity(name, ?) AS score FROM series
+			WHERE (name ILIKE ? OR name ILIKE ?)
+				AND EXISTS (SELECT 1 FROM book_series JOIN books ON books.id = book_series.book_id AND books.deleted_at IS NULL
+					WHERE book_series.series_id = series.id)
+		) matches
+		ORDER BY score DESC, value ASC
+		LIMIT ?`,
+		prefix, pattern, wordPattern,
+		prefix, pattern, wordPattern,
+		prefix, pattern, wordPattern,
+		limit).
+		Scan(&suggestions).Error
+	if err != nil {
+		return nil, err
+	}
+	
+	if s.redis != nil {
+		if data, err := json.Marshal(suggestions); err == nil {
+			s.redis.Set(ctx, key, data, s.config.Search.AutocompleteCacheTTL)
+		}
+	}
+	
+	return suggestions, nil
+}
+
+// InvalidateAutocomplete retires cached suggestions after the catalog
+// changes. Cache keys carry a generation number, so bumping it drops every
+// entry at once and the old ones simply expire.
+func (s *CatalogService) InvalidateAutocomplete(ctx context.Context) {
+	invalidateAutocomplete(ctx, s.redis)
+}
+
+// invalidateAutocomplete bumps the generation that prefixes every cached
+// autocomplete key, so services that write books can drop stale suggestions.
+func invalidateAutocomplete(ctx context.Context, client *redis.Client) {
+	if client != nil {
+		client.Incr(ctx, autocompleteGenerationKey)
+	}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,642 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSerialNotFound        = errors.New("serial not found")
+	ErrSerialExists          = errors.New("a serial with this issn already exists")
+	ErrSerialInactive        = errors.New("serial is inactive")
+	ErrSerialISSN            = errors.New("invalid issn")
+	ErrSerialFrequency       = errors.New("frequency must be weekly, monthly or quarterly")
+	ErrSubscriptionNotFound  = errors.New("subscription not found")
+	ErrSubscriptionCancelled = errors.New("subscription is cancelled")
+	ErrIssueNotFound         = errors.New("issue not found")
+	ErrIssueReceived         = errors.New("issue has already been checked in")
+	ErrNoExpectedIssue       = errors.New("no issue is expected on this subscription")
+	ErrCheckInTarget         = errors.New("give an issue_id or a subscription_id")
+	ErrIssueNotCirculating   = errors.New("issues of this serial do not circulate")
+	ErrIssueUnavailable      = errors.New("no copy of this issue is available for loan")
+	ErrIssueLoanNotFound     = errors.New("issue loan not found")
+	ErrIssueRenewalLimit     = errors.New("issue loan has reached its renewal limit")
+	ErrIssueLoanOverdue      = errors.New("overdue issue loans cannot be renewed")
+)
+
+const maxPredictedIssues = 520
+
+var openIssueStatuses = []models.IssueStatus{models.IssueExpected, models.IssueClaimed}
+
+type SerialService struct {
+	db     *gorm.DB
+	config *config.Config
+}
+
+func NewSerialService(db *gorm.DB, cfg *config.Config) *SerialService {
+	return &SerialService{db: db, config: cfg}
+}
+
+func (s *SerialService) Subscriptions(serialID, branchID, status string) ([]models.SerialSubscription, error) {
+	query := s.db.Preload("Serial")
+	if serialID != "" {
+		query = query.Where("serial_id = ?", serialID)
+	}
+	if branchID != "" {
+		query = query.Where("branch_id = ?", branchID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	
+	var subscriptions []models.SerialSubscription
+	err := query.Order("start_date DESC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (s *SerialService) CreateSubscription(req models.SubscriptionRequest) (*models.SerialSubscription, error) {
+	subscription := models.SerialSubscription{
+		Status:          models.SubscriptionActive,
+		Frequency:       req.Frequency,
+		StartDate:       req.StartDate,
+		EndDate:         req.EndDate,
+		FirstIssueDate:  req.FirstIssueDate,
+		FirstVolume:     req.FirstVolume,
+		FirstNumber:     req.FirstNumber,
+		IssuesPerVolume: req.IssuesPerVolume,
+		Copies:          req.Copies,
+		ClaimAfterDays:  req.ClaimAfterDays,
+		Notes:           req.Notes,
+	}
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var serial models.Serial
+		if err := tx.First(&serial, "id = ?", req.SerialID).Error; err != nil {
+			return ErrSerialNotFound
+		}
+		if !serial.IsActive {
+			return ErrSerialInactive
+		}
+		subscription.SerialID = serial.ID
+		
+		branch, err := ResolveBranch(tx, req.BranchID)
+		if err != nil {
+			return err
+		}
+		subscription.BranchID = branch.ID
+		
+		if req.VendorID != "" {
+			var vendor models.Vendor
+			if err := tx.First(&vendor, "id = ?", req.VendorID).Error; err != nil {
+				return ErrVendorNotFound
+			}
+			subscription.VendorID = &vendor.ID
+		}
+		
+		if subscription.Frequency == "" {
+			subscription.Frequency = serial.Frequency
+		}
+		if !subscription.Frequency.Valid() {
+			return ErrSerialFrequency
+		}
+		if subscription.StartDate.IsZero() {
+			subscription.StartDate = time.Now().Truncate(24 * time.Hour)
+		}
+		if subscription.FirstIssueDate.IsZero() {
+			subscription.FirstIssueDate = subscription.StartDate
+		}
+		if subscription.FirstVolume == 0 {
+			subscription.FirstVolume = 1
+		}
+		if subscription.FirstNumber == 0 {
+			subscription.FirstNumber = 1
+		}
+		if subscription.Copies == 0 {
+			subscription.Copies = 1
+		}
+		if subscription.ClaimAfterDays == 0 {
+			subscription.ClaimAfterDays = 14
+		}
+		
+		if err := tx.Omit("Serial").Create(&subscription).Error; err != nil {
+			return err
+		}
+		_, err = s.predict(tx, &subscription, s.horizon())
+		return err
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (s *SerialService) CancelSubscription(id string) (*models.SerialSubscription, error) {
+	var subscription models.SerialSubscription
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&subscription, "id = ?", id).Error; err != nil {
+			return ErrSubscriptionNotFound
+		}
+		if subscription.Status == models.SubscriptionCancelled {
+			return ErrSubscriptionCancelled
+		}
+		
+		now := time.Now()
+		subscription.Status = models.SubscriptionCancelled
+		subscription.EndDate = &now
+		if err := tx.Save(&subscription).Error; err != nil {
+			return err
+		}
+		return tx.Where("subscription_id = ? AND status = ? AND expected_date > ?", subscription.ID, models.IssueExpected, now).
+			Delete(&models.SerialIssue{}).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (s *SerialService) Predict(subscriptionID string, through time.Time) ([]models.SerialIssue, error) {
+	if through.IsZero() {
+		through = s.horizon()
+	}
+	
+	var issues []models.SerialIssue
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var subscription models.SerialSubscription
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&subscription, "id = ?", subscriptionID).Error; err != nil {
+			return ErrSubscriptionNotFound
+		}
+		if subscription.Status == models.SubscriptionCancelled {
+			return ErrSubscriptionCancelled
+		}
+		
+		var err error
+		issues, err = s.predict(tx, &subscription, through)
+		return err
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+func (s *SerialService) PredictDue() (int, error) {
+	var subscriptions []models.SerialSubscription
+	if err := s.db.Where("status = ?", models.SubscriptionActive).Find(&subscriptions).Error; err != nil {
+		return 0, err
+	}
+	
+	count := 0
+	through := s.horizon()
+	for i := range subscriptions {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			issues, err := s.predict(tx, &subscriptions[i], through)
+			count += len(issues)
+			return err
+		})
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+func (s *SerialService) Issues(serialID, subscriptionID, status string) ([]models.SerialIssue, error) {
+	query := s.db.Model(&models.SerialIssue{})
+	if serialID != "" {
+		query = query.Where("serial_id = ?", serialID)
+	}
+	if subscriptionID != "" {
+		query = query.Where("subscription_id = ?", subscriptionID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	
+	var issues []models.SerialIssue
+	err := query.Order("expected_date DESC").Find(&issues).Error
+	return issues, err
+}
+
+func (s *SerialService) CheckIn(req models.CheckInRequest) (*models.SerialIssue, error) {
+	var issue models.SerialIssue
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		receivedDate := req.ReceivedDate
+		if receivedDate.IsZero() {
+			receivedDate = time.Now()
+		}
+		
+		var subscription models.SerialSubscription
+		switch {
+		case req.IssueID != "":
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&issue, "id = ?", req.IssueID).Error; err != nil {
+				return ErrIssueNotFound
+			}
+			if err := tx.First(&subscription, "id = ?", issue.SubscriptionID).Error; err != nil {
+				return ErrSubscriptionNotFound
+			}
+		case req.SubscriptionID != "":
+			if err := tx.First(&subscription, "id = ?", req.SubscriptionID).Error; err != nil {
+				return ErrSubscriptionNotFound
+			}
+			
+			query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("subscription_id = ?", subscription.ID)
+			if req.Volume > 0 || req.Number > 0 {
+				query = query.Where("volume = ? AND number = ?", req.Volume, req.Number)
+			} else {
+				query = query.Where("status IN ?", openIssueStatuses).Order("expected_date ASC")
+			}
+			
+			err := query.First(&issue).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if req.Volume == 0 && req.Number == 0 {
+					return ErrNoExpectedIssue
+				}
+				issue = models.SerialIssue{
+					SerialID:       subscription.SerialID,
+					SubscriptionID: subscription.ID,
+					Volume:         req.Volume,
+					Number:         req.Number,
+					ExpectedDate:   receivedDate,
+				}
+			} else if err != nil {
+				return err
+			}
+		default:
+			return ErrCheckInTarget
+		}
+		
+		if issue.Status == models.IssueReceived {
+			return ErrIssueReceived
+		}
+		
+		copies := req.Copies
+		if copies == 0 {
+			copies = subscription.Copies
+		}
+		issue.Receive(copies, receivedDate)
+		if req.Barcode != "" {
+			issue.Barcode = strings.TrimSpace(req.Barcode)
+		}
+		if req.Label != "" {
+			issue.Label = req.Label
+		} else if issue.Label == "" {
+			issue.Label = models.IssueLabel(issue.Volume, issue.Number, issue.ExpectedDate)
+		}
+		if req.Notes != "" {
+			issue.Notes = req.Notes
+		}
+		
+		if issue.ID == uuid.Nil {
+			return tx.Omit("Serial").Create(&issue).Error
+		}
+		return tx.Omit("Serial").Save(&issue).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+func (s *SerialService) ClaimsDue(branchID string) ([]models.IssueClaim, error) {
+	now := time.Now()
+	query := s.db.Model(&models.SerialIssue{}).Preload("Serial").
+		Joins("JOIN serial_subscriptions ON serial_subscriptions.id = serial_issues.subscription_id").
+		Where("serial_issues.status IN ?", openIssueStatuses).
+		Where("serial_issues.expected_date + make_interval(days => serial_subscriptions.claim_after_days) < ?", now).
+		Where("serial_issues.last_claimed_at IS NULL OR serial_issues.last_claimed_at + make_interval(days => serial_subscriptions.claim_after_days) < ?", now)
+	if branchID != "" {
+		query = query.Where("serial_subscriptions.branch_id = ?", branchID)
+	}
+	
+	var issues []models.SerialIssue
+	if err := query.Order("serial_issues.expected_date ASC").Find(&issues).Error; err != nil {
+		return nil, err
+	}
+	
+	vendors := make(map[uuid.UUID]*uuid.UUID)
+	claims := make([]models.IssueClaim, 0, len(issues))
+	for _, issue := range issues {
+		vendorID, ok := vendors[issue.SubscriptionID]
+		if !ok {
+			var subscription models.SerialSubscription
+			if err := s.db.First(&subscription, "id = ?", issue.SubscriptionID).Error; err == nil {
+				vendorID = subscription.VendorID
+			}
+			vendors[issue.SubscriptionID] = vendorID
+		}
+		
+		claims = append(claims, models.IssueClaim{
+			Issue:       issue,
+			SerialTitle: issue.Serial.Title,
+			ISSN:        issue.Serial.ISSN,
+			VendorID:    vendorID,
+			DaysLate:    int(now.Sub(issue.ExpectedDate).Hours() / 24),
+		})
+	}
+	return claims, nil
+}
+
+func (s *SerialService) Claim(req models.IssueActionRequest) (*models.IssueActionResult, error) {
+	return s.updateIssues(req.IssueIDs, func(issue *models.SerialIssue) {
+		now := time.Now()
+		issue.Status = models.IssueClaimed
+		issue.ClaimCount++
+		issue.LastClaimedAt = &now
+		if req.Notes != "" {
+			issue.Notes = req.Notes
+		}
+	})
+}
+
+func (s *SerialService) MarkMissing(req models.IssueActionRequest) (*models.IssueActionResult, error) {
+	return s.updateIssues(req.IssueIDs, func(issue *models.SerialIssue) {
+		issue.Status = models.IssueMissing
+		if req.Notes != "" {
+			issue.Notes = req.Notes
+		}
+	})
+}
+
+func (s *SerialService) Loans(memberID, status string) ([]models.IssueLoan, error) {
+	query := s.db.Preload("Issue").Preload("Issue.Serial")
+	if memberID != "" {
+		query = query.Where("member_id = ?", memberID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	
+	var loans []models.IssueLoan
+	err := query.Order("loan_date DESC").Find(&loans).Error
+	return loans, err
+}
+
+func (s *SerialService) Checkout(req models.IssueCheckoutRequest, issuedByID uuid.UUID) (*models.IssueLoan, error) {
+	var loan models.IssueLoan
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var issue models.SerialIssue
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Serial")
+		if req.IssueID != "" {
+			query = query.Where("id = ?", req.IssueID)
+		} else {
+			query = query.Where("barcode = ? AND status = ?", strings.TrimSpace(req.Barcode), models.IssueReceived).Order("received_date DESC")
+		}
+		if err := query.First(&issue).Error; err != nil {
+			return ErrIssueNotFound
+		}
+		
+		if !issue.Serial.Circulates {
+			return ErrIssueNotCirculating
+		}
+		if issue.Status != models.IssueReceived || issue.AvailableCopies < 1 {
+			return ErrIssueUnavailable
+		}
+		
+		var member models.Member
+		memberQuery := tx.Clauses(clause.Locking{Strength: "UPDATE"})
+		if req.MemberID != "" {
+			memberQuery = memberQuery.Where("id = ?", req.MemberID)
+		} else {
+			memberQuery = memberQuery.Where("membership_id = ?", strings.TrimSpace(req.MembershipID))
+		}
+		if err := memberQuery.First(&member).Error; err != nil {
+			return ErrMemberNotFound
+		}
+		if !member.IsActive || member.IsExpired() {
+			return ErrMemberInactive
+		}
+		if !member.CanBorrowMore() {
+			return ErrLoanLimitReached
+		}
+		
+		branchID := uuid.Nil
+		if req.BranchID != "" {
+			branch, err := ResolveBranch(tx, req.BranchID)
+			if err != nil {
+				return err
+			}
+			branchID = branch.ID
+		} else {
+			var subscription models.SerialSubscription
+			if err := tx.First(&subscription, "id = ?", issue.SubscriptionID).Error; err != nil {
+				return ErrSubscriptionNotFound
+			}
+			branchID = subscription.BranchID
+		}
+		
+		loanDays := issue.Serial.LoanDays
+		if loanDays <= 0 {
+			loanDays = s.config.Library.MaxLoanDays
+		}
+		
+		now := time.Now()
+		loan = models.IssueLoan{
+			IssueID:     issue.ID,
+			MemberID:    member.ID,
+			IssuedByID:  issuedByID,
+			BranchID:    branchID,
+			LoanDate:    now,
+			DueDate:     now.AddDate(0, 0, loanDays),
+			Status:      models.LoanStatusActive,
+			MaxRenewals: issue.Serial.MaxRenewals,
+		}
+		if err := tx.Omit("Issue", "Member").Create(&loan).Error; err != nil {
+			return err
+		}
+		
+		issue.AvailableCopies--
+		if err := tx.Omit("Serial").Save(&issue).Error; err != nil {
+			return err
+		}
+		
+		member.CurrentBooksIssued++
+		if err := tx.Save(&member).Error; err != nil {
+			return err
+		}
+		
+		loan.Issue = issue
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+func (s *SerialService) Return(loanID string) (*models.IssueLoan, error) {
+	var loan models.IssueLoan
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&loan, "id = ?", loanID).Error; err != nil {
+			return ErrIssueLoanNotFound
+		}
+		if loan.Status != models.LoanStatusActive {
+			return ErrLoanNotActive
+		}
+		
+		var issue models.SerialIssue
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Serial").First(&issue, "id = ?", loan.IssueID).Error; err != nil {
+			return ErrIssueNotFound
+		}
+		
+		now := time.Now()
+		loan.ActualReturnDate = &now
+		loan.FineAmount = loan.CalculateFine(issue.Serial.FinePerDay, now)
+		loan.Status = models.LoanStatusReturned
+		if err := tx.Omit("Issue", "Member").Save(&loan).Error; err != nil {
+			return err
+		}
+		
+		if issue.AvailableCopies < issue.Copies {
+			issue.AvailableCopies++
+		}
+		if err := tx.Omit("Serial").Save(&issue).Error; err != nil {
+			return err
+		}
+		
+		var member models.Member
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&member, "id = ?", loan.MemberID).Error; err == nil {
+			if member.CurrentBooksIssued > 0 {
+				member.CurrentBooksIssued--
+			}
+			member.TotalFineAmount += loan.FineAmount
+			if err := tx.Save(&member).Error; err != nil {
+				return err
+			}
+		}
+		
+		loan.Issue = issue
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+func (s *SerialService) Renew(loanID string) (*models.IssueLoan, error) {
+	var loan models.IssueLoan
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&loan, "id = ?", loanID).Error; err != nil {
+			return ErrIssueLoanNotFound
+		}
+		if loan.Status != models.LoanStatusActive {
+			return ErrLoanNotActive
+		}
+		if loan.IsOverdue() {
+			return ErrIssueLoanOverdue
+		}
+		if loan.RenewalCount >= loan.MaxRenewals {
+			return ErrIssueRenewalLimit
+		}
+		
+		var issue models.SerialIssue
+		if err := tx.Preload("Serial").First(&issue, "id = ?", loan.IssueID).Error; err != nil {
+			return ErrIssueNotFound
+		}
+		
+		loanDays := issue.Serial.LoanDays
+		if loanDays <= 0 {
+			loanDays = s.config.Library.MaxLoanDays
+		}
+		loan.DueDate = loan.DueDate.AddDate(0, 0, loanDays)
+		loan.RenewalCount++
+		loan.Issue = issue
+		return tx.Omit("Issue", "Member").Save(&loan).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+func (s *SerialService) updateIssues(ids []string, apply func(issue *models.SerialIssue)) (*models.IssueActionResult, error) {
+	result := &models.IssueActionResult{Issues: []models.SerialIssue{}, Skipped: []string{}}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			var issue models.SerialIssue
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&issue, "id = ?", id).Error; err != nil {
+				result.Skipped = append(result.Skipped, id)
+				continue
+			}
+			if !issue.IsOpen() {
+				result.Skipped = append(result.Skipped, id)
+				continue
+			}
+			
+			apply(&issue)
+			if err := tx.Save(&issue).Error; err != nil {
+				return err
+			}
+			result.Issues = append(result.Issues, issue)
+		}
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *SerialService) predict(tx *gorm.DB, subscription *models.SerialSubscription, through time.Time) ([]models.SerialIssue, error) {
+	first := subscription.FirstIssueDate
+	index := 0
+	volume, number := subscription.FirstVolume, subscription.FirstNumber
+	
+	var last models.SerialIssue
+	err := tx.Where("subscription_id = ?", subscription.ID).
+		Order("expected_date DESC").Order("volume DESC").Order("number DESC").
+		First(&last).Error
+	if err == nil {
+		if index = subscription.Frequency.IssueIndex(first, last.ExpectedDate) + 1; index < 0 {
+			index = 0
+		}
+		volume, number = subscription.NextNumber(last.Volume, last.Number)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	
+	issues := []models.SerialIssue{}
+	for ; len(issues) < maxPredictedIssues; index++ {
+		date := subscription.Frequency.IssueDate(first, index)
+		if date.After(through) || (subscription.EndDate != nil && date.After(*subscription.EndDate)) {
+			break
+		}
+		
+		issue := models.SerialIssue{
+			SerialID:       subscription.SerialID,
+			SubscriptionID: subscription.ID,
+			Volume:         volume,
+			Number:         number,
+			Label:          models.IssueLabel(volume, number, date),
+			ExpectedDate:   date,
+			Status:         models.IssueExpected,
+		}
+		if err := tx.Omit("Serial").Create(&issue).Error; err != nil {
+			return nil, err
+		}
+		issues = append(issues, issue)
+		volume, number = subscription.NextNumber(volume, number)
+	}
+	return issues, nil
+}
+
+func (s *SerialService) horizon() time.Time {
+	return time.Now().AddDate(0, s.config.Library.SerialPredictMonths, 0)
+}
@@ -0,0 +1,657 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/pkg/callnumber"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrStocktakeNotFound     = errors.New("stocktake not found")
+	ErrStocktakeClosed       = errors.New("stocktake is no longer open")
+	ErrStocktakeNotCompleted = errors.New("complete the stocktake before marking items lost")
+	ErrNothingToScan         = errors.New("no barcodes to scan")
+)
+
+type StocktakeService struct {
+	db     *gorm.DB
+	config *config.Config
+}
+
+func NewStocktakeService(db *gorm.DB, cfg *config.Config) *StocktakeService {
+	return &StocktakeService{db: db, config: cfg}
+}
+
+type stocktakeScope struct {
+	stocktake *models.Stocktake
+	root      *models.Location
+	locations map[uuid.UUID]*models.Location
+}
+
+type stocktakeEntry struct {
+	book     models.Book
+	holding  *models.BookHolding
+	usage    models.HoldingResponse
+	inScope  bool
+	scanned  int64
+	misplace int64
+	foundAt  []string
+	moveTo   *uuid.UUID
+}
+
+func (s *StocktakeService) Start(req models.StocktakeRequest, startedByID uuid.UUID) (*models.Stocktake, error) {
+	stocktake := models.Stocktake{
+		CallNumberStart: strings.TrimSpace(req.CallNumberStart),
+		CallNumberEnd:   strings.TrimSpace(req.CallNumberEnd),
+		Status:          models.StocktakeOpen,
+		Notes:           req.Notes,
+		StartedByID:     startedByID,
+	}
+	stocktake.StartSort = rangeSortKey(stocktake.CallNumberStart, s.config.Library.CallNumberScheme)
+	stocktake.EndSort = rangeSortKey(stocktake.CallNumberEnd, s.config.Library.CallNumberScheme)
+	
+	if req.LocationID != "" {
+		var location models.Location
+		if err := s.db.First(&location, "id = ?", req.LocationID).Error; err != nil {
+			return nil, ErrLocationNotFound
+		}
+		if req.BranchID != "" && req.BranchID != location.BranchID.String() {
+			return nil, ErrLocationBranch
+		}
+		stocktake.BranchID = location.BranchID
+		stocktake.LocationID = &location.ID
+		stocktake.Scope = location.Path
+	} else {
+		branch, err := ResolveBranch(s.db, req.BranchID)
+		if err != nil {
+			return nil, err
+		}
+		stocktake.BranchID = branch.ID
+	}
+	
+	if err := s.db.Create(&stocktake).Error; err != nil {
+		return nil, err
+	}
+	return &stocktake, nil
+}
+
+func (s *StocktakeService) List(branchID, status string) ([]models.Stocktake, error) {
+	query := s.db.Model(&models.Stocktake{})
+	if branchID != "" {
+		query = query.Where("branch_id = ?", branchID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	
+	var stocktakes []models.Stocktake
+	err := query.Order("created_at DESC").Find(&stocktakes).Error
+	return stocktakes, err
+}
+
+func (s *StocktakeService) Scan(req models.StocktakeScanRequest, scannedByID uuid.UUID) ([]models.StocktakeScanResult, error) {
+	if len(req.Barcodes) == 0 {
+		return nil, ErrNothingToScan
+	}
+	
+	stocktake, err := s.find(s.db, req.StocktakeID)
+	if err != nil {
+		return nil, err
+	}
+	if !stocktake.IsOpen() {
+		return nil, ErrStocktakeClosed
+	}
+	
+	scope, err := s.scope(s.db, stocktake)
+	if err != nil {
+		return nil, err
+	}
+	
+	var scanLocation *models.Location
+	if req.LocationID != "" {
+		id, err := uuid.Parse(req.LocationID)
+		if err != nil {
+			return nil, ErrLocationNotFound
+		}
+		location, ok := scope.locations[id]
+		if !ok {
+			return nil, ErrLocationBranch
+		}
+		scanLocation = location
+	} else if scope.root != nil && scope.root.Level == models.LocationShelf {
+		scanLocation = scope.root
+	}
+	
+	circulation := NewCirculationService(s.db, s.config)
+	results := make([]models.StocktakeScanResult, 0, len(req.Barcodes))
+	
+	for _, barcode := range req.Barcodes {
+		barcode = strings.TrimSpace(barcode)
+		if barcode == "" {
+			continue
+		}
+		
+		scan := models.StocktakeScan{
+			StocktakeID: stocktake.ID,
+			Barcode:     barcode,
+			ScannedByID: scannedByID,
+		}
+		if scanLocation != nil {
+			scan.LocationID = &scanLocation.ID
+		}
+		
+		result := models.StocktakeScanResult{Barcode: barcode, Finding: models.FindingUnknown, Message: "Barcode does not match any item"}
+		
+		book, copyBarcode, err := circulation.FindCopy(barcode)
+		if err == nil {
+			scan.BookID = &book.ID
+			bookID := book.ID.String()
+			result.BookID = &bookID
+			result.Title = book.Title
+		}
+		
+		// A copy barcode names one physical item, so scanning it twice must
+		// not count as a second copy on the shelf.
+		if copyBarcode != "" {
+			var seen int64
+			s.db.Model(&models.StocktakeScan{}).
+				Where("stocktake_id = ? AND barcode = ?", stocktake.ID, copyBarcode).
+				Count(&seen)
+			if seen > 0 {
+				result.Finding = models.FindingDuplicate
+				result.Message = "Copy was already scanned in this stocktake"
+				results = append(results, result)
+				continue
+			}
+		}
+		
+		if err := s.db.Create(&scan).Error; err != nil {
+			return nil, err
+		}
+		
+		if book != nil {
+			entry, err := s.entry(s.db, scope, book)
+			if err != nil {
+				return nil, err
+			}
+			result.Finding, result.Message = scope.classify(entry, scanLocation)
+		}
+		results = append(results, result)
+	}
+	
+	return results, nil
+}
+
+func (s *StocktakeService) Report(id string) (*models.StocktakeReport, error) {
+	stocktake, err := s.find(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	
+	report, _, err := s.report(s.db, stocktake)
+	return report, err
+}
+
+func (s *StocktakeService) Complete(id string) (*models.StocktakeReport, error) {
+	return s.close(id, models.StocktakeCompleted)
+}
+
+func (s *StocktakeService) Cancel(id string) (*models.StocktakeReport, error) {
+	return s.close(id, models.StocktakeCancelled)
+}
+
+func (s *StocktakeService) MarkMissingLost(req models.StocktakeActionRequest) (*models.StocktakeActionReport, error) {
+	stocktake, err := s.find(s.db, req.StocktakeID)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake.Status != models.StocktakeCompleted {
+		return nil, ErrStocktakeNotCompleted
+	}
+	
+	result := &models.StocktakeActionReport{Skipped: []string{}}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		report, _, err := s.report(tx, stocktake)
+		if err != nil {
+			return err
+		}
+		
+		selected := selectedBooks(req.BookIDs)
+		for _, item := range report.Missing {
+			if selected != nil && !selected[item.BookID] {
+				continue
+			}
+			
+			var book models.Book
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", item.BookID).Error; err != nil {
+				result.Skipped = append(result.Skipped, item.BookID)
+				continue
+			}
+			for i := int64(0); i < item.Copies; i++ {
+				book.LoseShelfCopy()
+			}
+			if err := tx.Save(&book).Error; err != nil {
+				return err
+			}
+			if err := AdjustHolding(tx, book.ID, stocktake.BranchID, -int(item.Copies)); err != nil {
+				return err
+			}
+			result.Updated++
+			result.Copies += item.Copies
+		}
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *StocktakeService) FixLocations(req models.StocktakeActionRequest) (*models.StocktakeActionReport, error) {
+	stocktake, err := s.find(s.db, req.StocktakeID)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake.Status == models.StocktakeCancelled {
+		return nil, ErrStocktakeClosed
+	}
+	
+	locations := NewLocationService(s.db, s.config)
+	result := &models.StocktakeActionReport{Skipped: []string{}}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		_, entries, err := s.report(tx, stocktake)
+		if err != nil {
+			return err
+		}
+		
+		selected := selectedBooks(req.BookIDs)
+		for _, entry := range entries {
+			bookID := entry.book.ID.String()
+			if entry.moveTo == nil {
+				continue
+			}
+			if selected != nil && !selected[bookID] {
+				continue
+			}
+			if entry.holding == nil {
+				result.Skipped = append(result.Skipped, bookID)
+				continue
+			}
+			
+			var location models.Location
+			if err := tx.First(&location, "id = ?", *entry.moveTo).Error; err != nil {
+				return err
+			}
+			if err := locations.Place(tx, entry.book.ID, stocktake.BranchID, &location); err != nil {
+				return err
+			}
+			result.Updated++
+			result.Copies += entry.scanned
+		}
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *StocktakeService) close(id string, status models.StocktakeStatus) (*models.StocktakeReport, error) {
+	stocktake, err := s.find(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if !stocktake.IsOpen() {
+		return nil, ErrStocktakeClosed
+	}
+	
+	stocktake.Close(status)
+	if err := s.db.Save(stocktake).Error; err != nil {
+		return nil, err
+	}
+	
+	report, _, err := s.report(s.db, stocktake)
+	return report, err
+}
+
+func (s *StocktakeService) find(tx *gorm.DB, id string) (*models.Stocktake, error) {
+	var stocktake models.Stocktake
+	if err := tx.First(&stocktake, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStocktakeNotFound
+		}
+		return nil, err
+	}
+	return &stocktake, nil
+}
+
+func (s *StocktakeService) scope(tx *gorm.DB, stocktake *models.Stocktake) (*stocktakeScope, error) {
+	var locations []models.Location
+	if err := tx.Where("branch_id = ?", stocktake.BranchID).Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	
+	scope := &stocktakeScope{
+		stocktake: stocktake,
+		locations: make(map[uuid.UUID]*models.Location, len(locations)),
+	}
+	for i := range locations {
+		scope.locations[locations[i].ID] = &locations[i]
+	}
+	
+	if stocktake.LocationID != nil {
+		root, ok := scope.locations[*stocktake.LocationID]
+		if !ok {
+			return nil, ErrLocationNotFound
+		}
+		scope.root = root
+	}
+	return scope, nil
+}
+
+func (s *StocktakeService) entry(tx *gorm.DB, scope *stocktakeScope, book *models.Book) (*stocktakeEntry, error) {
+	entry := &stocktakeEntry{book: *book}
+	
+	var holding models.BookHolding
+	err := tx.Where("book_id = ? AND branch_id = ?", book.ID, scope.stocktake.BranchID).First(&holding).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		entry.holding = &holding
+		entry.usage = holdingUsage(tx, holding)
+		entry.inScope = scope.covers(entry.holding, book)
+	}
+	
+	tx.Model(&models.StocktakeScan{}).
+		Where("stocktake_id = ? AND book_id = ?", scope.stocktake.ID, book.ID).
+		Count(&entry.scanned)
+	return entry, nil
+}
+
+func (s *StocktakeService) report(tx *gorm.DB, stocktake *models.Stocktake) (*models.StocktakeReport, []*stocktakeEntry, error) {
+	scope, err := s.scope(tx, stocktake)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	var scans []models.StocktakeScan
+	if err := tx.Where("stocktake_id = ?", stocktake.ID).Order("created_at ASC").Find(&scans).Error; err != nil {
+		return nil, nil, err
+	}
+	
+	var holdings []models.BookHolding
+	query := tx.Where("branch_id = ?", stocktake.BranchID)
+	if scope.root != nil {
+		query = query.Where("location_id IS NOT NULL")
+	}
+	if err := query.Find(&holdings).Error; err != nil {
+		return nil, nil, err
+	}
+	
+	holdingByBook := make(map[uuid.UUID]*models.BookHolding, len(holdings))
+	bookIDs := make(map[uuid.UUID]bool)
+	for i := range holdings {
+		holding := &holdings[i]
+		if scope.root != nil {
+			location := scope.locations[*holding.LocationID]
+			if location == nil || !scope.root.Contains(location) {
+				continue
+			}
+		}
+		holdingByBook[holding.BookID] = holding
+		bookIDs[holding.BookID] = true
+	}
+	
+	report := &models.StocktakeReport{
+		Stocktake:   *stocktake,
+		Scanned:     int64(len(scans)),
+		Missing:     []models.StocktakeItem{},
+		Misshelved:  []models.StocktakeItem{},
+		Unexpected:  []models.StocktakeItem{},
+		WrongStatus: []models.StocktakeItem{},
+		Unknown:     []string{},
+	}
+	
+	for _, scan := range scans {
+		if scan.BookID == nil {
+			report.Unknown = append(report.Unknown, scan.Barcode)
+			continue
+		}
+		bookIDs[*scan.BookID] = true
+	}
+	
+	ids := make([]uuid.UUID, 0, len(bookIDs))
+	for id := range bookIDs {
+		ids = append(ids, id)
+	}
+	
+	var books []models.Book
+	if len(ids) > 0 {
+		if err := tx.Where("id IN ?", ids).Find(&books).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+	
+	sort.Slice(books, func(i, j int) bool {
+		if books[i].CallNumberSort != books[j].CallNumberSort {
+			return books[i].CallNumberSort < books[j].CallNumberSort
+		}
+		return books[i].Title < books[j].Title
+	})
+	
+	entries := make(map[uuid.UUID]*stocktakeEntry, len(books))
+	ordered := make([]*stocktakeEntry, 0, len(books))
+	for _, book := range books {
+		entry := &stocktakeEntry{book: book}
+		
+		holding := holdingByBook[book.ID]
+		if holding == nil {
+			var other models.BookHolding
+			if err := tx.Where("book_id = ? AND branch_id = ?", book.ID, stocktake.BranchID).First(&other).Error; err == nil {
+				holding = &other
+			}
+		}
+		if holding != nil {
+			entry.holding = holding
+			entry.usage = holdingUsage(tx, *holding)
+			entry.inScope = scope.covers(holding, &book)
+		}
+		
+		entries[book.ID] = entry
+		ordered = append(ordered, entry)
+	}
+	
+	for _, scan := range scans {
+		if scan.BookID == nil {
+			continue
+		}
+		entry, ok := entries[*scan.BookID]
+		if !ok {
+			continue
+		}
+		
+		var location *models.Location
+		if scan.LocationID != nil {
+			location = scope.locations[*scan.LocationID]
+		}
+		scope.record(entry, location)
+	}
+	
+	for _, entry := range ordered {
+		scope.tally(report, entry)
+	}
+	
+	return report, ordered, nil
+}
+
+// record counts one scan of entry's title at location, noting where it was
+// found and whether the copy sits outside its home shelf.
+func (sc *stocktakeScope) record(entry *stocktakeEntry, location *models.Location) {
+	entry.scanned++
+	if location == nil {
+		return
+	}
+	
+	if !containsString(entry.foundAt, location.Path) {
+		entry.foundAt = append(entry.foundAt, location.Path)
+	}
+	switch {
+	case entry.inScope && sc.misplaced(entry, location):
+		entry.misplace++
+		entry.moveTo = &location.ID
+	case !entry.inScope && (entry.holding == nil || entry.holding.LocationID == nil || sc.misplaced(entry, location)):
+		entry.moveTo = &location.ID
+	}
+}
+
+// tally adds entry's missing, misshelved, unexpected and wrong status copies
+// to report once every scan has been recorded.
+func (sc *stocktakeScope) tally(report *models.StocktakeReport, entry *stocktakeEntry) {
+	item := models.StocktakeItem{
+		BookID:     entry.book.ID.String(),
+		Title:      entry.book.Title,
+		Barcode:    entry.book.Barcode,
+		CallNumber: entry.book.CallNumber,
+		FoundAt:    entry.foundAt,
+		Scanned:    entry.scanned,
+	}
+	if home := sc.homeLocation(entry); home != nil {
+		item.Location = home.Path
+	}
+	
+	if !entry.inScope {
+		if entry.scanned > 0 {
+			item.Copies = entry.scanned
+			item.Reason = sc.outsideReason(entry)
+			report.Unexpected = append(report.Unexpected, item)
+		}
+		return
+	}
+	
+	item.Expected = entry.usage.OnShelf
+	report.Expected += item.Expected
+	
+	if item.Expected > entry.scanned {
+		missing := item
+		missing.Copies = item.Expected - entry.scanned
+		report.Missing = append(report.Missing, missing)
+	}
+	if entry.scanned > item.Expected {
+		wrong := item
+		wrong.Copies = entry.scanned - item.Expected
+		wrong.Reason = statusReason(entry)
+		report.WrongStatus = append(report.WrongStatus, wrong)
+	}
+	if entry.misplace > 0 {
+		misshelved := item
+		misshelved.Copies = entry.misplace
+		report.Misshelved = append(report.Misshelved, misshelved)
+	}
+}
+
+func (sc *stocktakeScope) covers(holding *models.BookHolding, book *models.Book) bool {
+	if sc.root != nil {
+		if holding.LocationID == nil {
+			return false
+		}
+		location := sc.locations[*holding.LocationID]
+		if location == nil || !sc.root.Contains(location) {
+			return false
+		}
+	}
+	
+	if sc.stocktake.StartSort != "" || sc.stocktake.EndSort != "" {
+		return book.CallNumberSort != "" && callnumber.InRange(book.CallNumberSort, sc.stocktake.StartSort, sc.stocktake.EndSort)
+	}
+	return true
+}
+
+func (sc *stocktakeScope) misplaced(entry *stocktakeEntry, found *models.Location) bool {
+	if entry.holding == nil || found == nil {
+		return false
+	}
+	
+	if home := sc.homeLocation(entry); home != nil {
+		return !home.Contains(found)
+	}
+	return entry.book.CallNumberSort != "" && !found.Covers(entry.book.CallNumberSort)
+}
+
+func (sc *stocktakeScope) classify(entry *stocktakeEntry, found *models.Location) (models.StocktakeFinding, string) {
+	if !entry.inScope {
+		return models.FindingUnexpected, sc.outsideReason(entry)
+	}
+	if sc.misplaced(entry, found) {
+		if home := sc.homeLocation(entry); home != nil {
+			return models.FindingMisshelved, "Belongs at " + home.Path
+		}
+		return models.FindingMisshelved, "Call number is outside the range of " + found.Path
+	}
+	if entry.scanned > entry.usage.OnShelf {
+		return models.FindingWrongStatus, statusReason(entry)
+	}
+	return models.FindingOK, ""
+}
+
+func (sc *stocktakeScope) outsideReason(entry *stocktakeEntry) string {
+	if entry.holding == nil {
+		return "Not held at this branch"
+	}
+	
+	home := sc.homeLocation(entry)
+	switch {
+	case sc.root != nil && home == nil:
+		return "Not assigned to a shelf location"
+	case sc.root != nil && !sc.root.Contains(home):
+		return "Shelved at " + home.Path
+	default:
+		return "Call number is outside the range of this stocktake"
+	}
+}
+
+func (sc *stocktakeScope) homeLocation(entry *stocktakeEntry) *models.Location {
+	if entry.holding == nil || entry.holding.LocationID == nil {
+		return nil
+	}
+	return sc.locations[*entry.holding.LocationID]
+}
+
+func statusReason(entry *stocktakeEntry) string {
+	switch {
+	case entry.usage.OnLoan > 0:
+		return "Recorded as on loan"
+	case entry.usage.InTransit > 0:
+		return "Recorded as in transit"
+	case entry.usage.OnHoldShelf > 0:
+		return "Recorded as on the hold shelf"
+	case entry.usage.InRepair > 0:
+		return "Recorded as in repair"
+	case entry.book.Status == models.BookStatusLost || entry.usage.TotalCopies == 0:
+		return "Recorded as lost or withdrawn"
+	default:
+		return "More copies scanned than on record"
+	}
+}
+
+func selectedBooks(bookIDs []string) map[string]bool {
+	if len(bookIDs) == 0 {
+		return nil
+	}
+	
+	selected := make(map[string]bool, len(bookIDs))
+	for _, id := range bookIDs {
+		selected[strings.TrimSpace(id)] = true
+	}
+	return selected
+}
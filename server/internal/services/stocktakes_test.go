@@ -0,0 +1,261 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/pkg/callnumber"
+
+	"github.com/google/uuid"
+)
+
+type stocktakeFixture struct {
+	scope   *stocktakeScope
+	fiction *models.Location
+	science *models.Location
+	annex   *models.Location
+}
+
+func newStocktakeFixture(withRoot bool) *stocktakeFixture {
+	key := func(value string) string { return callnumber.SortKey(value, callnumber.Dewey) }
+	floor := &models.Location{ID: uuid.New(), Path: "F1"}
+	fixture := &stocktakeFixture{
+		fiction: &models.Location{ID: uuid.New(), Path: "F1/FIC", StartSort: key("800"), EndSort: key("899")},
+		science: &models.Location{ID: uuid.New(), Path: "F1/SCI", StartSort: key("500"), EndSort: key("599")},
+		annex:   &models.Location{ID: uuid.New(), Path: "F2/ANNEX"},
+	}
+	
+	fixture.scope = &stocktakeScope{
+		stocktake: &models.Stocktake{ID: uuid.New()},
+		locations: map[uuid.UUID]*models.Location{floor.ID: floor},
+	}
+	for _, location := range []*models.Location{fixture.fiction, fixture.science, fixture.annex} {
+		fixture.scope.locations[location.ID] = location
+	}
+	if withRoot {
+		fixture.scope.root = floor
+	}
+	return fixture
+}
+
+// entry builds a title held at home, or not held at the branch when held is
+// false, with the given usage.
+func (f *stocktakeFixture) entry(callNumber string, held bool, home *models.Location, usage models.HoldingResponse) *stocktakeEntry {
+	book := models.Book{
+		ID:             uuid.New(),
+		Title:          "Stocktake " + callNumber,
+		CallNumber:     callNumber,
+		CallNumberSort: callnumber.SortKey(callNumber, callnumber.Dewey),
+	}
+	entry := &stocktakeEntry{book: book, usage: usage}
+	if held {
+		entry.holding = &models.BookHolding{BookID: book.ID}
+		if home != nil {
+			entry.holding.LocationID = &home.ID
+		}
+		entry.inScope = f.scope.covers(entry.holding, &book)
+	}
+	return entry
+}
+
+func TestStocktakeTally(t *testing.T) {
+	f := newStocktakeFixture(true)
+	onShelf := func(copies int64) models.HoldingResponse {
+		return models.HoldingResponse{TotalCopies: copies, OnShelf: copies}
+	}
+	
+	tests := []struct {
+		name        string
+		entry       *stocktakeEntry
+		scans       []*models.Location
+		missing     int64
+		misshelved  int64
+		unexpected  int64
+		wrongStatus int64
+		reason      string
+		moveTo      *models.Location
+	}{
+		{
+			name:  "scanned on its home shelf",
+			entry: f.entry("823.912 ORW", true, f.fiction, onShelf(1)),
+			scans: []*models.Location{f.fiction},
+		},
+		{
+			name:    "never scanned",
+			entry:   f.entry("823.912 ORW", true, f.fiction, onShelf(1)),
+			missing: 1,
+		},
+		{
+			name:    "one of two copies scanned",
+			entry:   f.entry("823.912 ORW", true, f.fiction, onShelf(2)),
+			scans:   []*models.Location{f.fiction},
+			missing: 1,
+		},
+		{
+			name:       "scanned on another shelf",
+			entry:      f.entry("823.912 ORW", true, f.fiction, onShelf(1)),
+			scans:      []*models.Location{f.science},
+			misshelved: 1,
+			moveTo:     f.science,
+		},
+		{
+			name:  "scan without a location is not misshelved",
+			entry: f.entry("823.912 ORW", true, f.fiction, onShelf(1)),
+			scans: []*models.Location{nil},
+		},
+		{
+			name:       "shelved outside the stocktake",
+			entry:      f.entry("823.912 ORW", true, f.annex, onShelf(1)),
+			scans:      []*models.Location{f.fiction},
+			unexpected: 1,
+			reason:     "Shelved at F2/ANNEX",
+			moveTo:     f.fiction,
+		},
+		{
+			name:       "not held at this branch",
+			entry:      f.entry("823.912 ORW", false, nil, models.HoldingResponse{}),
+			scans:      []*models.Location{f.fiction},
+			unexpected: 1,
+			reason:     "Not held at this branch",
+			moveTo:     f.fiction,
+		},
+		{
+			name:  "outside the stocktake and not scanned",
+			entry: f.entry("823.912 ORW", true, f.annex, onShelf(1)),
+		},
+		{
+			name:        "scanned while on loan",
+			entry:       f.entry("823.912 ORW", true, f.fiction, models.HoldingResponse{TotalCopies: 1, OnLoan: 1}),
+			scans:       []*models.Location{f.fiction},
+			wrongStatus: 1,
+			reason:      "Recorded as on loan",
+		},
+		{
+			name:        "scanned after being withdrawn",
+			entry:       f.entry("823.912 ORW", true, f.fiction, models.HoldingResponse{}),
+			scans:       []*models.Location{f.fiction},
+			wrongStatus: 1,
+			reason:      "Recorded as lost or withdrawn",
+		},
+	}
+	
+	copies := func(items []models.StocktakeItem) int64 {
+		var total int64
+		for _, item := range items {
+			total += item.Copies
+		}
+		return total
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, location := range tt.scans {
+				f.scope.record(tt.entry, location)
+			}
+			
+			report := &models.StocktakeReport{}
+			f.scope.tally(report, tt.entry)
+			
+			if got := copies(report.Missing); got != tt.missing {
+				t.Errorf("missing = %d, want %d", got, tt.missing)
+			}
+			if got := copies(report.Misshelved); got != tt.misshelved {
+				t.Errorf("misshelved = %d, want %d", got, tt.misshelved)
+			}
+			if got := copies(report.Unexpected); got != tt.unexpected {
+				t.Errorf("unexpected = %d, want %d", got, tt.unexpected)
+			}
+			if got := copies(report.WrongStatus); got != tt.wrongStatus {
+				t.Errorf("wrong status = %d, want %d", got, tt.wrongStatus)
+			}
+			
+			if tt.reason != "" {
+				var reasons []string
+				for _, item := range append(report.Unexpected, report.WrongStatus...) {
+					reasons = append(reasons, item.Reason)
+				}
+				if len(reasons) != 1 || reasons[0] != tt.reason {
+					t.Errorf("reasons = %q, want %q", reasons, tt.reason)
+				}
+			}
+			
+			switch {
+			case tt.moveTo == nil && tt.entry.moveTo != nil:
+				t.Errorf("move suggested to %v, want none", *tt.entry.moveTo)
+			case tt.moveTo != nil && (tt.entry.moveTo == nil || *tt.entry.moveTo != tt.moveTo.ID):
+				t.Errorf("move suggested to %v, want %s", tt.entry.moveTo, tt.moveTo.Path)
+			}
+		})
+	}
+}
+
+func TestStocktakeClassify(t *testing.T) {
+	rooted := newStocktakeFixture(true)
+	branch := newStocktakeFixture(false)
+	
+	tests := []struct {
+		name    string
+		fixture *stocktakeFixture
+		entry   *stocktakeEntry
+		found   *models.Location
+		finding models.StocktakeFinding
+		message string
+	}{
+		{
+			name:    "on its home shelf",
+			fixture: rooted,
+			entry:   rooted.entry("823.912 ORW", true, rooted.fiction, models.HoldingResponse{OnShelf: 1}),
+			found:   rooted.fiction,
+			finding: models.FindingOK,
+		},
+		{
+			name:    "away from its home shelf",
+			fixture: rooted,
+			entry:   rooted.entry("823.912 ORW", true, rooted.fiction, models.HoldingResponse{OnShelf: 1}),
+			found:   rooted.science,
+			finding: models.FindingMisshelved,
+			message: "Belongs at F1/FIC",
+		},
+		{
+			name:    "no home shelf and outside the found shelf's range",
+			fixture: branch,
+			entry:   branch.entry("510 SMI", true, nil, models.HoldingResponse{OnShelf: 1}),
+			found:   branch.fiction,
+			finding: models.FindingMisshelved,
+			message: "Call number is outside the range of F1/FIC",
+		},
+		{
+			name:    "no home shelf and inside the found shelf's range",
+			fixture: branch,
+			entry:   branch.entry("510 SMI", true, nil, models.HoldingResponse{OnShelf: 1}),
+			found:   branch.science,
+			finding: models.FindingOK,
+		},
+		{
+			name:    "not assigned a shelf in a location stocktake",
+			fixture: rooted,
+			entry:   rooted.entry("823.912 ORW", true, nil, models.HoldingResponse{OnShelf: 1}),
+			found:   rooted.fiction,
+			finding: models.FindingUnexpected,
+			message: "Not assigned to a shelf location",
+		},
+		{
+			name:    "recorded in transit",
+			fixture: rooted,
+			entry:   rooted.entry("823.912 ORW", true, rooted.fiction, models.HoldingResponse{TotalCopies: 1, InTransit: 1}),
+			found:   rooted.fiction,
+			finding: models.FindingWrongStatus,
+			message: "Recorded as in transit",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.entry.scanned = 1
+			finding, message := tt.fixture.scope.classify(tt.entry, tt.found)
+			if finding != tt.finding || message != tt.message {
+				t.Fatalf("classify = %q %q, want %q %q", finding, message, tt.finding, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/pkg/isbn"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSuggestionNotFound  = errors.New("purchase suggestion not found")
+	ErrSuggestionExists    = errors.New("you have already suggested this title")
+	ErrSuggestionInCatalog = errors.New("this title is already in the catalog")
+	ErrSuggestionState     = errors.New("suggestion cannot change from its current status")
+	ErrSuggestionISBN      = errors.New("invalid isbn")
+	ErrRejectionReason     = errors.New("a reason is required to reject a suggestion")
+)
+
+var openSuggestionStatuses = []models.SuggestionStatus{models.SuggestionPending, models.SuggestionApproved, models.SuggestionOrdered}
+
+type PurchaseSuggestionService struct {
+	db          *gorm.DB
+	config      *config.Config
+	circulation *CirculationService
+}
+
+func NewPurchaseSuggestionService(db *gorm.DB, cfg *config.Config) *PurchaseSuggestionService {
+	return &PurchaseSuggestionService{
+		db:          db,
+		config:      cfg,
+		circulation: NewCirculationService(db, cfg),
+	}
+}
+
+func (s *PurchaseSuggestionService) List(status, memberID string) ([]models.PurchaseSuggestion, error) {
+	query := s.db.Preload("Member").Preload("Member.User")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if memberID != "" {
+		query = query.Where("member_id = ?", memberID)
+	}
+	
+	var suggestions []models.PurchaseSuggestion
+	err := query.Order("created_at DESC").Find(&suggestions).Error
+	return suggestions, err
+}
+
+func (s *PurchaseSuggestionService) ForUser(userID uuid.UUID) ([]models.PurchaseSuggestion, error) {
+	var member models.Member
+	if err := s.db.Where("user_id = ?", userID).First(&member).Error; err != nil {
+		return nil, ErrMemberNotFound
+	}
+	
+	var suggestions []models.PurchaseSuggestion
+	err := s.db.Where("member_id = ?", member.ID).Order("created_at DESC").Find(&suggestions).Error
+	return suggestions, err
+}
+
+func (s *PurchaseSuggestionService) Submit(userID uuid.UUID, req models.SuggestionRequest) (*models.PurchaseSuggestion, error) {
+	var member models.Member
+	if err := s.db.Where("user_id = ?", userID).First(&member).Error; err != nil {
+		return nil, ErrMemberNotFound
+	}
+	
+	suggestion := models.PurchaseSuggestion{
+		MemberID:  member.ID,
+		Title:     strings.TrimSpace(req.Title),
+		Author:    strings.TrimSpace(req.Author),
+		Publisher: strings.TrimSpace(req.Publisher),
+		Reason:    req.Reason,
+		Status:    models.SuggestionPending,
+	}
+	if strings.TrimSpace(req.ISBN) != "" {
+		normalized, err := isbn.Normalize(req.ISBN)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSuggestionISBN, err)
+		}
+		suggestion.ISBN = normalized
+	}
+	
+	book, err := s.catalogMatch(s.db, suggestion.ISBN, suggestion.Title, suggestion.Author)
+	if err != nil {
+		return nil, err
+	}
+	if book != nil {
+		return nil, fmt.Errorf("%w: %s (book %s)", ErrSuggestionInCatalog, book.Title, book.ID)
+	}
+	
+	var earlier []models.PurchaseSuggestion
+	if err := s.matching(s.db, suggestion.ISBN, suggestion.Title).
+		Where("status IN ?", openSuggestionStatuses).
+		Order("created_at ASC").Find(&earlier).Error; err != nil {
+		return nil, err
+	}
+	for _, other := range earlier {
+		if other.MemberID == member.ID {
+			return nil, ErrSuggestionExists
+		}
+	}
+	if len(earlier) > 0 {
+		suggestion.DuplicateOfID = &earlier[0].ID
+	}
+	
+	if err := s.db.Create(&suggestion).Error; err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+func (s *PurchaseSuggestionService) Approve(req models.SuggestionReviewRequest, reviewerID uuid.UUID) (*models.PurchaseSuggestion, error) {
+	return s.review(req.SuggestionID, func(tx *gorm.DB, suggestion *models.PurchaseSuggestion) error {
+		if suggestion.Status != models.SuggestionPending {
+			return ErrSuggestionState
+		}
+		suggestion.Review(models.SuggestionApproved, reviewerID)
+		return nil
+	})
+}
+
+func (s *PurchaseSuggestionService) Reject(req models.SuggestionReviewRequest, reviewerID uuid.UUID) (*models.PurchaseSuggestion, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, ErrRejectionReason
+	}
+	
+	return s.review(req.SuggestionID, func(tx *gorm.DB, suggestion *models.PurchaseSuggestion) error {
+		if suggestion.Status != models.SuggestionPending && suggestion.Status != models.SuggestionApproved {
+			return ErrSuggestionState
+		}
+		suggestion.Review(models.SuggestionRejected, reviewerID)
+		suggestion.RejectionReason = strings.TrimSpace(req.Reason)
+		return nil
+	})
+}
+
+func (s *PurchaseSuggestionService) MarkOrdered(req models.SuggestionReviewRequest, reviewerID uuid.UUID) (*models.PurchaseSuggestion, error) {
+	return s.review(req.SuggestionID, func(tx *gorm.DB, suggestion *models.PurchaseSuggestion) error {
+		if suggestion.Status != models.SuggestionPending && suggestion.Status != models.SuggestionApproved {
+			return ErrSuggestionState
+		}
+		if req.OrderLineID != "" {
+			var line models.PurchaseOrderLine
+			if err := tx.First(&line, "id = ?", req.OrderLineID).Error; err != nil {
+				return ErrOrderLineNotFound
+			}
+			suggestion.OrderLineID = &line.ID
+		}
+		suggestion.Review(models.SuggestionOrdered, reviewerID)
+		return nil
+	})
+}
+
+func (s *PurchaseSuggestionService) Fulfill(tx *gorm.DB, book *models.Book, branchID uuid.UUID) (int, error) {
+	var suggestions []models.PurchaseSuggestion
+	if err := s.matching(tx, book.ISBN, book.Title).
+		Where("status IN ?", openSuggestionStatuses).
+		Order("created_at ASC").Find(&suggestions).Error; err != nil {
+		return 0, err
+	}
+	if len(suggestions) == 0 {
+		return 0, nil
+	}
+	
+	now := time.Now()
+	for i := range suggestions {
+		suggestion := &suggestions[i]
+		reservation, err := s.reserve(tx, book, suggestion.MemberID)
+		if err != nil {
+			return 0, err
+		}
+		
+		suggestion.Status = models.SuggestionFulfilled
+		suggestion.BookID = &book.ID
+		suggestion.ReservationID = &reservation.ID
+		suggestion.FulfilledAt = &now
+		if err := tx.Omit("Member").Save(suggestion).Error; err != nil {
+			return 0, err
+		}
+	}
+	
+	onShelf, err := s.circulation.onShelfAt(tx, book.ID, branchID)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(suggestions) && int64(i) < onShelf && book.AvailableCopies > 0; i++ {
+		book.DecrementAvailable()
+		if _, _, err := s.circulation.trapNextHold(tx, book, branchID, branchID); err != nil {
+			return 0, err
+		}
+	}
+	
+	return len(suggestions), nil
+}
+
+func (s *PurchaseSuggestionService) reserve(tx *gorm.DB, book *models.Book, memberID uuid.UUID) (*models.Reservation, error) {
+	var reservation models.Reservation
+	err := tx.Where("book_id = ? AND member_id = ? AND status IN ?",
+		book.ID, memberID, []models.ReservationStatus{models.ReservationStatusPending, models.ReservationStatusInTransit, models.ReservationStatusReadyForPickup}).
+		First(&reservation).Error
+	if err == nil {
+		return &reservation, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	
+	var member models.Member
+	if err := tx.First(&member, "id = ?", memberID).Error; err != nil {
+		return nil, ErrMemberNotFound
+	}
+	
+	var queuePosition int64
+	tx.Model(&models.Reservation{}).
+		Where("book_id = ? AND status = ?", book.ID, models.ReservationStatusPending).
+		Count(&queuePosition)
+	
+	reservation = models.Reservation{
+		BookID:        book.ID,
+		MemberID:      member.ID,
+		Status:        models.ReservationStatusPending,
+		QueuePosition: int(queuePosition) + 1,
+		Notes:         "Placed automatically for a purchase suggestion",
+	}
+	if member.HomeBranchID != nil {
+		if branch, err := ResolveBranch(tx, member.HomeBranchID.String()); err == nil {
+			reservation.PickupBranchID = &branch.ID
+			reservation.PickupLocation = branch.Name
+		}
+	}
+	
+	if err := tx.Create(&reservation).Error; err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (s *PurchaseSuggestionService) review(id string, apply func(tx *gorm.DB, suggestion *models.PurchaseSuggestion) error) (*models.PurchaseSuggestion, error) {
+	var suggestion models.PurchaseSuggestion
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&suggestion, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSuggestionNotFound
+			}
+			return err
+		}
+		if err := apply(tx, &suggestion); err != nil {
+			return err
+		}
+		return tx.Omit("Member").Save(&suggestion).Error
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+func (s *PurchaseSuggestionService) matching(tx *gorm.DB, isbn, title string) *gorm.DB {
+	if isbn != "" {
+		return tx.Where("isbn = ? OR (isbn = '' AND LOWER(title) = LOWER(?))", isbn, title)
+	}
+	return tx.Where("LOWER(title) = LOWER(?)", title)
+}
+
+func (s *PurchaseSuggestionService) catalogMatch(tx *gorm.DB, isbn, title, author string) (*models.Book, error) {
+	var book models.Book
+	if isbn != "" {
+		err := tx.Where("isbn = ?", isbn).First(&book).Error
+		if err == nil {
+			return &book, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	
+	query := tx.Where("LOWER(title) = LOWER(?)", title)
+	if author != "" {
+		query = query.Where("author ILIKE ?", "%"+author+"%")
+	}
+	err := query.First(&book).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
@@ -0,0 +1,462 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrWeedingCriteria  = errors.New("give book_ids or a number of years without circulation")
+	ErrWithdrawalReason = errors.New("a reason is required to withdraw items")
+)
+
+const uncategorized = "Uncategorized"
+
+type ageBand struct {
+	label  string
+	maxAge int
+}
+
+var ageBands = []ageBand{
+	{"0-4 years", 4},
+	{"5-9 years", 9},
+	{"10-19 years", 19},
+	{"20-49 years", 49},
+	{"50+ years", math.MaxInt32},
+}
+
+type WeedingService struct {
+	db          *gorm.DB
+	config      *config.Config
+	circulation *CirculationService
+}
+
+func NewWeedingService(db *gorm.DB, cfg *config.Config) *WeedingService {
+	return &WeedingService{
+		db:          db,
+		config:      cfg,
+		circulation: NewCirculationService(db, cfg),
+	}
+}
+
+func (s *WeedingService) Dormant(years int, branchID, category string, limit int) ([]models.DormantItem, error) {
+	return s.dormant(s.db, years, branchID, category, limit)
+}
+
+func (s *WeedingService) Turnover(years int, threshold float64) (*models.TurnoverReport, error) {
+	var rows []struct {
+		Category string
+		Titles   int64
+		Copies   int64
+		Loans    int64
+	}
+	since := time.Now().AddDate(-years, 0, 0)
+	if err := s.db.Raw(`SELECT COALESCE(NULLIF(b.category, ''), ?) AS category, COUNT(*) AS titles,
+			COALESCE(SUM(b.total_copies), 0) AS copies, COALESCE(SUM(l.loans), 0) AS loans
+		FROM books b
+		LEFT JOIN (
+			SELECT book_id, COUNT(*) AS loans FROM loans
+			WHERE loan_date >= ? AND deleted_at IS NULL
+			GROUP BY book_id
+		) l ON l.book_id = b.id
+		WHERE b.deleted_at IS NULL AND b.total_copies > 0
+		GROUP BY 1`, uncategorized, since).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	
+	report := &models.TurnoverReport{Years: years, Categories: []models.CategoryTurnover{}}
+	var copies, loans int64
+	for _, row := range rows {
+		copies += row.Copies
+		loans += row.Loans
+		report.Categories = append(report.Categories, models.CategoryTurnover{
+			Category: row.Category,
+			Titles:   row.Titles,
+			Copies:   row.Copies,
+			Loans:    row.Loans,
+			Turnover: turnover(row.Loans, row.Copies, years),
+		})
+	}
+	
+	report.Overall = turnover(loans, copies, years)
+	report.Threshold = flagLowTurnover(report.Categories, report.Overall, threshold)
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		return report.Categories[i].Turnover < report.Categories[j].Turnover
+	})
+	return report, nil
+}
+
+func (s *WeedingService) CollectionAge(branchID, category string) (*models.CollectionAgeReport, error) {
+	var rows []struct {
+		Category string
+		Year     int
+		Titles   int64
+		Copies   int64
+	}
+	query := s.db.Table("books").
+		Select("COALESCE(NULLIF(books.category, ''), ?) AS category, COALESCE(EXTRACT(YEAR FROM books.publish_date), 0)::int AS year, COUNT(*) AS titles, COALESCE(SUM(books.total_copies), 0) AS copies", uncategorized).
+		Where("books.deleted_at IS NULL AND books.total_copies > 0")
+	if branchID != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM book_holdings h WHERE h.book_id = books.id AND h.branch_id = ? AND h.total_copies > 0)", branchID)
+	}
+	if category != "" {
+		query = query.Where("books.category = ?", category)
+	}
+	if err := query.Group("1, 2").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	
+	report := &models.CollectionAgeReport{Categories: []models.CategoryAge{}}
+	unknown := models.AgeBand{Label: "unknown"}
+	bands := make([]models.AgeBand, len(ageBands))
+	for i, band := range ageBands {
+		bands[i].Label = band.label
+	}
+	
+	type yearCount struct {
+		year   int
+		titles int64
+	}
+	byCategory := make(map[string][]yearCount)
+	currentYear := time.Now().Year()
+	var dated, totalAge, titles int64
+	
+	for _, row := range rows {
+		titles += row.Titles
+		if row.Year <= 1 {
+			unknown.Titles += row.Titles
+			unknown.Copies += row.Copies
+			continue
+		}
+		
+		age := currentYear - row.Year
+		if age < 0 {
+			age = 0
+		}
+		band := &bands[ageBandIndex(age)]
+		band.Titles += row.Titles
+		band.Copies += row.Copies
+		dated += row.Titles
+		totalAge += int64(age) * row.Titles
+		byCategory[row.Category] = append(byCategory[row.Category], yearCount{row.Year, row.Titles})
+	}
+	
+	if unknown.Titles > 0 {
+		bands = append(bands, unknown)
+	}
+	for i := range bands {
+		if titles > 0 {
+			bands[i].Share = float64(bands[i].Titles) / float64(titles)
+		}
+	}
+	report.Bands = bands
+	if dated > 0 {
+		report.AverageAge = float64(totalAge) / float64(dated)
+	}
+	
+	for category, years := range byCategory {
+		sort.Slice(years, func(i, j int) bool { return years[i].year < years[j].year })
+		
+		summary := models.CategoryAge{Category: category, OldestYear: years[0].year}
+		var ageSum int64
+		for _, y := range years {
+			summary.Titles += y.titles
+			ageSum += int64(currentYear-y.year) * y.titles
+		}
+		summary.AverageAge = float64(ageSum) / float64(summary.Titles)
+		
+		var seen int64
+		for _, y := range years {
+			seen += y.titles
+			if seen*2 >= summary.Titles {
+				summary.MedianYear = y.year
+				break
+			}
+		}
+		report.Categories = append(report.Categories, summary)
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		return report.Categories[i].AverageAge > report.Categories[j].AverageAge
+	})
+	return report, nil
+}
+
+func (s *WeedingService) Demand(minHolds int, holdsPerCopy float64) ([]models.DemandRatio, error) {
+	var rows []struct {
+		BookID      string
+		Title       string
+		Author      string
+		TotalCopies int
+		Holds       int64
+	}
+	if err := s.db.Table("books").
+		Select("books.id AS book_id, books.title, books.author, books.total_copies, COUNT(reservations.id) AS holds").
+		Joins("JOIN reservations ON reservations.book_id = books.id AND reservations.deleted_at IS NULL AND reservations.status IN ?",
+			[]models.ReservationStatus{models.ReservationStatusPending, models.ReservationStatusInTransit}).
+		Where("books.deleted_at IS NULL").
+		Group("books.id").
+		Having("COUNT(reservations.id) >= ?", minHolds).
+		Order("COUNT(reservations.id)::float / GREATEST(books.total_copies, 1) DESC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	
+	ratios := make([]models.DemandRatio, 0, len(rows))
+	for _, row := range rows {
+		ratios = append(ratios, demandRatio(models.DemandRatio{
+			BookID:      row.BookID,
+			Title:       row.Title,
+			Author:      row.Author,
+			TotalCopies: row.TotalCopies,
+			Holds:       row.Holds,
+		}, holdsPerCopy))
+	}
+	return ratios, nil
+}
+
+func (s *WeedingService) Candidates(status, branchID string) ([]models.WeedingCandidate, error) {
+	query := s.db.Preload("Book")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if branchID != "" {
+		query = query.Where("branch_id = ?", branchID)
+	}
+	
+	var candidates []models.WeedingCandidate
+	err := query.Order("created_at DESC").Find(&candidates).Error
+	return candidates, err
+}
+
+func (s *WeedingService) Propose(req models.WeedingProposalRequest, proposedByID uuid.UUID) (*models.WeedingResult, error) {
+	if len(req.BookIDs) == 0 && req.Years <= 0 {
+		return nil, ErrWeedingCriteria
+	}
+	
+	result := &models.WeedingResult{Candidates: []models.WeedingCandidate{}, Skipped: []string{}}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		branch, err := ResolveBranch(tx, req.BranchID)
+		if err != nil {
+			return err
+		}
+		
+		criteria := strings.TrimSpace(req.Criteria)
+		bookIDs := req.BookIDs
+		if len(bookIDs) == 0 {
+			items, err := s.dormant(tx, req.Years, branch.ID.String(), req.Category, 0)
+			if err != nil {
+				return err
+			}
+			for _, item := range items {
+				bookIDs = append(bookIDs, item.BookID)
+			}
+			if criteria == "" {
+				criteria = fmt.Sprintf("No circulation in %d years", req.Years)
+			}
+		}
+		
+		for _, bookID := range bookIDs {
+			var holding models.BookHolding
+			if err := tx.Where("book_id = ? AND branch_id = ? AND total_copies > 0", bookID, branch.ID).First(&holding).Error; err != nil {
+				result.Skipped = append(result.Skipped, bookID)
+				continue
+			}
+			
+			var open int64
+			tx.Model(&models.WeedingCandidate{}).
+				Where("book_id = ? AND branch_id = ? AND status = ?", holding.BookID, branch.ID, models.WeedingProposed).
+				Count(&open)
+			if open > 0 {
+				result.Skipped = append(result.Skipped, bookID)
+				continue
+			}
+			
+			candidate := models.WeedingCandidate{
+				BookID:       holding.BookID,
+				BranchID:     branch.ID,
+				Copies:       req.Copies,
+				Criteria:     criteria,
+				Status:       models.WeedingProposed,
+				ProposedByID: proposedByID,
+			}
+			if err := tx.Create(&candidate).Error; err != nil {
+				return err
+			}
+			result.Candidates = append(result.Candidates, candidate)
+		}
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *WeedingService) Withdraw(req models.WeedingDecisionRequest, decidedByID uuid.UUID) (*models.WeedingResult, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrWithdrawalReason
+	}
+	
+	return s.decide(req.CandidateIDs, func(tx *gorm.DB, candidate *models.WeedingCandidate) (bool, error) {
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, "id = ?", candidate.BookID).Error; err != nil {
+			return false, nil
+		}
+		
+		onShelf, err := s.circulation.onShelfAt(tx, book.ID, candidate.BranchID)
+		if err != nil {
+			return false, err
+		}
+		copies := int(onShelf)
+		if candidate.Copies > 0 && candidate.Copies < copies {
+			copies = candidate.Copies
+		}
+		if copies == 0 {
+			return false, nil
+		}
+		
+		for i := 0; i < copies; i++ {
+			book.LoseShelfCopy()
+		}
+		if err := tx.Save(&book).Error; err != nil {
+			return false, err
+		}
+		if err := AdjustHolding(tx, book.ID, candidate.BranchID, -copies); err != nil {
+			return false, err
+		}
+		
+		candidate.Withdrawn = copies
+		candidate.Decide(models.WeedingWithdrawn, reason, decidedByID)
+		return true, nil
+	})
+}
+
+func (s *WeedingService) Keep(req models.WeedingDecisionRequest, decidedByID uuid.UUID) (*models.WeedingResult, error) {
+	return s.decide(req.CandidateIDs, func(tx *gorm.DB, candidate *models.WeedingCandidate) (bool, error) {
+		candidate.Decide(models.WeedingKept, strings.TrimSpace(req.Reason), decidedByID)
+		return true, nil
+	})
+}
+
+func (s *WeedingService) decide(ids []string, apply func(tx *gorm.DB, candidate *models.WeedingCandidate) (bool, error)) (*models.WeedingResult, error) {
+	result := &models.WeedingResult{Candidates: []models.WeedingCandidate{}, Skipped: []string{}}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			var candidate models.WeedingCandidate
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&candidate, "id = ?", id).Error; err != nil {
+				result.Skipped = append(result.Skipped, id)
+				continue
+			}
+			if candidate.Status != models.WeedingProposed {
+				result.Skipped = append(result.Skipped, id)
+				continue
+			}
+			
+			applied, err := apply(tx, &candidate)
+			if err != nil {
+				return err
+			}
+			if !applied {
+				result.Skipped = append(result.Skipped, id)
+				continue
+			}
+			
+			if err := tx.Omit("Book").Save(&candidate).Error; err != nil {
+				return err
+			}
+			result.Copies += candidate.Withdrawn
+			result.Candidates = append(result.Candidates, candidate)
+		}
+		return nil
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *WeedingService) dormant(tx *gorm.DB, years int, branchID, category string, limit int) ([]models.DormantItem, error) {
+	cutoff := time.Now().AddDate(-years, 0, 0)
+	query := tx.Table("books").
+		Select("books.id AS book_id, books.title, books.author, books.call_number, books.category, books.publish_date, books.total_copies, COUNT(loans.id) AS loans, MAX(loans.loan_date) AS last_loan_date").
+		Joins("LEFT JOIN loans ON loans.book_id = books.id AND loans.deleted_at IS NULL").
+		Where("books.deleted_at IS NULL AND books.total_copies > 0 AND books.created_at < ?", cutoff)
+	if branchID != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM book_holdings h WHERE h.book_id = books.id AND h.branch_id = ? AND h.total_copies > 0)", branchID)
+	}
+	if category != "" {
+		query = query.Where("books.category = ?", category)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	
+	items := []models.DormantItem{}
+	err := query.Group("books.id").
+		Having("MAX(loans.loan_date) IS NULL OR MAX(loans.loan_date) < ?", cutoff).
+		Order("last_loan_date ASC NULLS FIRST").
+		Order("books." + shelfOrder).
+		Scan(&items).Error
+	return items, err
+}
+
+func turnover(loans, copies int64, years int) float64 {
+	if copies == 0 || years <= 0 {
+		return 0
+	}
+	return float64(loans) / float64(copies) / float64(years)
+}
+
+// flagLowTurnover marks the categories circulating below threshold, or below
+// half the overall turnover when no threshold is given, and returns the
+// threshold it used.
+func flagLowTurnover(categories []models.CategoryTurnover, overall, threshold float64) float64 {
+	if threshold <= 0 {
+		threshold = overall / 2
+	}
+	for i := range categories {
+		categories[i].Low = categories[i].Turnover < threshold
+	}
+	return threshold
+}
+
+// ageBandIndex returns the index in ageBands of the band an item age in years
+// falls into.
+func ageBandIndex(age int) int {
+	for i, band := range ageBands {
+		if age <= band.maxAge {
+			return i
+		}
+	}
+	return len(ageBands) - 1
+}
+
+// demandRatio fills in the hold ratios of ratio and, given a target number of
+// holds per copy, how many more copies would meet it.
+func demandRatio(ratio models.DemandRatio, holdsPerCopy float64) models.DemandRatio {
+	ratio.HoldsPerCopy = float64(ratio.Holds) / math.Max(float64(ratio.TotalCopies), 1)
+	if ratio.Holds > 0 {
+		ratio.CopiesPerHold = float64(ratio.TotalCopies) / float64(ratio.Holds)
+	}
+	if holdsPerCopy > 0 {
+		needed := int(math.Ceil(float64(ratio.Holds)/holdsPerCopy)) - ratio.TotalCopies
+		if needed > 0 {
+			ratio.CopiesNeeded = needed
+		}
+	}
+	return ratio
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestTurnover(t *testing.T) {
+	tests := []struct {
+		name   string
+		loans  int64
+		copies int64
+		years  int
+		want   float64
+	}{
+		{name: "loans per copy per year", loans: 30, copies: 5, years: 3, want: 2},
+		{name: "no loans", loans: 0, copies: 5, years: 3, want: 0},
+		{name: "no copies", loans: 4, copies: 0, years: 3, want: 0},
+		{name: "no period", loans: 4, copies: 2, years: 0, want: 0},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := turnover(tt.loans, tt.copies, tt.years); got != tt.want {
+				t.Fatalf("turnover = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagLowTurnover(t *testing.T) {
+	tests := []struct {
+		name          string
+		threshold     float64
+		wantThreshold float64
+		wantLow       []bool
+	}{
+		{name: "half the overall turnover by default", threshold: 0, wantThreshold: 1, wantLow: []bool{true, false, false}},
+		{name: "given threshold", threshold: 2.5, wantThreshold: 2.5, wantLow: []bool{true, true, false}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories := []models.CategoryTurnover{{Turnover: 0.5}, {Turnover: 1}, {Turnover: 3}}
+			if got := flagLowTurnover(categories, 2, tt.threshold); got != tt.wantThreshold {
+				t.Fatalf("threshold = %v, want %v", got, tt.wantThreshold)
+			}
+			for i, category := range categories {
+				if category.Low != tt.wantLow[i] {
+					t.Errorf("turnover %v low = %v, want %v", category.Turnover, category.Low, tt.wantLow[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAgeBandIndex(t *testing.T) {
+	tests := []struct {
+		age  int
+		want string
+	}{
+		{age: 0, want: "0-4 years"},
+		{age: 4, want: "0-4 years"},
+		{age: 5, want: "5-9 years"},
+		{age: 19, want: "10-19 years"},
+		{age: 20, want: "20-49 years"},
+		{age: 50, want: "50+ years"},
+		{age: 300, want: "50+ years"},
+	}
+	
+	for _, tt := range tests {
+		if got := ageBands[ageBandIndex(tt.age)].label; got != tt.want {
+			t.Errorf("age %d band = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestDemandRatio(t *testing.T) {
+	tests := []struct {
+		name         string
+		copies       int
+		holds        int64
+		holdsPerCopy float64
+		wantPerCopy  float64
+		wantPerHold  float64
+		wantNeeded   int
+	}{
+		{name: "over the target", copies: 2, holds: 9, holdsPerCopy: 3, wantPerCopy: 4.5, wantPerHold: 2.0 / 9, wantNeeded: 1},
+		{name: "at the target", copies: 3, holds: 9, holdsPerCopy: 3, wantPerCopy: 3, wantPerHold: 1.0 / 3},
+		{name: "no target", copies: 1, holds: 5, wantPerCopy: 5, wantPerHold: 0.2},
+		{name: "no copies left", copies: 0, holds: 4, holdsPerCopy: 2, wantPerCopy: 4, wantNeeded: 2},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ratio := demandRatio(models.DemandRatio{TotalCopies: tt.copies, Holds: tt.holds}, tt.holdsPerCopy)
+			if math.Abs(ratio.HoldsPerCopy-tt.wantPerCopy) > 1e-9 {
+				t.Errorf("holds per copy = %v, want %v", ratio.HoldsPerCopy, tt.wantPerCopy)
+			}
+			if math.Abs(ratio.CopiesPerHold-tt.wantPerHold) > 1e-9 {
+				t.Errorf("copies per hold = %v, want %v", ratio.CopiesPerHold, tt.wantPerHold)
+			}
+			if ratio.CopiesNeeded != tt.wantNeeded {
+				t.Errorf("copies needed = %d, want %d", ratio.CopiesNeeded, tt.wantNeeded)
+			}
+		})
+	}
+}
+
+func TestWeedingDormantCriteria(t *testing.T) {
+	db, cfg := testDB(t)
+	weeding := NewWeedingService(db, cfg)
+	member := createMember(t, db, "weeding", false)
+	category := "Weeding " + uuid.New().String()[:8]
+	longAgo := time.Now().AddDate(-5, 0, 0)
+	
+	add := func(title string, created time.Time, copies int, loanDates ...time.Time) models.Book {
+		t.Helper()
+		book := models.Book{Title: title, Author: "Tester", Category: category, Barcode: "W" + uuid.New().String()[:8], TotalCopies: copies, AvailableCopies: copies, Status: models.BookStatusAvailable}
+		if err := db.Create(&book).Error; err != nil {
+			t.Fatalf("create book: %v", err)
+		}
+		db.Model(&book).UpdateColumn("created_at", created)
+		if err := SyncHoldings(db, &book, uuid.Nil); err != nil {
+			t.Fatalf("holdings: %v", err)
+		}
+		for _, loanDate := range loanDates {
+			loan := models.Loan{BookID: book.ID, MemberID: member.ID, IssuedByID: member.UserID, LoanDate: loanDate, DueDate: loanDate.AddDate(0, 0, 14), Status: models.LoanStatusReturned, MaxRenewals: 2}
+			if err := db.Create(&loan).Error; err != nil {
+				t.Fatalf("create loan: %v", err)
+			}
+		}
+		return book
+	}
+	
+	never := add("Never borrowed", longAgo, 1)
+	stale := add("Borrowed long ago", longAgo, 1, longAgo.AddDate(0, 1, 0))
+	add("Borrowed recently", longAgo, 1, longAgo, time.Now().AddDate(0, -2, 0))
+	add("Newly added", time.Now().AddDate(0, -1, 0), 1)
+	add("Withdrawn", longAgo, 0)
+	
+	items, err := weeding.Dormant(3, "", category, 0)
+	if err != nil {
+		t.Fatalf("dormant: %v", err)
+	}
+	if len(items) != 2 || items[0].BookID != never.ID.String() || items[1].BookID != stale.ID.String() {
+		t.Fatalf("dormant items = %+v, want never borrowed then borrowed long ago", items)
+	}
+	if items[0].LastLoanDate != nil || items[1].Loans != 1 {
+		t.Fatalf("dormant circulation = %+v", items)
+	}
+	
+	result, err := weeding.Propose(models.WeedingProposalRequest{Years: 3, Category: category}, member.UserID)
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	if len(result.Candidates) != 2 || result.Candidates[0].Criteria != "No circulation in 3 years" {
+		t.Fatalf("candidates = %+v", result.Candidates)
+	}
+	
+	again, err := weeding.Propose(models.WeedingProposalRequest{Years: 3, Category: category}, member.UserID)
+	if err != nil {
+		t.Fatalf("propose again: %v", err)
+	}
+	if len(again.Candidates) != 0 || len(again.Skipped) != 2 {
+		t.Fatalf("second proposal created %d and skipped %d, want titles already proposed skipped", len(again.Candidates), len(again.Skipped))
+	}
+	
+	if _, err := weeding.Propose(models.WeedingProposalRequest{}, member.UserID); err != ErrWeedingCriteria {
+		t.Fatalf("proposal without criteria error = %v, want %v", err, ErrWeedingCriteria)
+	}
+}
@@ -0,0 +1,399 @@
+package sip2
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+
+	"github.com/google/uuid"
+)
+
+func (s *Server) login(sess *session, msg *Message) *Message {
+	var user models.User
+	err := s.db.Where("email = ?", msg.Get("CN")).First(&user).Error
+	
+	ok := err == nil && user.IsActive && user.IsLibrarian() && user.CheckPassword(msg.Get("CO"))
+	if ok {
+		sess.user = &user
+	}
+	
+	return NewMessage("94", bit(ok))
+}
+
+func (s *Server) scStatus(sess *session, msg *Message) *Message {
+	online := sess.user != nil
+	
+	return NewMessage("98",
+		flag(online), flag(online), flag(online), flag(online), "N", "N",
+		"030", "003", formatDate(time.Now()), "2.00").
+		Add("AO", s.config.SIP2.Institution).
+		Add("AM", s.config.SIP2.Institution).
+		Add("BX", "YYYYYYYYYYNNYNNN")
+}
+
+func (s *Server) patronStatusFlags(member *models.Member) string {
+	var overdue int64
+	s.db.Model(&models.Loan{}).
+		Where("member_id = ? AND status IN ? AND due_date < ?", member.ID,
+			[]models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue}, time.Now()).
+		Count(&overdue)
+	
+	blocked := !member.IsActive || member.IsExpired()
+	flags := []bool{
+		blocked || !member.CanBorrowMore(),
+		blocked,
+		false,
+		blocked,
+		false,
+		!member.CanBorrowMore(),
+		overdue > 0,
+		false,
+		false,
+		false,
+		member.HasOutstandingFines(),
+		false,
+		false,
+		false,
+	}
+	
+	var b strings.Builder
+	for _, f := range flags {
+		if f {
+			b.WriteString("Y")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	return b.String()
+}
+
+func (s *Server) patronStatus(msg *Message) *Message {
+	patronID := msg.Get("AA")
+	member, err := s.circulation.FindMemberByCard(patronID)
+	if err != nil {
+		return NewMessage("24", "YYYY          ", "000", formatDate(time.Now())).
+			Add("AO", msg.Get("AO")).
+			Add("AA", patronID).
+			Add("AE", "").
+			Add("BL", "N")
+	}
+	
+	reply := NewMessage("24", s.patronStatusFlags(member), "000", formatDate(time.Now())).
+		Add("AO", msg.Get("AO")).
+		Add("AA", patronID).
+		Add("AE", member.User.FullName).
+		Add("BL", "Y").
+		Add("BV", amount(member.GetOutstandingFines()))
+	
+	if password := msg.Get("AD"); password != "" {
+		reply.Add("CQ", flag(member.User.CheckPassword(password)))
+	}
+	
+	return reply
+}
+
+func (s *Server) patronInformation(msg *Message) *Message {
+	patronID := msg.Get("AA")
+	member, err := s.circulation.FindMemberByCard(patronID)
+	if err != nil {
+		return NewMessage("64", "YYYY          ", "000", formatDate(time.Now()),
+			count(0), count(0), count(0), count(0), count(0), count(0)).
+			Add("AO", msg.Get("AO")).
+			Add("AA", patronID).
+			Add("AE", "").
+			Add("BL", "N")
+	}
+	
+	var loans []models.Loan
+	s.db.Preload("Book").
+		Where("member_id = ? AND status IN ?", member.ID,
+			[]models.LoanStatus{models.LoanStatusActive, models.LoanStatusOverdue}).
+		Find(&loans)
+	
+	var holds []models.Reservation
+	s.db.Preload("Book").
+		Where("member_id = ? AND status IN ?", member.ID,
+			[]models.ReservationStatus{models.ReservationStatusPending, models.ReservationStatusInTransit, models.ReservationStatusReadyForPickup}).
+		Find(&holds)
+	
+	var overdue []models.Loan
+	for _, l := range loans {
+		if l.IsOverdue() {
+			overdue = append(overdue, l)
+		}
+	}
+	
+	available := 0
+	for _, r := range holds {
+		if r.IsReadyForPickup() {
+			available++
+		}
+	}
+	
+	reply := NewMessage("64", s.patronStatusFlags(member), "000", formatDate(time.Now()),
+		count(available), count(len(overdue)), count(len(loans)), count(0), count(0), count(len(holds)-available)).
+		Add("AO", msg.Get("AO")).
+		Add("AA", patronID).
+		Add("AE", member.User.FullName).
+		Add("BZ", count(s.config.Library.MaxBooksPerMember)).
+		Add("CB", count(member.MaxBooksAllowed)).
+		Add("BL", "Y").
+		Add("BV", amount(member.GetOutstandingFines())).
+		Add("BE", member.User.Email).
+		Add("BF", member.User.Phone)
+	
+	if password := msg.Get("AD"); password != "" {
+		reply.Add("CQ", flag(member.User.CheckPassword(password)))
+	}
+	
+	summary := msg.Fixed[21:]
+	switch {
+	case strings.HasPrefix(summary, "Y"):
+		for _, r := range holds {
+			if r.IsReadyForPickup() {
+				reply.Add("AS", r.Book.ISBN)
+			}
+		}
+	case strings.HasPrefix(summary, " Y"):
+		for _, l := range overdue {
+			reply.Add("AT", l.Book.ISBN)
+		}
+	case strings.HasPrefix(summary, "  Y"):
+		for _, l := range loans {
+			reply.Add("AU", l.Book.ISBN)
+		}
+	case strings.HasPrefix(summary, "     Y"):
+		for _, r := range holds {
+			if !r.IsReadyForPickup() {
+				reply.Add("CD", r.Book.ISBN)
+			}
+		}
+	}
+	
+	return reply
+}
+
+func circulationStatus(book *models.Book, held bool) string {
+	switch {
+	case held:
+		return "08"
+	case book.Status == models.BookStatusLost:
+		return "12"
+	case book.IsAvailable():
+		return "03"
+	case book.Status == models.BookStatusDamaged:
+		return "01"
+	default:
+		return "04"
+	}
+}
+
+func (s *Server) itemInformation(msg *Message) *Message {
+	itemID := msg.Get("AB")
+	book, err := s.circulation.FindItem(itemID)
+	if err != nil {
+		return NewMessage("18", "01", "00", "01", formatDate(time.Now())).
+			Add("AB", itemID).
+			Add("AJ", "")
+	}
+	
+	var queue int64
+	s.db.Model(&models.Reservation{}).
+		Where("book_id = ? AND status = ?", book.ID, models.ReservationStatusPending).
+		Count(&queue)
+	
+	var held int64
+	s.db.Model(&models.Reservation{}).
+		Where("book_id = ? AND status = ?", book.ID, models.ReservationStatusReadyForPickup).
+		Count(&held)
+	
+	reply := NewMessage("18", circulationStatus(book, held > 0 && book.AvailableCopies == 0), "00", "01", formatDate(time.Now())).
+		Add("CF", strconv.FormatInt(queue, 10)).
+		Add("AB", itemID).
+		Add("AJ", book.Title).
+		Add("AQ", book.Location)
+	
+	if _, loan, err := s.circulation.FindCheckinLoan(itemID, uuid.Nil); err == nil && !book.IsAvailable() {
+		reply.Add("AH", formatDate(loan.DueDate))
+	}
+	
+	return reply
+}
+
+func (s *Server) checkout(sess *session, msg *Message) *Message {
+	patronID, itemID := msg.Get("AA"), msg.Get("AB")
+	fail := func(screen string) *Message {
+		return NewMessage("12", "0", "N", "U", "N", formatDate(time.Now())).
+			Add("AO", msg.Get("AO")).
+			Add("AA", patronID).
+			Add("AB", itemID).
+			Add("AJ", "").
+			Add("AH", "").
+			Add("AF", screen)
+	}
+	
+	member, err := s.circulation.FindMemberByCard(patronID)
+	if err != nil {
+		return fail("Patron not found")
+	}
+	if password := msg.Get("AD"); password != "" && !member.User.CheckPassword(password) {
+		return fail("Invalid patron password")
+	}
+	
+	book, copyBarcode, err := s.circulation.FindCopy(itemID)
+	if err != nil {
+		return fail("Item not found")
+	}
+	
+	result, err := s.circulation.Checkout(book.ID, member.ID, sess.user.ID, uuid.Nil, copyBarcode, time.Time{}, "Self-checkout")
+	if err != nil {
+		return fail(screenMessage(err))
+	}
+	
+	return NewMessage("12", "1", "N", "U", "Y", formatDate(time.Now())).
+		Add("AO", msg.Get("AO")).
+		Add("AA", patronID).
+		Add("AB", itemID).
+		Add("AJ", book.Title).
+		Add("AH", formatDate(result.Loan.DueDate))
+}
+
+func (s *Server) checkin(msg *Message) *Message {
+	itemID := msg.Get("AB")
+	fail := func(screen string) *Message {
+		return NewMessage("10", "0", "N", "U", "N", formatDate(time.Now())).
+			Add("AO", msg.Get("AO")).
+			Add("AB", itemID).
+			Add("AQ", "").
+			Add("AF", screen)
+	}
+	
+	book, loan, err := s.circulation.FindCheckinLoan(itemID, uuid.Nil)
+	switch {
+	case book == nil:
+		return fail("Item not found")
+	case errors.Is(err, services.ErrLoanAmbiguous):
+		return fail("Please return this item at the desk")
+	case err != nil:
+		return fail("Item is not checked out")
+	}
+	
+	result, err := s.circulation.Return(loan.ID, uuid.Nil, time.Time{})
+	if err != nil {
+		return fail(screenMessage(err))
+	}
+	
+	alert := result.TrappedHold != nil
+	reply := NewMessage("10", "1", "Y", "U", flag(alert), formatDate(time.Now())).
+		Add("AO", msg.Get("AO")).
+		Add("AB", itemID).
+		Add("AQ", book.Location).
+		Add("AJ", book.Title)
+	
+	if alert {
+		reply.Add("CV", "01").
+			Add("AF", "Hold for pickup at "+result.TrappedHold.PickupLocation)
+	}
+	
+	return reply
+}
+
+func (s *Server) renew(msg *Message) *Message {
+	patronID, itemID := msg.Get("AA"), msg.Get("AB")
+	fail := func(screen string) *Message {
+		return NewMessage("30", "0", "N", "U", "N", formatDate(time.Now())).
+			Add("AO", msg.Get("AO")).
+			Add("AA", patronID).
+			Add("AB", itemID).
+			Add("AJ", "").
+			Add("AH", "").
+			Add("AF", screen)
+	}
+	
+	member, err := s.circulation.FindMemberByCard(patronID)
+	if err != nil {
+		return fail("Patron not found")
+	}
+	if password := msg.Get("AD"); password != "" && !member.User.CheckPassword(password) {
+		return fail("Invalid patron password")
+	}
+	
+	book, err := s.circulation.FindItem(itemID)
+	if err != nil {
+		return fail("Item not found")
+	}
+	
+	loan, err := s.circulation.FindOutstandingLoan(book.ID, member.ID)
+	if err != nil {
+		return fail("Item is not checked out to this patron")
+	}
+	
+	loan, err = s.circulation.Renew(loan.ID, time.Time{}, false)
+	if err != nil {
+		return fail(screenMessage(err))
+	}
+	
+	return NewMessage("30", "1", "Y", "U", "Y", formatDate(time.Now())).
+		Add("AO", msg.Get("AO")).
+		Add("AA", patronID).
+		Add("AB", itemID).
+		Add("AJ", book.Title).
+		Add("AH", formatDate(loan.DueDate))
+}
+
+func (s *Server) feePaid(msg *Message) *Message {
+	patronID := msg.Get("AA")
+	reply := func(ok bool, screen string) *Message {
+		m := NewMessage("38", flag(ok), formatDate(time.Now())).
+			Add("AO", msg.Get("AO")).
+			Add("AA", patronID)
+		if screen != "" {
+			m.Add("AF", screen)
+		}
+		return m
+	}
+	
+	paid, err := strconv.ParseFloat(msg.Get("BV"), 64)
+	if err != nil || paid <= 0 {
+		return reply(false, "Invalid fee amount")
+	}
+	
+	member, err := s.circulation.FindMemberByCard(patronID)
+	if err != nil {
+		return reply(false, "Patron not found")
+	}
+	
+	if _, err := s.circulation.RecordPayment(member.ID, paid); err != nil {
+		return reply(false, screenMessage(err))
+	}
+	
+	return reply(true, "")
+}
+
+func screenMessage(err error) string {
+	var denied *services.RenewalDeniedError
+	switch {
+	case errors.As(err, &denied):
+		return "Renewal not allowed: " + strings.ReplaceAll(string(denied.Reason), "_", " ")
+	case errors.Is(err, services.ErrBookOnHold):
+		return "Item is on hold for another patron"
+	case errors.Is(err, services.ErrBookUnavailable),
+		errors.Is(err, services.ErrCopyOnLoan):
+		return "Item is not available"
+	case errors.Is(err, services.ErrLoanLimitReached),
+		errors.Is(err, services.ErrItemTypeLimit):
+		return "Loan limit reached"
+	case errors.Is(err, services.ErrItemNotLendable):
+		return "Item cannot be borrowed"
+	case errors.Is(err, services.ErrMemberInactive):
+		return "Membership is not active"
+	case errors.Is(err, services.ErrPaymentExceedsBalance):
+		return "Payment exceeds outstanding balance"
+	default:
+		return "Please see a librarian"
+	}
+}
@@ -0,0 +1,165 @@
+package sip2
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMessageTooShort = errors.New("sip2 message too short")
+	ErrBadChecksum     = errors.New("sip2 checksum mismatch")
+)
+
+const dateLayout = "20060102    150405"
+
+var fixedLengths = map[string]int{
+	"93": 2,
+	"99": 8,
+	"23": 21,
+	"63": 31,
+	"17": 18,
+	"11": 38,
+	"09": 37,
+	"29": 38,
+	"37": 25,
+	"35": 18,
+	"97": 0,
+	"94": 1,
+	"96": 0,
+	"98": 34,
+	"24": 35,
+	"64": 59,
+	"18": 24,
+	"12": 22,
+	"10": 22,
+	"30": 22,
+	"38": 19,
+	"36": 19,
+}
+
+type Field struct {
+	ID    string
+	Value string
+}
+
+type Message struct {
+	Code     string
+	Fixed    string
+	Fields   []Field
+	Sequence string
+}
+
+func Parse(raw string) (*Message, error) {
+	raw = strings.TrimRight(raw, "\r\n")
+	if len(raw) < 2 {
+		return nil, ErrMessageTooShort
+	}
+	
+	if i := strings.LastIndex(raw, "AZ"); i >= 0 && len(raw)-i == 6 {
+		if checksum(raw[:i+2]) != strings.ToUpper(raw[i+2:]) {
+			return nil, ErrBadChecksum
+		}
+		raw = raw[:i]
+	}
+	
+	msg := &Message{Code: raw[:2]}
+	body := raw[2:]
+	
+	fixed := fixedLengths[msg.Code]
+	if len(body) < fixed {
+		return nil, ErrMessageTooShort
+	}
+	msg.Fixed = body[:fixed]
+	
+	for _, part := range strings.Split(body[fixed:], "|") {
+		if len(part) < 2 {
+			continue
+		}
+		if part[:2] == "AY" {
+			msg.Sequence = strings.TrimSuffix(part[2:], "AZ")
+			continue
+		}
+		msg.Fields = append(msg.Fields, Field{ID: part[:2], Value: part[2:]})
+	}
+	
+	return msg, nil
+}
+
+func NewMessage(code string, fixed ...string) *Message {
+	return &Message{Code: code, Fixed: strings.Join(fixed, "")}
+}
+
+func (m *Message) Get(id string) string {
+	for _, f := range m.Fields {
+		if f.ID == id {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+func (m *Message) Add(id, value string) *Message {
+	m.Fields = append(m.Fields, Field{ID: id, Value: strings.ReplaceAll(value, "|", " ")})
+	return m
+}
+
+func (m *Message) Encode() string {
+	var b strings.Builder
+	b.WriteString(m.Code)
+	b.WriteString(m.Fixed)
+	for _, f := range m.Fields {
+		b.WriteString(f.ID)
+		b.WriteString(f.Value)
+		b.WriteString("|")
+	}
+	
+	if m.Sequence != "" {
+		b.WriteString("AY")
+		b.WriteString(m.Sequence)
+		b.WriteString("AZ")
+		b.WriteString(checksum(b.String()))
+	}
+	
+	b.WriteString("\r")
+	return b.String()
+}
+
+func checksum(s string) string {
+	var sum uint16
+	for i := 0; i < len(s); i++ {
+		sum += uint16(s[i])
+	}
+	return fmt.Sprintf("%04X", -sum)
+}
+
+func formatDate(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
+func flag(b bool) string {
+	if b {
+		return "Y"
+	}
+	return "N"
+}
+
+func bit(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func count(n int) string {
+	if n > 9999 {
+		n = 9999
+	}
+	return fmt.Sprintf("%04d", n)
+}
+
+func amount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
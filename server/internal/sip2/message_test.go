@@ -0,0 +1,90 @@
+package sip2
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFixedLengths(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		code     string
+		fixed    string
+		sequence string
+		fields   map[string]string
+	}{
+		{
+			name:     "sc status",
+			raw:      "9900302.00AY1AZ",
+			code:     "99",
+			fixed:    "00302.00",
+			sequence: "1",
+		},
+		{
+			name:     "login",
+			raw:      "9300CNdesk@example.org|COsecret|CPlib|AY2AZ",
+			code:     "93",
+			fixed:    "00",
+			sequence: "2",
+			fields:   map[string]string{"CN": "desk@example.org", "CO": "secret", "CP": "lib"},
+		},
+		{
+			name:   "checkin",
+			raw:    "09N20240101    12000020240101    120000APmain|AOlib|AB0001|AC|",
+			code:   "09",
+			fixed:  "N20240101    12000020240101    120000",
+			fields: map[string]string{"AP": "main", "AO": "lib", "AB": "0001", "AC": ""},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := tt.raw
+			if tt.sequence != "" {
+				raw += checksum(raw) + "\r"
+			}
+			
+			msg, err := Parse(raw)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", raw, err)
+			}
+			if msg.Code != tt.code || msg.Fixed != tt.fixed {
+				t.Fatalf("got code %q fixed %q, want %q %q", msg.Code, msg.Fixed, tt.code, tt.fixed)
+			}
+			if msg.Sequence != tt.sequence {
+				t.Fatalf("got sequence %q, want %q", msg.Sequence, tt.sequence)
+			}
+			if len(msg.Fields) != len(tt.fields) {
+				t.Fatalf("got %d fields %v, want %d", len(msg.Fields), msg.Fields, len(tt.fields))
+			}
+			for id, value := range tt.fields {
+				if got := msg.Get(id); got != value {
+					t.Errorf("field %s = %q, want %q", id, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRejectsBadChecksum(t *testing.T) {
+	if _, err := Parse("9900302.00AY1AZ0000"); err != ErrBadChecksum {
+		t.Fatalf("got %v, want ErrBadChecksum", err)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	now := formatDate(time.Now())
+	msg := NewMessage("09", "N", now, now).
+		Add("AO", "lib").
+		Add("AB", "a|b")
+	msg.Sequence = "7"
+	
+	parsed, err := Parse(msg.Encode())
+	if err != nil {
+		t.Fatalf("Parse(Encode()): %v", err)
+	}
+	if parsed.Sequence != "7" || parsed.Get("AO") != "lib" || parsed.Get("AB") != "a b" {
+		t.Fatalf("round trip lost data: %+v", parsed)
+	}
+}
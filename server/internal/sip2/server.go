@@ -0,0 +1,171 @@
+package sip2
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+type Server struct {
+	db          *gorm.DB
+	config      *config.Config
+	circulation *services.CirculationService
+	log         *logger.Logger
+	
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+type session struct {
+	user *models.User
+	last string
+}
+
+func NewServer(db *gorm.DB, cfg *config.Config, log *logger.Logger) *Server {
+	return &Server{
+		db:          db,
+		config:      cfg,
+		circulation: services.NewCirculationService(db, cfg),
+		log:         log,
+		conns:       make(map[net.Conn]struct{}),
+	}
+}
+
+func (s *Server) ListenAndServe() error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.config.SIP2.Port))
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+	
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) Shutdown() {
+	s.mu.Lock()
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	
+	s.wg.Wait()
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+	
+	reader := bufio.NewReader(conn)
+	sess := &session{}
+	
+	for {
+		if s.config.SIP2.IdleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(s.config.SIP2.IdleTimeout))
+		}
+		
+		line, err := reader.ReadString('\r')
+		if err != nil {
+			return
+		}
+		
+		var response string
+		msg, err := Parse(line)
+		switch {
+		case err != nil:
+			s.log.Warn("Invalid SIP2 message", "remote", conn.RemoteAddr().String(), "error", err)
+			response = NewMessage("96").Encode()
+		case msg.Code == "97":
+			response = sess.last
+		default:
+			reply := s.handle(sess, msg)
+			if reply == nil {
+				s.log.Warn("Unsupported SIP2 message", "code", msg.Code)
+				reply = NewMessage("96")
+			}
+			reply.Sequence = msg.Sequence
+			response = reply.Encode()
+			sess.last = response
+		}
+		
+		if _, err := conn.Write([]byte(response)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handle(sess *session, msg *Message) *Message {
+	switch msg.Code {
+	case "93":
+		return s.login(sess, msg)
+	case "99":
+		return s.scStatus(sess, msg)
+	}
+	
+	if sess.user == nil {
+		return nil
+	}
+	
+	switch msg.Code {
+	case "23":
+		return s.patronStatus(msg)
+	case "63":
+		return s.patronInformation(msg)
+	case "17":
+		return s.itemInformation(msg)
+	case "11":
+		return s.checkout(sess, msg)
+	case "09":
+		return s.checkin(msg)
+	case "29":
+		return s.renew(msg)
+	case "37":
+		return s.feePaid(msg)
+	case "35":
+		return NewMessage("36", "Y", formatDate(time.Now())).
+			Add("AO", msg.Get("AO")).
+			Add("AA", msg.Get("AA"))
+	}
+	
+	return nil
+}
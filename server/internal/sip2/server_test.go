@@ -0,0 +1,210 @@
+package sip2
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/library-management-system/server/internal/config"
+	"github.com/library-management-system/server/internal/database"
+	"github.com/library-management-system/server/internal/models"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+	seq    int
+}
+
+func startServer(t *testing.T, db *gorm.DB, cfg *config.Config) *fakeClient {
+	t.Helper()
+	
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	
+	server := NewServer(db, cfg, logger.New("error", "text"))
+	go server.Serve(listener)
+	t.Cleanup(server.Shutdown)
+	
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	return &fakeClient{t: t, conn: conn, reader: bufio.NewReader(conn)}
+}
+
+func (c *fakeClient) sendRaw(raw string) string {
+	c.t.Helper()
+	
+	c.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := c.conn.Write([]byte(raw)); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+	reply, err := c.reader.ReadString('\r')
+	if err != nil {
+		c.t.Fatalf("read: %v", err)
+	}
+	return reply
+}
+
+func (c *fakeClient) send(body string) *Message {
+	c.t.Helper()
+	
+	c.seq = (c.seq + 1) % 10
+	raw := body + "AY" + strconv.Itoa(c.seq) + "AZ"
+	reply := c.sendRaw(raw + checksum(raw) + "\r")
+	
+	msg, err := Parse(reply)
+	if err != nil {
+		c.t.Fatalf("reply %q: %v", reply, err)
+	}
+	if msg.Sequence != strconv.Itoa(c.seq) {
+		c.t.Fatalf("reply %q has sequence %q, want %d", reply, msg.Sequence, c.seq)
+	}
+	return msg
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		SIP2: config.SIP2Config{Institution: "lib", IdleTimeout: 5 * time.Second},
+	}
+}
+
+func TestSCStatusEchoesSequence(t *testing.T) {
+	client := startServer(t, nil, testConfig())
+	
+	reply := client.send("9900302.00")
+	if reply.Code != "98" {
+		t.Fatalf("got code %q, want 98", reply.Code)
+	}
+	if !strings.HasPrefix(reply.Fixed, "NNNNNN") {
+		t.Fatalf("offline ACS reported online: %q", reply.Fixed)
+	}
+	if reply.Get("AO") != "lib" {
+		t.Fatalf("institution %q, want lib", reply.Get("AO"))
+	}
+}
+
+func TestRequestsBeforeLoginAreRefused(t *testing.T) {
+	client := startServer(t, nil, testConfig())
+	
+	now := formatDate(time.Now())
+	reply := client.send("23001" + now + "AOlib|AA1234|")
+	if reply.Code != "96" {
+		t.Fatalf("got code %q, want 96", reply.Code)
+	}
+}
+
+func TestResendAndBadChecksum(t *testing.T) {
+	client := startServer(t, nil, testConfig())
+	
+	first := client.sendRaw("9900302.00AY1AZ" + checksum("9900302.00AY1AZ") + "\r")
+	if again := client.sendRaw("97\r"); again != first {
+		t.Fatalf("resend returned %q, want %q", again, first)
+	}
+	
+	if reply := client.sendRaw("9900302.00AY2AZ0000\r"); !strings.HasPrefix(reply, "96") {
+		t.Fatalf("bad checksum answered with %q, want 96", reply)
+	}
+}
+
+func testDB(t *testing.T) (*gorm.DB, *config.Config) {
+	t.Helper()
+	
+	if os.Getenv("SIP2_TEST_DATABASE") == "" {
+		t.Skip("set SIP2_TEST_DATABASE=1 and DB_* to run against Postgres")
+	}
+	
+	cfg := config.Load()
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db, cfg
+}
+
+func TestCirculationOverSIP2(t *testing.T) {
+	db, cfg := testDB(t)
+	suffix := uuid.New().String()[:8]
+	
+	staff := models.User{Email: "kiosk-" + suffix + "@example.org", FullName: "Kiosk", Role: models.RoleLibrarian, IsActive: true}
+	staff.SetPassword("kiosk-pass")
+	patron := models.User{Email: "patron-" + suffix + "@example.org", FullName: "Patron", Role: models.RoleMember, IsActive: true}
+	patron.SetPassword("patron-pass")
+	for _, user := range []*models.User{&staff, &patron} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	
+	member := models.Member{UserID: patron.ID, MembershipID: "M" + suffix, JoinDate: time.Now(), MaxBooksAllowed: 5, IsActive: true}
+	if err := db.Create(&member).Error; err != nil {
+		t.Fatalf("create member: %v", err)
+	}
+	
+	book := models.Book{Title: "SIP2 Test", Author: "Tester", Barcode: "B" + suffix, TotalCopies: 1, AvailableCopies: 1, Status: models.BookStatusAvailable}
+	if err := db.Create(&book).Error; err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+	if _, _, err := services.EnsureBranches(db, cfg); err != nil {
+		t.Fatalf("branches: %v", err)
+	}
+	
+	client := startServer(t, db, cfg)
+	now := formatDate(time.Now())
+	
+	if reply := client.send("9300CN" + staff.Email + "|COwrong|"); reply.Fixed != "0" {
+		t.Fatalf("login with a bad password answered %q", reply.Fixed)
+	}
+	if reply := client.send("9300CN" + staff.Email + "|COkiosk-pass|"); reply.Fixed != "1" {
+		t.Fatalf("login answered %q, want 1", reply.Fixed)
+	}
+	
+	status := client.send("23001" + now + "AOlib|AA" + member.MembershipID + "|ADpatron-pass|")
+	if status.Get("BL") != "Y" || status.Get("CQ") != "Y" {
+		t.Fatalf("patron status %+v", status)
+	}
+	
+	wrongPassword := client.send("11YN" + now + now + "AOlib|AA" + member.MembershipID + "|AB" + book.Barcode + "|ADwrong|")
+	if wrongPassword.Code != "12" || wrongPassword.Fixed[0] != '0' || wrongPassword.Get("AF") == "" {
+		t.Fatalf("checkout with a wrong patron password was not refused: %+v", wrongPassword)
+	}
+	
+	checkout := client.send("11YN" + now + now + "AOlib|AA" + member.MembershipID + "|AB" + book.Barcode + "|ADpatron-pass|")
+	if checkout.Code != "12" || checkout.Fixed[0] != '1' {
+		t.Fatalf("checkout failed: %+v", checkout)
+	}
+	
+	again := client.send("11YN" + now + now + "AOlib|AA" + member.MembershipID + "|AB" + book.Barcode + "|")
+	if again.Fixed[0] != '0' || again.Get("AF") == "" {
+		t.Fatalf("second checkout of the only copy was not refused: %+v", again)
+	}
+	
+	checkin := client.send("09N" + now + now + "APmain|AOlib|AB" + book.Barcode + "|")
+	if checkin.Code != "10" || checkin.Fixed[0] != '1' {
+		t.Fatalf("checkin failed: %+v", checkin)
+	}
+	
+	var loan models.Loan
+	if err := db.Where("book_id = ? AND member_id = ?", book.ID, member.ID).First(&loan).Error; err != nil {
+		t.Fatalf("loan: %v", err)
+	}
+	if loan.Status != models.LoanStatusReturned {
+		t.Fatalf("loan status %q after checkin, want returned", loan.Status)
+	}
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	root := filepath.Join(t.TempDir(), "blobs")
+	store := NewLocalStore(root)
+	
+	if err := store.Put(ctx, "covers/1/abc/medium.jpg", []byte("cover"), "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	
+	body, info, err := store.Get(ctx, "covers/1/abc/medium.jpg")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "cover" {
+		t.Errorf("content = %q, want %q", data, "cover")
+	}
+	if info.ContentType != "image/jpeg" || info.Size != 5 || info.ETag == "" {
+		t.Errorf("info = %+v", info)
+	}
+	
+	if err := store.Delete(ctx, "covers/1/abc/medium.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(ctx, "covers/1/abc/medium.jpg"); err != nil {
+		t.Errorf("second Delete: %v", err)
+	}
+	if _, _, err := store.Get(ctx, "covers/1/abc/medium.jpg"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Get after Delete error = %v, want ErrBlobNotFound", err)
+	}
+}
+
+func TestLocalStorePath(t *testing.T) {
+	store := NewLocalStore("/srv/blobs")
+	
+	tests := []struct {
+		key  string
+		want string
+		err  error
+	}{
+		{key: "digital/1/abc.pdf", want: "/srv/blobs/digital/1/abc.pdf"},
+		{key: "/digital/1/abc.pdf", want: "/srv/blobs/digital/1/abc.pdf"},
+		{key: "digital//1/./abc.pdf", want: "/srv/blobs/digital/1/abc.pdf"},
+		{key: "digital/1/a..b.pdf", want: "/srv/blobs/digital/1/a..b.pdf"},
+		{key: "../escape.txt", err: ErrBlobNotFound},
+		{key: "digital/../../escape.txt", err: ErrBlobNotFound},
+		{key: "digital/1/..", err: ErrBlobNotFound},
+		{key: `digital\..\..\escape.txt`, err: ErrBlobNotFound},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := store.path(tt.key)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if got != filepath.FromSlash(tt.want) {
+				t.Errorf("path = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalStoreTraversal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := NewLocalStore(filepath.Join(dir, "blobs"))
+	
+	if err := store.Put(ctx, "../../escape.txt", []byte("x"), "text/plain"); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("Put error = %v, want ErrBlobNotFound", err)
+	}
+	if _, _, err := store.Get(ctx, "../blobs/../escape.txt"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Get error = %v, want ErrBlobNotFound", err)
+	}
+	if err := store.Delete(ctx, "../escape.txt"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Delete error = %v, want ErrBlobNotFound", err)
+	}
+	
+	for _, path := range []string{filepath.Join(dir, "escape.txt"), filepath.Join(filepath.Dir(dir), "escape.txt")} {
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("%s was written outside the root", path)
+		}
+	}
+}
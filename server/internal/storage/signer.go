@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrLinkInvalid = errors.New("download link signature is invalid")
+	ErrLinkExpired = errors.New("download link has expired")
+)
+
+type URLSigner struct {
+	secret []byte
+	now    func() time.Time
+}
+
+func NewURLSigner(secret string) *URLSigner {
+	return &URLSigner{secret: []byte(secret), now: time.Now}
+}
+
+func (s *URLSigner) Sign(subject string, expires time.Time) string {
+	return hex.EncodeToString(hmacSHA256(s.secret, subject+"\n"+strconv.FormatInt(expires.Unix(), 10)))
+}
+
+func (s *URLSigner) Verify(subject string, expires int64, signature string) error {
+	given, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrLinkInvalid
+	}
+	
+	expected := hmacSHA256(s.secret, subject+"\n"+strconv.FormatInt(expires, 10))
+	if !hmac.Equal(given, expected) {
+		return ErrLinkInvalid
+	}
+	if s.now().Unix() > expires {
+		return ErrLinkExpired
+	}
+	return nil
+}
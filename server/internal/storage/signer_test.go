@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestURLSigner(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	signer := NewURLSigner("secret")
+	signer.now = func() time.Time { return now }
+	
+	expires := now.Add(time.Hour)
+	signature := signer.Sign("digital/1", expires)
+	
+	tests := []struct {
+		name      string
+		subject   string
+		expires   int64
+		signature string
+		at        time.Time
+		err       error
+	}{
+		{name: "valid", subject: "digital/1", expires: expires.Unix(), signature: signature, at: now},
+		{name: "other subject", subject: "digital/2", expires: expires.Unix(), signature: signature, at: now, err: ErrLinkInvalid},
+		{name: "extended expiry", subject: "digital/1", expires: expires.Add(time.Hour).Unix(), signature: signature, at: now, err: ErrLinkInvalid},
+		{name: "not hex", subject: "digital/1", expires: expires.Unix(), signature: "zz", at: now, err: ErrLinkInvalid},
+		{name: "tampered", subject: "digital/1", expires: expires.Unix(), signature: strings.Repeat("0", len(signature)), at: now, err: ErrLinkInvalid},
+		{name: "expired", subject: "digital/1", expires: expires.Unix(), signature: signature, at: expires.Add(time.Second), err: ErrLinkExpired},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := tt.at
+			signer.now = func() time.Time { return at }
+			if err := signer.Verify(tt.subject, tt.expires, tt.signature); !errors.Is(err, tt.err) {
+				t.Errorf("Verify error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -14,12 +14,16 @@ import (
 	"github.com/library-management-system/server/internal/database"
 	"github.com/library-management-system/server/internal/middleware"
 	"github.com/library-management-system/server/internal/routes"
+	"github.com/library-management-system/server/internal/scheduler"
+	"github.com/library-management-system/server/internal/services"
+	"github.com/library-management-system/server/internal/sip2"
 	"github.com/library-management-system/server/pkg/logger"
 	"github.com/library-management-system/server/pkg/redis"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -41,6 +45,79 @@ func main() {
 		appLogger.Fatal("Failed to run database migrations", "error", err)
 	}
 
+	// Backfills of existing rows run once per database and are recorded in
+	// data_migrations.
+	if _, err := database.RunDataMigration(db, "normalize_isbns", func(tx *gorm.DB) error {
+		isbnReport, err := services.NormalizeISBNs(tx, true)
+		if err != nil {
+			return err
+		}
+		if len(isbnReport.Invalid) > 0 || len(isbnReport.Conflicts) > 0 {
+			appLogger.Warn("Some ISBNs need review, see get_isbn_report",
+				"normalized", isbnReport.Normalized,
+				"invalid", len(isbnReport.Invalid),
+				"conflicts", len(isbnReport.Conflicts))
+		}
+		return nil
+	}); err != nil {
+		appLogger.Warn("Failed to normalize ISBNs", "error", err)
+	}
+
+	if _, err := database.RunDataMigration(db, "link_authorities", func(tx *gorm.DB) error {
+		authorityReport, err := services.NewAuthorityService(tx, nil).Migrate(true)
+		if err != nil {
+			return err
+		}
+		if authorityReport.BooksLinked > 0 {
+			appLogger.Info("Linked books to authority records, see get_duplicate_suggestions",
+				"books", authorityReport.BooksLinked,
+				"variants", len(authorityReport.Variants))
+		}
+		return nil
+	}); err != nil {
+		appLogger.Warn("Failed to link authors, publishers and subjects", "error", err)
+	}
+
+	if _, err := database.RunDataMigration(db, "default_branch", func(tx *gorm.DB) error {
+		defaultBranch, holdings, err := services.EnsureBranches(tx, cfg)
+		if err != nil {
+			return err
+		}
+		if holdings > 0 {
+			appLogger.Info("Assigned existing copies to the default branch",
+				"branch", defaultBranch.Code,
+				"books", holdings)
+		}
+		return nil
+	}); err != nil {
+		appLogger.Warn("Failed to set up the default branch", "error", err)
+	}
+
+	if _, err := database.RunDataMigration(db, "call_number_sort_keys", func(tx *gorm.DB) error {
+		sorted, err := services.EnsureCallNumbers(tx, cfg)
+		if err != nil {
+			return err
+		}
+		if sorted > 0 {
+			appLogger.Info("Built shelf order keys for existing call numbers", "books", sorted)
+		}
+		return nil
+	}); err != nil {
+		appLogger.Warn("Failed to build call number sort keys", "error", err)
+	}
+
+	if seeded, err := services.EnsureItemTypeRules(db, cfg); err != nil {
+		appLogger.Warn("Failed to set up item type loan rules", "error", err)
+	} else if seeded > 0 {
+		appLogger.Info("Created default loan rules for item types", "types", seeded)
+	}
+
+	if interrupted, err := services.NewImportService(db, nil, cfg).FailInterrupted(); err != nil {
+		appLogger.Warn("Failed to close interrupted import jobs", "error", err)
+	} else if interrupted > 0 {
+		appLogger.Warn("Marked import jobs interrupted by a restart as failed", "jobs", interrupted)
+	}
+
 	redisClient, err := redis.Connect(cfg.Redis)
 	if err != nil {
 		appLogger.Warn("Failed to connect to Redis, caching disabled", "error", err)
@@ -80,6 +157,23 @@ func main() {
 	api := router.Group(cfg.APIPrefix)
 	routes.Setup(api, db, redisClient, cfg, appLogger)
 
+	var jobs *scheduler.Scheduler
+	if cfg.Scheduler.Enabled {
+		jobs = scheduler.New(db, cfg, appLogger)
+		jobs.Start()
+	}
+
+	var sipServer *sip2.Server
+	if cfg.SIP2.Enabled {
+		sipServer = sip2.NewServer(db, cfg, appLogger)
+		go func() {
+			appLogger.Info("SIP2 server started", "port", cfg.SIP2.Port)
+			if err := sipServer.ListenAndServe(); err != nil {
+				appLogger.Error("SIP2 server stopped", "error", err)
+			}
+		}()
+	}
+
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Port),
 		Handler:      router,
@@ -108,6 +202,14 @@ func main() {
 		appLogger.Error("Server forced to shutdown", "error", err)
 	}
 
+	if jobs != nil {
+		jobs.Stop()
+	}
+
+	if sipServer != nil {
+		sipServer.Shutdown()
+	}
+
 	if redisClient != nil {
 		if err := redisClient.Close(); err != nil {
 			appLogger.Error("Failed to close Redis connection", "error", err)
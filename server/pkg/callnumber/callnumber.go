@@ -0,0 +1,321 @@
+package callnumber
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+type Scheme string
+
+const (
+	Dewey Scheme = "dewey"
+	LC    Scheme = "lc"
+	Local Scheme = "local"
+)
+
+var (
+	ErrEmpty         = errors.New("call number is empty")
+	ErrUnknownScheme = errors.New("call number scheme must be dewey, lc or local")
+	ErrInvalidDewey  = errors.New("dewey call number must start with a three digit class such as 823.912")
+	ErrInvalidLC     = errors.New("lc call number must start with one to three class letters followed by a number such as QA76.73")
+	ErrUnrecognized  = errors.New("call number is not a recognised dewey or lc call number")
+)
+
+type CallNumber struct {
+	Scheme  Scheme
+	Display string
+	SortKey string
+}
+
+func (s Scheme) IsValid() bool {
+	return s == Dewey || s == LC || s == Local
+}
+
+func Parse(value string, scheme Scheme) (CallNumber, error) {
+	tokens := tokenize(value)
+	if len(tokens) == 0 {
+		return CallNumber{}, ErrEmpty
+	}
+	
+	switch scheme {
+	case Dewey:
+		return parseDewey(tokens)
+	case LC:
+		return parseLC(tokens)
+	case Local:
+		return parseLocal(tokens), nil
+	default:
+		return CallNumber{}, ErrUnknownScheme
+	}
+}
+
+func Detect(value string, preferred Scheme) (CallNumber, error) {
+	if preferred != "" && preferred != Dewey && preferred != LC {
+		return Parse(value, preferred)
+	}
+	
+	order := []Scheme{Dewey, LC}
+	if preferred == LC {
+		order = []Scheme{LC, Dewey}
+	}
+	
+	for _, scheme := range order {
+		parsed, err := Parse(value, scheme)
+		if err == nil {
+			return parsed, nil
+		}
+		if errors.Is(err, ErrEmpty) {
+			return CallNumber{}, err
+		}
+	}
+	return CallNumber{}, ErrUnrecognized
+}
+
+func SortKey(value string, scheme Scheme) string {
+	parsed, err := Parse(value, scheme)
+	if err != nil {
+		return parseLocal(tokenize(value)).SortKey
+	}
+	return parsed.SortKey
+}
+
+func InRange(key, start, end string) bool {
+	if start != "" && key < start {
+		return false
+	}
+	if end != "" && key > end && !strings.HasPrefix(key, end) {
+		return false
+	}
+	return true
+}
+
+func tokenize(value string) []string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.NewReplacer("/", "", "'", "").Replace(value)
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+}
+
+func parseDewey(tokens []string) (CallNumber, error) {
+	var prefix []string
+	for len(tokens) > 0 && isLetters(tokens[0]) {
+		prefix = append(prefix, tokens[0])
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 {
+		return CallNumber{}, ErrInvalidDewey
+	}
+	
+	class, rest := splitClass(tokens[0])
+	whole, decimal, _ := strings.Cut(class, ".")
+	if len(whole) != 3 || !isDigits(whole) || !isDigits(decimal) || (strings.Contains(class, ".") && decimal == "") {
+		return CallNumber{}, ErrInvalidDewey
+	}
+	
+	display := append([]string{}, prefix...)
+	classDisplay := whole
+	classKey := whole
+	if decimal != "" {
+		classDisplay += "." + decimal
+		classKey += "." + decimal
+	}
+	display = append(display, classDisplay)
+	key := append(append([]string{}, prefix...), classKey)
+	
+	tail := tokens[1:]
+	if rest != "" {
+		tail = append([]string{rest}, tail...)
+	}
+	
+	cutterDone := false
+	for _, token := range tail {
+		if !cutterDone && isCutter(strings.TrimPrefix(token, ".")) {
+			token = strings.TrimPrefix(token, ".")
+			display = append(display, token)
+			key = append(key, token)
+			continue
+		}
+		cutterDone = true
+		display = append(display, token)
+		key = append(key, padNumbers(token))
+	}
+	
+	return CallNumber{
+		Scheme:  Dewey,
+		Display: strings.Join(display, " "),
+		SortKey: strings.Join(key, " "),
+	}, nil
+}
+
+func parseLC(tokens []string) (CallNumber, error) {
+	joined := strings.Join(tokens, " ")
+	
+	i := 0
+	for i < len(joined) && i < 4 && isLetter(joined[i]) {
+		i++
+	}
+	if i == 0 || i > 3 {
+		return CallNumber{}, ErrInvalidLC
+	}
+	letters := joined[:i]
+	rest := strings.TrimLeft(joined[i:], " ")
+	
+	j := 0
+	for j < len(rest) && (isDigit(rest[j]) || (rest[j] == '.' && j+1 < len(rest) && isDigit(rest[j+1]))) {
+		j++
+	}
+	if j == 0 {
+		return CallNumber{}, ErrInvalidLC
+	}
+	number := rest[:j]
+	whole, decimal, _ := strings.Cut(number, ".")
+	if len(whole) > 4 {
+		return CallNumber{}, ErrInvalidLC
+	}
+	
+	classDisplay := letters + number
+	key := []string{letters + " " + strings.Repeat("0", 4-len(whole)) + whole}
+	if decimal != "" {
+		key[0] += "." + decimal
+	}
+	display := []string{classDisplay}
+	
+	remaining := strings.TrimSpace(rest[j:])
+	cutters, tail := splitCutters(remaining)
+	for n, cutter := range cutters {
+		if n == 0 {
+			display = append(display, "."+cutter)
+		} else {
+			display[len(display)-1] += " " + cutter
+		}
+		key = append(key, cutter)
+	}
+	
+	for _, token := range tail {
+		display = append(display, token)
+		key = append(key, padNumbers(token))
+	}
+	
+	return CallNumber{
+		Scheme:  LC,
+		Display: strings.Join(display, " "),
+		SortKey: strings.Join(key, " "),
+	}, nil
+}
+
+func parseLocal(tokens []string) CallNumber {
+	key := make([]string, len(tokens))
+	for i, token := range tokens {
+		key[i] = padNumbers(token)
+	}
+	return CallNumber{
+		Scheme:  Local,
+		Display: strings.Join(tokens, " "),
+		SortKey: strings.Join(key, " "),
+	}
+}
+
+func splitClass(token string) (string, string) {
+	for i := 0; i < len(token); i++ {
+		if !isDigit(token[i]) && token[i] != '.' {
+			return token[:i], token[i:]
+		}
+		if token[i] == '.' && (i+1 == len(token) || !isDigit(token[i+1])) {
+			return token[:i], token[i:]
+		}
+	}
+	return token, ""
+}
+
+func splitCutters(value string) ([]string, []string) {
+	var cutters []string
+	var tail []string
+	
+	for _, token := range strings.Fields(value) {
+		if len(tail) > 0 {
+			tail = append(tail, token)
+			continue
+		}
+		
+		parts := strings.Split(strings.TrimPrefix(token, "."), ".")
+		if allCutters(parts) {
+			cutters = append(cutters, parts...)
+			continue
+		}
+		tail = append(tail, token)
+	}
+	return cutters, tail
+}
+
+func allCutters(parts []string) bool {
+	if len(parts) == 0 {
+		return false
+	}
+	for _, part := range parts {
+		if !isCutter(part) {
+			return false
+		}
+	}
+	return true
+}
+
+func isCutter(token string) bool {
+	if len(token) < 2 || !isLetter(token[0]) || !isDigit(token[1]) {
+		return false
+	}
+	for i := 1; i < len(token); i++ {
+		if !isDigit(token[i]) && !isLetter(token[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func padNumbers(token string) string {
+	var b strings.Builder
+	for i := 0; i < len(token); {
+		if !isDigit(token[i]) {
+			b.WriteByte(token[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(token) && isDigit(token[j]) {
+			j++
+		}
+		if j-i < 6 {
+			b.WriteString(strings.Repeat("0", 6-(j-i)))
+		}
+		b.WriteString(token[i:j])
+		i = j
+	}
+	return b.String()
+}
+
+func isLetters(token string) bool {
+	for i := 0; i < len(token); i++ {
+		if !isLetter(token[i]) {
+			return false
+		}
+	}
+	return token != ""
+}
+
+func isDigits(token string) bool {
+	for i := 0; i < len(token); i++ {
+		if !isDigit(token[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isLetter(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
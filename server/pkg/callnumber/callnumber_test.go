@@ -0,0 +1,150 @@
+package callnumber
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestShelfOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme Scheme
+		order  []string
+	}{
+		{
+			name:   "dewey decimals and cutters",
+			scheme: Dewey,
+			order: []string{
+				"005.133 K26",
+				"823.91 T649",
+				"823.912 T649h 1999",
+				"823.912 T65",
+				"823.92 A12",
+				"900 B1",
+			},
+		},
+		{
+			name:   "dewey years and volumes",
+			scheme: Dewey,
+			order: []string{
+				"823.912 T649 1999",
+				"823.912 T649 2008",
+				"823.912 T649 v.2",
+				"823.912 T649 v.10",
+			},
+		},
+		{
+			name:   "lc class letters and numbers",
+			scheme: LC,
+			order: []string{
+				"P35 .A1",
+				"PR6039.O32 H6 1999",
+				"QA9 .B4",
+				"QA76.73.J38 S65 2008",
+				"QA76.9.D3 C67",
+				"QA760 .A1",
+				"QB1 .A1",
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var previous CallNumber
+			for i, value := range tt.order {
+				parsed, err := Parse(value, tt.scheme)
+				if err != nil {
+					t.Fatalf("Parse(%q) error: %v", value, err)
+				}
+				if i > 0 && parsed.SortKey <= previous.SortKey {
+					t.Errorf("%q (%q) should shelve after %q (%q)", value, parsed.SortKey, previous.Display, previous.SortKey)
+				}
+				previous = parsed
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		value  string
+		scheme Scheme
+		err    error
+	}{
+		{value: "  ", scheme: Dewey, err: ErrEmpty},
+		{value: "82.3 T649", scheme: Dewey, err: ErrInvalidDewey},
+		{value: "QA76.73", scheme: Dewey, err: ErrInvalidDewey},
+		{value: "823.912", scheme: LC, err: ErrInvalidLC},
+		{value: "QABC76", scheme: LC, err: ErrInvalidLC},
+		{value: "823.912", scheme: "udc", err: ErrUnknownScheme},
+	}
+	
+	for _, tt := range tests {
+		if _, err := Parse(tt.value, tt.scheme); !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q, %s) error = %v, want %v", tt.value, tt.scheme, err, tt.err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		value     string
+		preferred Scheme
+		scheme    Scheme
+		err       error
+	}{
+		{value: "823.912 T649h", preferred: "", scheme: Dewey},
+		{value: "823.912 T649h", preferred: LC, scheme: Dewey},
+		{value: "QA76.73 .J38", preferred: Dewey, scheme: LC},
+		{value: "FIC SMITH", preferred: Local, scheme: Local},
+		{value: "FIC SMITH", preferred: Dewey, err: ErrUnrecognized},
+	}
+	
+	for _, tt := range tests {
+		parsed, err := Detect(tt.value, tt.preferred)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Detect(%q, %q) error = %v, want %v", tt.value, tt.preferred, err, tt.err)
+			continue
+		}
+		if err == nil && parsed.Scheme != tt.scheme {
+			t.Errorf("Detect(%q, %q) scheme = %s, want %s", tt.value, tt.preferred, parsed.Scheme, tt.scheme)
+		}
+	}
+}
+
+func TestInRange(t *testing.T) {
+	key := func(value string, scheme Scheme) string {
+		parsed, err := Parse(value, scheme)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", value, err)
+		}
+		return parsed.SortKey
+	}
+	
+	start, end := key("800", Dewey), key("823.912", Dewey)
+	tests := []struct {
+		name       string
+		value      string
+		scheme     Scheme
+		start, end string
+		want       bool
+	}{
+		{name: "inside", value: "810.5 A1", scheme: Dewey, start: start, end: end, want: true},
+		{name: "at the start", value: "800", scheme: Dewey, start: start, end: end, want: true},
+		{name: "under the end class", value: "823.912 T649h 1999", scheme: Dewey, start: start, end: end, want: true},
+		{name: "before the start", value: "795.4 B2", scheme: Dewey, start: start, end: end, want: false},
+		{name: "after the end", value: "823.92 A1", scheme: Dewey, start: start, end: end, want: false},
+		{name: "open start", value: "001 A1", scheme: Dewey, end: end, want: true},
+		{name: "open end", value: "999 Z9", scheme: Dewey, start: start, want: true},
+		{name: "lc inside", value: "QA76.73.J38 S65", scheme: LC, start: key("QA1", LC), end: key("QA99", LC), want: true},
+		{name: "lc after", value: "QA760 .A1", scheme: LC, start: key("QA1", LC), end: key("QA99", LC), want: false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InRange(key(tt.value, tt.scheme), tt.start, tt.end); got != tt.want {
+				t.Errorf("InRange = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
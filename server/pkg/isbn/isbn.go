@@ -0,0 +1,120 @@
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidLength   = errors.New("isbn must have 10 or 13 digits")
+	ErrInvalidChecksum = errors.New("isbn checksum is invalid")
+	ErrInvalidPrefix   = errors.New("isbn-13 must start with 978 or 979")
+)
+
+func Clean(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.TrimPrefix(value, "ISBN-13")
+	value = strings.TrimPrefix(value, "ISBN-10")
+	value = strings.TrimPrefix(value, "ISBN")
+	value = strings.TrimLeft(value, ": ")
+	
+	var b strings.Builder
+	for _, r := range value {
+		if (r >= '0' && r <= '9') || r == 'X' {
+			b.WriteRune(r)
+		} else if r != '-' && r != ' ' {
+			return value
+		}
+	}
+	return b.String()
+}
+
+func Normalize(value string) (string, error) {
+	cleaned := Clean(value)
+	
+	switch len(cleaned) {
+	case 10:
+		if !valid10(cleaned) {
+			return "", ErrInvalidChecksum
+		}
+		return convert10(cleaned), nil
+	case 13:
+		if !strings.HasPrefix(cleaned, "978") && !strings.HasPrefix(cleaned, "979") {
+			return "", ErrInvalidPrefix
+		}
+		if !valid13(cleaned) {
+			return "", ErrInvalidChecksum
+		}
+		return cleaned, nil
+	default:
+		return "", ErrInvalidLength
+	}
+}
+
+func Valid(value string) bool {
+	_, err := Normalize(value)
+	return err == nil
+}
+
+func To10(isbn13 string) string {
+	if len(isbn13) != 13 || !strings.HasPrefix(isbn13, "978") {
+		return ""
+	}
+	
+	body := isbn13[3:12]
+	sum := 0
+	for i, r := range body {
+		sum += (10 - i) * int(r-'0')
+	}
+	
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return body + "X"
+	}
+	return body + string(rune('0'+check))
+}
+
+func valid10(value string) bool {
+	sum := 0
+	for i, r := range value {
+		var digit int
+		switch {
+		case r >= '0' && r <= '9':
+			digit = int(r - '0')
+		case r == 'X' && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+		sum += (10 - i) * digit
+	}
+	return sum%11 == 0
+}
+
+func valid13(value string) bool {
+	sum := 0
+	for i, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(r-'0')
+	}
+	return sum%10 == 0
+}
+
+func convert10(isbn10 string) string {
+	body := "978" + isbn10[:9]
+	sum := 0
+	for i, r := range body {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(r-'0')
+	}
+	return body + string(rune('0'+(10-sum%10)%10))
+}
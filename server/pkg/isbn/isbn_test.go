@@ -0,0 +1,85 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+		err   error
+	}{
+		{name: "isbn-13", value: "9780306406157", want: "9780306406157"},
+		{name: "isbn-13 with hyphens", value: "978-0-306-40615-7", want: "9780306406157"},
+		{name: "isbn-13 with prefix and spaces", value: " ISBN-13: 978 0 306 40615 7 ", want: "9780306406157"},
+		{name: "979 prefix", value: "979-10-343-0310-6", want: "9791034303106"},
+		{name: "isbn-10", value: "0306406152", want: "9780306406157"},
+		{name: "isbn-10 with hyphens", value: "0-306-40615-2", want: "9780306406157"},
+		{name: "isbn-10 with lowercase prefix", value: "isbn 0 306 40615 2", want: "9780306406157"},
+		{name: "isbn-10 with X check digit", value: "080442957X", want: "9780804429573"},
+		{name: "isbn-10 with lowercase x", value: "0-8044-2957-x", want: "9780804429573"},
+		{name: "isbn-10 bad check digit", value: "0306406153", err: ErrInvalidChecksum},
+		{name: "isbn-10 X before the end", value: "X306406152", err: ErrInvalidChecksum},
+		{name: "isbn-13 bad check digit", value: "9780306406158", err: ErrInvalidChecksum},
+		{name: "isbn-13 X check digit", value: "978030640615X", err: ErrInvalidChecksum},
+		{name: "isbn-13 wrong prefix", value: "9770306406155", err: ErrInvalidPrefix},
+		{name: "too short", value: "030640615", err: ErrInvalidLength},
+		{name: "between lengths", value: "978030640615", err: ErrInvalidLength},
+		{name: "too long", value: "97803064061570", err: ErrInvalidLength},
+		{name: "empty", value: "", err: ErrInvalidLength},
+		{name: "letters", value: "0306406152a", err: ErrInvalidLength},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Normalize(%q) error = %v, want %v", tt.value, err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+			if Valid(tt.value) != (tt.err == nil) {
+				t.Errorf("Valid(%q) = %v", tt.value, !(tt.err == nil))
+			}
+		})
+	}
+}
+
+func TestTo10(t *testing.T) {
+	tests := []struct {
+		isbn13 string
+		want   string
+	}{
+		{isbn13: "9780306406157", want: "0306406152"},
+		{isbn13: "9780804429573", want: "080442957X"},
+		{isbn13: "9791034303106", want: ""},
+		{isbn13: "978030640615", want: ""},
+		{isbn13: "", want: ""},
+	}
+	
+	for _, tt := range tests {
+		if got := To10(tt.isbn13); got != tt.want {
+			t.Errorf("To10(%q) = %q, want %q", tt.isbn13, got, tt.want)
+		}
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := map[string]string{
+		"978-0-306-40615-7":   "9780306406157",
+		"ISBN: 0 306 40615 2": "0306406152",
+		"ISBN-10 080442957x":  "080442957X",
+		"  9780306406157  ":   "9780306406157",
+		"0306406152 (pbk)":    "0306406152 (PBK)",
+		"":                    "",
+	}
+	for value, want := range tests {
+		if got := Clean(value); got != want {
+			t.Errorf("Clean(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
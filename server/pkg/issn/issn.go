@@ -0,0 +1,60 @@
+package issn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidLength   = errors.New("issn must have 8 characters")
+	ErrInvalidChecksum = errors.New("issn checksum is invalid")
+)
+
+func Clean(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.TrimPrefix(value, "ISSN")
+	value = strings.TrimLeft(value, ": ")
+	
+	var b strings.Builder
+	for _, r := range value {
+		if (r >= '0' && r <= '9') || r == 'X' {
+			b.WriteRune(r)
+		} else if r != '-' && r != ' ' {
+			return value
+		}
+	}
+	return b.String()
+}
+
+func Normalize(value string) (string, error) {
+	cleaned := Clean(value)
+	if len(cleaned) != 8 {
+		return "", ErrInvalidLength
+	}
+	if !valid(cleaned) {
+		return "", ErrInvalidChecksum
+	}
+	return cleaned[:4] + "-" + cleaned[4:], nil
+}
+
+func Valid(value string) bool {
+	_, err := Normalize(value)
+	return err == nil
+}
+
+func valid(value string) bool {
+	sum := 0
+	for i, r := range value {
+		var digit int
+		switch {
+		case r >= '0' && r <= '9':
+			digit = int(r - '0')
+		case r == 'X' && i == 7:
+			digit = 10
+		default:
+			return false
+		}
+		sum += (8 - i) * digit
+	}
+	return sum%11 == 0
+}
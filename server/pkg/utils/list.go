@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidSortField = errors.New("invalid sort field")
+	ErrInvalidSortOrder = errors.New("invalid sort order")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+)
+
+type ListParams struct {
+	Page      int
+	Limit     int
+	Offset    int
+	SortBy    string
+	SortOrder string
+	column    string
+	cursor    *listCursor
+}
+
+type listCursor struct {
+	Sort  string      `json:"s"`
+	Order string      `json:"o"`
+	Value interface{} `json:"v"`
+	ID    string      `json:"id"`
+}
+
+func GetListParams(c *gin.Context, sortColumns map[string]string, defaultSort, defaultOrder string, defaultLimit, maxLimit int) (ListParams, error) {
+	page, limit, offset := GetPaginationParams(c, defaultLimit, maxLimit)
+	params := ListParams{
+		Page:      page,
+		Limit:     limit,
+		Offset:    offset,
+		SortBy:    c.DefaultQuery("sort_by", defaultSort),
+		SortOrder: strings.ToLower(c.DefaultQuery("sort_order", defaultOrder)),
+	}
+	
+	column, ok := sortColumns[params.SortBy]
+	if !ok {
+		return params, ErrInvalidSortField
+	}
+	params.column = column
+	
+	if params.SortOrder != "asc" && params.SortOrder != "desc" {
+		return params, ErrInvalidSortOrder
+	}
+	
+	if token := c.Query("cursor"); token != "" {
+		cursor, err := decodeCursor(token)
+		if err != nil || cursor.Sort != params.SortBy || cursor.Order != params.SortOrder {
+			return params, ErrInvalidCursor
+		}
+		params.cursor = cursor
+		params.Page = 0
+		params.Offset = 0
+	}
+	
+	return params, nil
+}
+
+func (p ListParams) HasCursor() bool {
+	return p.cursor != nil
+}
+
+func (p ListParams) Apply(query *gorm.DB, idColumn string) *gorm.DB {
+	direction, comparison := "ASC", ">"
+	if p.SortOrder == "desc" {
+		direction, comparison = "DESC", "<"
+	}
+	
+	if p.cursor != nil {
+		query = query.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", p.column, idColumn, comparison), p.cursor.Value, p.cursor.ID)
+	}
+	
+	return query.Order(fmt.Sprintf("%s %s, %s %s", p.column, direction, idColumn, direction)).
+		Limit(p.Limit + 1).
+		Offset(p.Offset)
+}
+
+func (p ListParams) Pagination(total int64, nextCursor string) Pagination {
+	pagination := CalculatePagination(p.Page, p.Limit, total)
+	pagination.SortBy = p.SortBy
+	pagination.SortOrder = p.SortOrder
+	pagination.NextCursor = nextCursor
+	pagination.HasMore = pagination.HasMore || nextCursor != ""
+	return pagination
+}
+
+// Sortable is implemented by list rows so the next-page cursor is built
+// from the value the query sorted on, whatever the row's JSON looks like.
+type Sortable interface {
+	CursorID() string
+	SortValue(field string) interface{}
+}
+
+func TrimPage[T Sortable](p ListParams, rows []T) ([]T, string) {
+	if len(rows) <= p.Limit {
+		return rows, ""
+	}
+	
+	rows = rows[:p.Limit]
+	last := rows[len(rows)-1]
+	
+	return rows, encodeCursor(listCursor{
+		Sort:  p.SortBy,
+		Order: p.SortOrder,
+		Value: last.SortValue(p.SortBy),
+		ID:    last.CursorID(),
+	})
+}
+
+func encodeCursor(cursor listCursor) string {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string) (*listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	
+	var cursor listCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	
+	if cursor.ID == "" || cursor.Value == nil {
+		return nil, ErrInvalidCursor
+	}
+	
+	return &cursor, nil
+}
@@ -0,0 +1,41 @@
+package utils
+
+import "testing"
+
+type row struct {
+	id    string
+	title string
+}
+
+func (r row) CursorID() string {
+	return r.id
+}
+
+func (r row) SortValue(field string) interface{} {
+	return r.title
+}
+
+func TestTrimPageCursor(t *testing.T) {
+	params := ListParams{Limit: 2, SortBy: "title", SortOrder: "asc"}
+	rows := []row{{id: "a", title: "823"}, {id: "b", title: ""}, {id: "c", title: ""}}
+	
+	page, token := TrimPage(params, rows)
+	if len(page) != 2 {
+		t.Fatalf("page has %d rows, want 2", len(page))
+	}
+	if token == "" {
+		t.Fatal("expected a next cursor")
+	}
+	
+	cursor, err := decodeCursor(token)
+	if err != nil {
+		t.Fatalf("cursor for an empty sort value did not decode: %v", err)
+	}
+	if cursor.ID != "b" || cursor.Value != "" || cursor.Sort != "title" || cursor.Order != "asc" {
+		t.Errorf("cursor = %+v", cursor)
+	}
+	
+	if _, token := TrimPage(params, rows[:2]); token != "" {
+		t.Errorf("last page returned cursor %q", token)
+	}
+}
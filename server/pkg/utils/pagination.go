@@ -7,10 +7,14 @@ import (
 )
 
 type Pagination struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"total_pages"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	SortBy     string `json:"sort_by,omitempty"`
+	SortOrder  string `json:"sort_order,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
 }
 
 func GetPaginationParams(c *gin.Context, defaultLimit, maxLimit int) (page, limit, offset int) {
@@ -41,5 +45,6 @@ func CalculatePagination(page, limit int, total int64) Pagination {
 		Limit:      limit,
 		Total:      total,
 		TotalPages: totalPages,
+		HasMore:    page > 0 && page < totalPages,
 	}
 }
\ No newline at end of file